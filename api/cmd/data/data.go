@@ -0,0 +1,16 @@
+// Package data embeds the SQL scripts that create and populate the database, so the
+// tools that run them do not depend on the working directory.
+package data
+
+import "embed"
+
+// Migrations holds the schema migrations, named <version>_<name>.up.sql and
+// <version>_<name>.down.sql, applied in version order.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
+
+// Seeds holds the scripts inserting sample data. They may be run repeatedly.
+//
+//go:embed seed/*.sql
+var Seeds embed.FS
@@ -3,6 +3,3 @@ DROP TABLE IF EXISTS relationships;
 
 -- Drop Users Table
 DROP TABLE IF EXISTS users;
-
--- Drop Database
-DROP DATABASE IF EXISTS friends_db;
@@ -1,7 +1,5 @@
-CREATE DATABASE friends_db;
-
 -- Create Users Table
-CREATE TABLE users (
+CREATE TABLE IF NOT EXISTS users (
     id UUID PRIMARY KEY,
     email VARCHAR(255) UNIQUE NOT NULL,
     created_at TIMESTAMP NOT NULL,
@@ -9,7 +7,7 @@ CREATE TABLE users (
 );
 
 -- Create Relationships Table
-CREATE TABLE relationships (
+CREATE TABLE IF NOT EXISTS relationships (
     id UUID PRIMARY KEY,
     requestor_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
     target_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
@@ -18,5 +16,3 @@ CREATE TABLE relationships (
     updated_at TIMESTAMP NOT NULL,
     CONSTRAINT no_self_friendship CHECK (requestor_id != target_id)
 );
-
-
@@ -0,0 +1,2 @@
+-- Drop Relationship Events Table
+DROP TABLE IF EXISTS relationship_events;
@@ -0,0 +1,15 @@
+-- Create Relationship Events Table (transactional outbox)
+CREATE TABLE IF NOT EXISTS relationship_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS relationship_events_pending_idx
+    ON relationship_events (next_attempt_at)
+    WHERE published_at IS NULL;
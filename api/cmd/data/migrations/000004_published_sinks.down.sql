@@ -0,0 +1,3 @@
+-- Drop the sinks recorded per event
+ALTER TABLE relationship_events
+    DROP COLUMN IF EXISTS published_sinks;
@@ -0,0 +1,3 @@
+-- Record which sinks accepted an event, so a retry skips them
+ALTER TABLE relationship_events
+    ADD COLUMN IF NOT EXISTS published_sinks JSONB NOT NULL DEFAULT '[]';
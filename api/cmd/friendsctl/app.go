@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	graphCtrl "github.com/koeylp/friends-management/cmd/internal/controller/graph"
+	relationshipCtrl "github.com/koeylp/friends-management/cmd/internal/controller/relationship"
+	userCtrl "github.com/koeylp/friends-management/cmd/internal/controller/user"
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	graphRepo "github.com/koeylp/friends-management/cmd/internal/repository/graph"
+	outboxRepo "github.com/koeylp/friends-management/cmd/internal/repository/outbox"
+	relationshipRepo "github.com/koeylp/friends-management/cmd/internal/repository/relationship"
+	userRepo "github.com/koeylp/friends-management/cmd/internal/repository/user"
+)
+
+// app runs friendsctl commands against the controllers and repositories of the API.
+type app struct {
+	db               *sql.DB
+	userCtrl         userCtrl.UserController
+	relationshipCtrl relationshipCtrl.RelationshipController
+	graphCtrl        graphCtrl.GraphController
+	graphRepo        graphRepo.GraphRepository
+}
+
+// newApp wires the controllers and repositories to db. Metrics are collected but, as the
+// CLI runs one command and exits, never exposed.
+func newApp(db *sql.DB) *app {
+	users := userRepo.NewUserRepository(db)
+	graph := graphRepo.NewGraphRepository(db)
+	return &app{
+		db:               db,
+		userCtrl:         userCtrl.NewUserController(users),
+		relationshipCtrl: relationshipCtrl.NewRelationshipController(relationshipRepo.NewRelationshipRepository(db), users, outboxRepo.NewOutboxRepository(db), metrics.New()),
+		graphCtrl:        graphCtrl.NewGraphController(graph, users),
+		graphRepo:        graph,
+	}
+}
+
+// command runs one friendsctl command with the arguments following its name.
+type command func(a *app, ctx context.Context, p *printer, args []string) error
+
+var commands = map[string]command{
+	"user":         (*app).user,
+	"friends":      (*app).friends,
+	"relationship": (*app).relationship,
+	"migrate":      (*app).migrate,
+	"seed":         (*app).seed,
+	"export":       (*app).export,
+}
+
+// run parses the global flags and runs the command named by args, writing its result to stdout.
+func (a *app) run(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("friendsctl", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	output := flags.String("output", formatTable, "output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprint(stdout, usage)
+			return nil
+		}
+		return usagef("%v", err)
+	}
+	if *output != formatTable && *output != formatJSON {
+		return usagef("unknown output format %q", *output)
+	}
+	if flags.NArg() == 0 {
+		return usagef("no command given")
+	}
+
+	name := flags.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		return usagef("unknown command %q", name)
+	}
+	return cmd(a, ctx, &printer{w: stdout, json: *output == formatJSON}, flags.Args()[1:])
+}
+
+// subcommand returns the subcommand in args and the arguments following it, checking it is one of names.
+func subcommand(command string, args []string, names ...string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, usagef("%s needs a subcommand: %s", command, strings.Join(names, ", "))
+	}
+	for _, name := range names {
+		if args[0] == name {
+			return name, args[1:], nil
+		}
+	}
+	return "", nil, usagef("unknown %s subcommand %q", command, args[0])
+}
+
+// checkArgs reports a usage error unless args holds exactly the named arguments.
+func checkArgs(command string, args []string, names ...string) error {
+	if len(args) != len(names) {
+		return usagef("usage: friendsctl %s %s", command, strings.Join(names, " "))
+	}
+	return nil
+}
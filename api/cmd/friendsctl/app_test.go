@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	graphCtrl "github.com/koeylp/friends-management/cmd/internal/controller/graph"
+	relationshipCtrl "github.com/koeylp/friends-management/cmd/internal/controller/relationship"
+	userCtrl "github.com/koeylp/friends-management/cmd/internal/controller/user"
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	relationshipRepo "github.com/koeylp/friends-management/cmd/internal/repository/relationship"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testApp wires the commands to mock repositories, with andy and john as existing users.
+func testApp() (*app, *relationshipCtrl.MockRelationshipRepository, *graphCtrl.MockGraphRepository) {
+	relationships := new(relationshipCtrl.MockRelationshipRepository)
+	users := new(relationshipCtrl.MockUserRepository)
+	graphs := new(graphCtrl.MockGraphRepository)
+	outbox := new(relationshipCtrl.MockOutboxRepository)
+
+	andy, john := &user.User{ID: "1", Email: "andy@example.com"}, &user.User{ID: "2", Email: "john@example.com"}
+	users.On("GetUserByEmail", mock.Anything, "andy@example.com").Return(andy, nil)
+	users.On("GetUserByEmail", mock.Anything, "john@example.com").Return(john, nil)
+	users.On("GetUserByEmail", mock.Anything, mock.Anything).Return(nil, domain.Errorf(domain.ErrUserNotFound, "user not found"))
+	users.On("GetUsersByEmails", mock.Anything, []string{"andy@example.com", "john@example.com"}).Return([]*user.User{andy, john}, []string{}, nil)
+	users.On("GetUsersByEmails", mock.Anything, []string{"john@example.com", "andy@example.com"}).Return([]*user.User{john, andy}, []string{}, nil)
+	outbox.On("Append", mock.Anything, event.UpdatePosted, mock.Anything).Return(nil)
+
+	return &app{
+		userCtrl:         userCtrl.NewUserController(users),
+		relationshipCtrl: relationshipCtrl.NewRelationshipController(relationships, users, outbox, metrics.New()),
+		graphCtrl:        graphCtrl.NewGraphController(graphs, users),
+		graphRepo:        graphs,
+	}, relationships, graphs
+}
+
+// Test that command lines that cannot be understood are reported as usage errors.
+func TestRun_UsageErrors(t *testing.T) {
+	a, _, _ := testApp()
+
+	for _, args := range [][]string{
+		{},
+		{"frobnicate"},
+		{"-output", "yaml", "friends", "andy@example.com"},
+		{"user"},
+		{"user", "show"},
+		{"relationship", "add", "friend", "andy@example.com"},
+		{"relationship", "add", "follow", "andy@example.com", "john@example.com"},
+		{"migrate", "sideways"},
+		{"migrate", "down", "-steps", "-1"},
+	} {
+		err := a.run(context.Background(), args, new(bytes.Buffer))
+
+		var usageErr *usageError
+		assert.ErrorAs(t, err, &usageErr, "args %q", args)
+	}
+}
+
+// Test that -h prints the usage.
+func TestRun_Help(t *testing.T) {
+	a, _, _ := testApp()
+	var out bytes.Buffer
+
+	err := a.run(context.Background(), []string{"-h"}, &out)
+
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "friendsctl [-output table|json] <command> [arguments]")
+}
+
+// Test listing friends as a table and as JSON.
+func TestRun_Friends(t *testing.T) {
+	a, relationships, _ := testApp()
+	relationships.On("GetFriends", mock.Anything, "andy@example.com").Return([]string{"john@example.com", "kate@example.com"}, nil)
+
+	var out bytes.Buffer
+	err := a.run(context.Background(), []string{"friends", "andy@example.com"}, &out)
+	require.NoError(t, err)
+	assert.Equal(t, "FRIEND\njohn@example.com\nkate@example.com\n", out.String())
+
+	out.Reset()
+	err = a.run(context.Background(), []string{"-output", "json", "friends", "andy@example.com"}, &out)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"friends": ["john@example.com", "kate@example.com"], "count": 2}`, out.String())
+
+	err = a.run(context.Background(), []string{"friends", "not-an-email"}, &out)
+	assert.EqualError(t, err, "invalid arguments: email must be a valid email address")
+}
+
+// Test that relationships are added and removed through the controller.
+func TestRun_Relationship(t *testing.T) {
+	a, relationships, _ := testApp()
+	relationships.On("CheckSubscriptionExists", mock.Anything, "1", "2").Return(false, nil)
+	relationships.On("Subscribe", mock.Anything, "1", "2").Return(nil)
+	relationships.On("DeleteFriend", mock.Anything, "2", "1").Return(domain.Errorf(domain.ErrNotFriends, "friendship not found"))
+
+	var out bytes.Buffer
+	err := a.run(context.Background(), []string{"relationship", "add", "subscription", "andy@example.com", "john@example.com"}, &out)
+	require.NoError(t, err)
+	assert.Equal(t, "Added subscription from andy@example.com to john@example.com\n", out.String())
+
+	err = a.run(context.Background(), []string{"relationship", "remove", "friend", "john@example.com", "andy@example.com"}, &out)
+	assert.ErrorIs(t, err, domain.ErrNotFriends)
+	assert.EqualError(t, err, "john@example.com and andy@example.com are not friends")
+
+	err = a.run(context.Background(), []string{"relationship", "add", "block", "andy@example.com", "andy@example.com"}, &out)
+	assert.EqualError(t, err, "invalid arguments: target cannot be equal to requestor")
+
+	relationships.AssertExpectations(t)
+}
+
+// Test that the summary sorts the relationships of the user by direction and skips the others.
+func TestRun_UserShow(t *testing.T) {
+	a, _, graphs := testApp()
+	since := time.Date(2024, 10, 28, 2, 12, 36, 0, time.UTC)
+	graphs.On("StreamEdges", mock.Anything, &graph.Filter{UserID: "1", Depth: 1}).Return([]*graph.Edge{
+		{Source: "john@example.com", Target: "andy@example.com", RelationshipType: relationshipRepo.FRIEND, CreatedAt: since},
+		{Source: "andy@example.com", Target: "kate@example.com", RelationshipType: relationshipRepo.SUBSCRIBE, CreatedAt: since},
+		{Source: "lisa@example.com", Target: "andy@example.com", RelationshipType: relationshipRepo.BLOCK, CreatedAt: since},
+		{Source: "john@example.com", Target: "kate@example.com", RelationshipType: relationshipRepo.FRIEND, CreatedAt: since},
+	}, nil)
+
+	var out bytes.Buffer
+	err := a.run(context.Background(), []string{"user", "show", "andy@example.com"}, &out)
+	require.NoError(t, err)
+	assert.Equal(t, ""+
+		"RELATIONSHIP   EMAIL             SINCE\n"+
+		"friend         john@example.com  2024-10-28 02:12:36\n"+
+		"subscribed to  kate@example.com  2024-10-28 02:12:36\n"+
+		"blocked by     lisa@example.com  2024-10-28 02:12:36\n", out.String())
+
+	out.Reset()
+	err = a.run(context.Background(), []string{"-output", "json", "user", "show", "andy@example.com"}, &out)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"email": "andy@example.com",
+		"friends": ["john@example.com"],
+		"subscriptions": ["kate@example.com"],
+		"subscribers": [],
+		"blocking": [],
+		"blocked_by": ["lisa@example.com"]
+	}`, out.String())
+
+	err = a.run(context.Background(), []string{"user", "show", "ghost@example.com"}, &out)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
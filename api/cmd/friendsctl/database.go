@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/koeylp/friends-management/cmd/data"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/migrate"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+)
+
+// migrate runs "migrate up", "migrate down [-steps N]" and "migrate status".
+func (a *app) migrate(ctx context.Context, p *printer, args []string) error {
+	name, args, err := subcommand("migrate", args, "up", "down", "status")
+	if err != nil {
+		return err
+	}
+	flags := flag.NewFlagSet("migrate "+name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	steps := 0
+	if name == "down" {
+		flags.IntVar(&steps, "steps", 1, "number of migrations to revert")
+	}
+	if err := flags.Parse(args); err != nil {
+		return usagef("%v", err)
+	}
+	if flags.NArg() > 0 || steps < 0 {
+		return usagef("usage: friendsctl migrate up | down [-steps N] | status")
+	}
+
+	migrations, err := fs.Sub(data.Migrations, "migrations")
+	if err != nil {
+		return err
+	}
+	migrator, err := migrate.NewMigrator(a.db, migrations)
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "up":
+		versions, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		return p.message("Applied %d migration(s)%s", len(versions), listed(versions))
+	case "down":
+		versions, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		return p.message("Reverted %d migration(s)%s", len(versions), listed(versions))
+	default:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, len(statuses))
+		for i, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			rows[i] = []string{status.Version, appliedAt}
+		}
+		return p.table(statuses, []string{"VERSION", "APPLIED"}, rows)
+	}
+}
+
+// seed runs "seed".
+func (a *app) seed(ctx context.Context, p *printer, args []string) error {
+	if err := checkArgs("seed", args); err != nil {
+		return err
+	}
+	seeds, err := fs.Sub(data.Seeds, "seed")
+	if err != nil {
+		return err
+	}
+	names, err := migrate.Seed(ctx, a.db, seeds)
+	if err != nil {
+		return err
+	}
+	return p.message("Ran %d seed script(s)%s", len(names), listed(names))
+}
+
+// export runs "export", streaming the social graph to stdout or a file.
+// Its output is the export itself, so the global output format does not apply.
+func (a *app) export(ctx context.Context, p *printer, args []string) error {
+	var exportReq graph.ExportRequest
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&exportReq.Format, "format", graph.FormatNDJSON, "output format: ndjson, csv, graphml or dot")
+	flags.StringVar(&exportReq.Email, "email", "", "only export the ego network of this user")
+	flags.IntVar(&exportReq.Depth, "depth", graph.DefaultDepth, "ego network depth, used with -email")
+	output := flags.String("o", "", "output file (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return usagef("%v", err)
+	}
+	if flags.NArg() > 0 {
+		return usagef("usage: friendsctl export [-format F] [-email E] [-depth N] [-o FILE]")
+	}
+
+	if exportReq.Email == "" {
+		exportReq.Depth = 0
+	}
+	if err := graph.ValidateExportRequest(&exportReq); err != nil {
+		return invalidArguments(err)
+	}
+
+	out := p.w
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	return a.graphCtrl.Export(ctx, &exportReq, out)
+}
+
+// listed formats names as a list following a message, or nothing when there are none.
+func listed(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(names, ", ")
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/postgres"
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+)
+
+const usage = `friendsctl inspects and repairs the friends database.
+
+Usage:
+
+	friendsctl [-output table|json] <command> [arguments]
+
+Commands:
+
+	user create EMAIL...                        create users
+	user show EMAIL                             show every relationship of a user
+	friends EMAIL                               list the friends of a user
+	relationship add TYPE REQUESTOR TARGET      add a friend, subscription or block
+	relationship remove TYPE REQUESTOR TARGET   remove a friend, subscription or block
+	migrate up|down|status                      apply, revert or list schema migrations
+	seed                                        insert the sample data
+	export [-format F] [-email E] [-depth N] [-o FILE]
+	                                            export the social graph
+
+TYPE is one of friend, subscription or block. For a friendship the order of the two
+users does not matter.
+`
+
+// friendsctl is the admin command line tool. It uses the same controllers and repositories
+// as the API, so every change it makes is validated, recorded as an event and logged the same way.
+//
+// Usage:
+//
+//	go run ./cmd/friendsctl user show john@example.com
+func main() {
+	slog.SetDefault(logging.NewLogger(config.GetLogConfig()))
+
+	db, err := postgres.Open(context.Background(), config.GetDBConfig())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "friendsctl: database connection failed: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	err = newApp(db).run(context.Background(), os.Args[1:], os.Stdout)
+	var usageErr *usageError
+	switch {
+	case err == nil:
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(os.Stderr, "friendsctl: %v\nRun 'friendsctl -h' for usage.\n", err)
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "friendsctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// usageError reports a command line that could not be understood.
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
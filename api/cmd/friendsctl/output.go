@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	validation "github.com/koeylp/friends-management/cmd/internal/pkg/validation_util"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// printer writes command results as an aligned table for people or as JSON for scripts.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+// table prints v as JSON, or as a table with the given header and rows.
+func (p *printer) table(v interface{}, header []string, rows [][]string) error {
+	if p.json {
+		return p.printJSON(v)
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// message prints the outcome of a command that changed something.
+func (p *printer) message(format string, args ...interface{}) error {
+	message := fmt.Sprintf(format, args...)
+	if p.json {
+		return p.printJSON(struct {
+			Message string `json:"message"`
+		}{message})
+	}
+	_, err := fmt.Fprintln(p.w, message)
+	return err
+}
+
+func (p *printer) printJSON(v interface{}) error {
+	encoder := json.NewEncoder(p.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// invalidArguments describes the arguments that failed validation, one per field.
+func invalidArguments(err error) error {
+	fieldErrs := validation.FieldErrors(err)
+	if len(fieldErrs) == 0 {
+		return err
+	}
+	messages := make([]string, len(fieldErrs))
+	for i, fieldErr := range fieldErrs {
+		messages[i] = fieldErr.Message
+	}
+	return errors.New("invalid arguments: " + strings.Join(messages, "; "))
+}
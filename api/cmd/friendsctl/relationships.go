@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/block"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/subscription"
+)
+
+// relationship runs "relationship add|remove TYPE REQUESTOR TARGET".
+func (a *app) relationship(ctx context.Context, p *printer, args []string) error {
+	action, args, err := subcommand("relationship", args, "add", "remove")
+	if err != nil {
+		return err
+	}
+	if err := checkArgs("relationship "+action, args, "TYPE", "REQUESTOR", "TARGET"); err != nil {
+		return err
+	}
+	kind, requestor, target := args[0], args[1], args[2]
+	add := action == "add"
+
+	switch kind {
+	case "friend":
+		friendReq := &friend.CreateFriend{Friends: []string{requestor, target}}
+		if err := friend.ValidateCreateFriendRequest(friendReq); err != nil {
+			return invalidArguments(err)
+		}
+		if add {
+			err = a.relationshipCtrl.CreateFriend(ctx, friendReq)
+		} else {
+			err = a.relationshipCtrl.Unfriend(ctx, friendReq)
+		}
+	case "subscription":
+		subscribeReq := &subscription.SubscribeRequest{Requestor: requestor, Target: target}
+		if err := subscription.ValidateSubscribeRequest(subscribeReq); err != nil {
+			return invalidArguments(err)
+		}
+		if add {
+			err = a.relationshipCtrl.Subscribe(ctx, subscribeReq)
+		} else {
+			err = a.relationshipCtrl.Unsubscribe(ctx, subscribeReq)
+		}
+	case "block":
+		blockReq := &block.BlockRequest{Requestor: requestor, Target: target}
+		if err := block.ValidateBlockRequest(blockReq); err != nil {
+			return invalidArguments(err)
+		}
+		if add {
+			err = a.relationshipCtrl.BlockUpdates(ctx, blockReq)
+		} else {
+			err = a.relationshipCtrl.UnblockUpdates(ctx, blockReq)
+		}
+	default:
+		return usagef("unknown relationship type %q: use friend, subscription or block", kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	if add {
+		return p.message("Added %s from %s to %s", kind, requestor, target)
+	}
+	return p.message("Removed %s from %s to %s", kind, requestor, target)
+}
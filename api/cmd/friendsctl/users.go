@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	relationshipRepo "github.com/koeylp/friends-management/cmd/internal/repository/relationship"
+)
+
+// summary lists every relationship a user takes part in, by direction.
+type summary struct {
+	Email         string   `json:"email"`
+	Friends       []string `json:"friends"`
+	Subscriptions []string `json:"subscriptions"`
+	Subscribers   []string `json:"subscribers"`
+	Blocking      []string `json:"blocking"`
+	BlockedBy     []string `json:"blocked_by"`
+}
+
+// user runs "user create EMAIL..." and "user show EMAIL".
+func (a *app) user(ctx context.Context, p *printer, args []string) error {
+	name, args, err := subcommand("user", args, "create", "show")
+	if err != nil {
+		return err
+	}
+	if name == "create" {
+		return a.createUsers(ctx, p, args)
+	}
+	if err := checkArgs("user show", args, "EMAIL"); err != nil {
+		return err
+	}
+	return a.showUser(ctx, p, args[0])
+}
+
+// createUsers creates a user for every email, stopping at the first that fails.
+func (a *app) createUsers(ctx context.Context, p *printer, emails []string) error {
+	if len(emails) == 0 {
+		return usagef("usage: friendsctl user create EMAIL...")
+	}
+	for _, email := range emails {
+		createUserReq := &user.CreateUser{Email: email}
+		if err := user.ValidateCreateUserRequest(createUserReq); err != nil {
+			return invalidArguments(err)
+		}
+		if err := a.userCtrl.CreateUser(ctx, createUserReq); err != nil {
+			return err
+		}
+	}
+	return p.message("Created %d user(s)", len(emails))
+}
+
+// showUser prints the relationships of a user, read from the user's ego network.
+func (a *app) showUser(ctx context.Context, p *printer, email string) error {
+	found, err := a.userCtrl.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	s := &summary{
+		Email:         found.Email,
+		Friends:       []string{},
+		Subscriptions: []string{},
+		Subscribers:   []string{},
+		Blocking:      []string{},
+		BlockedBy:     []string{},
+	}
+	var rows [][]string
+	err = a.graphRepo.StreamEdges(ctx, &graph.Filter{UserID: found.ID, Depth: 1}, func(edge *graph.Edge) error {
+		// The ego network also holds the relationships between the user's neighbours.
+		outgoing := edge.Source == found.Email
+		if !outgoing && edge.Target != found.Email {
+			return nil
+		}
+		other := edge.Target
+		if !outgoing {
+			other = edge.Source
+		}
+
+		var kind string
+		switch {
+		case edge.RelationshipType == relationshipRepo.FRIEND:
+			kind = "friend"
+			s.Friends = append(s.Friends, other)
+		case edge.RelationshipType == relationshipRepo.SUBSCRIBE && outgoing:
+			kind = "subscribed to"
+			s.Subscriptions = append(s.Subscriptions, other)
+		case edge.RelationshipType == relationshipRepo.SUBSCRIBE:
+			kind = "subscriber"
+			s.Subscribers = append(s.Subscribers, other)
+		case edge.RelationshipType == relationshipRepo.BLOCK && outgoing:
+			kind = "blocking"
+			s.Blocking = append(s.Blocking, other)
+		case edge.RelationshipType == relationshipRepo.BLOCK:
+			kind = "blocked by"
+			s.BlockedBy = append(s.BlockedBy, other)
+		default:
+			return nil
+		}
+		rows = append(rows, []string{kind, other, edge.CreatedAt.Format("2006-01-02 15:04:05")})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.table(s, []string{"RELATIONSHIP", "EMAIL", "SINCE"}, rows)
+}
+
+// friends runs "friends EMAIL".
+func (a *app) friends(ctx context.Context, p *printer, args []string) error {
+	if err := checkArgs("friends", args, "EMAIL"); err != nil {
+		return err
+	}
+	emailReq := &friend.EmailRequest{Email: args[0]}
+	if err := friend.ValidateEmailRequest(emailReq); err != nil {
+		return invalidArguments(err)
+	}
+
+	friends, err := a.relationshipCtrl.GetFriendListByEmail(ctx, emailReq.Email)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(friends))
+	for i, email := range friends {
+		rows[i] = []string{email}
+	}
+	return p.table(&friend.FriendList{Friends: friends, Count: len(friends)}, []string{"FRIEND"}, rows)
+}
@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+	utils "github.com/koeylp/friends-management/cmd/internal/pkg/export_util"
+	graphRepo "github.com/koeylp/friends-management/cmd/internal/repository/graph"
+	userRepo "github.com/koeylp/friends-management/cmd/internal/repository/user"
+)
+
+// GraphController defines the interface for social graph export operations.
+type GraphController interface {
+	Export(ctx context.Context, exportReq *graph.ExportRequest, w io.Writer) error
+}
+
+// graphControllerImpl implements the GraphController interface.
+type graphControllerImpl struct {
+	graphRepo graphRepo.GraphRepository
+	userRepo  userRepo.UserRepository
+}
+
+// NewGraphController creates a new instance of GraphController with the provided repositories.
+func NewGraphController(graphRepo graphRepo.GraphRepository, userRepo userRepo.UserRepository) GraphController {
+	return &graphControllerImpl{graphRepo: graphRepo, userRepo: userRepo}
+}
+
+// Export streams users and relationships to w in the requested format.
+// When an email is given only its ego network, up to the requested depth, is exported.
+// Errors returned before anything is written can still be reported to the caller as a normal response.
+func (s *graphControllerImpl) Export(ctx context.Context, exportReq *graph.ExportRequest, w io.Writer) error {
+	var filter *graph.Filter
+	if exportReq.Email != "" {
+		foundUser, err := s.userRepo.GetUserByEmail(ctx, exportReq.Email)
+		if err != nil {
+			return err
+		}
+		depth := exportReq.Depth
+		if depth == 0 {
+			depth = graph.DefaultDepth
+		}
+		filter = &graph.Filter{UserID: foundUser.ID, Depth: depth}
+	}
+
+	encoder, err := utils.NewGraphEncoder(exportReq.Format, w)
+	if err != nil {
+		return domain.Errorf(domain.ErrInvalidArgument, "%s", err.Error())
+	}
+
+	if err := encoder.Begin(); err != nil {
+		return fmt.Errorf("failed to write export header: %w", err)
+	}
+	if err := s.graphRepo.StreamNodes(ctx, filter, encoder.WriteNode); err != nil {
+		return fmt.Errorf("failed to export nodes: %w", err)
+	}
+	if err := s.graphRepo.StreamEdges(ctx, filter, encoder.WriteEdge); err != nil {
+		return fmt.Errorf("failed to export edges: %w", err)
+	}
+	return encoder.End()
+}
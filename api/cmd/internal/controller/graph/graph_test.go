@@ -0,0 +1,141 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	exportTime = time.Date(2024, 10, 24, 10, 0, 0, 0, time.UTC)
+	mockNodes  = []*graph.Node{
+		{ID: "1", Email: "alice@example.com", CreatedAt: exportTime},
+		{ID: "2", Email: "bob@example.com", CreatedAt: exportTime},
+	}
+	mockEdges = []*graph.Edge{
+		{ID: "r1", Source: "alice@example.com", Target: "bob@example.com", RelationshipType: "Friend", CreatedAt: exportTime},
+	}
+)
+
+// Tests that every supported format streams nodes followed by edges.
+func TestExport_Formats(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		format   string
+		expected []string
+	}{
+		{
+			format: graph.FormatNDJSON,
+			expected: []string{
+				`{"kind":"node","id":"1","email":"alice@example.com","created_at":"2024-10-24T10:00:00Z"}`,
+				`{"kind":"edge","id":"r1","source":"alice@example.com","target":"bob@example.com","relationship_type":"Friend","created_at":"2024-10-24T10:00:00Z"}`,
+			},
+		},
+		{
+			format: graph.FormatCSV,
+			expected: []string{
+				"kind,id,email,source,target,relationship_type,created_at",
+				"node,2,bob@example.com,,,,2024-10-24T10:00:00Z",
+				"edge,r1,,alice@example.com,bob@example.com,Friend,2024-10-24T10:00:00Z",
+			},
+		},
+		{
+			format: graph.FormatGraphML,
+			expected: []string{
+				`<graph id="friends" edgedefault="directed">`,
+				`<node id="alice@example.com">`,
+				`<edge id="r1" source="alice@example.com" target="bob@example.com">`,
+				`</graphml>`,
+			},
+		},
+		{
+			format: graph.FormatDOT,
+			expected: []string{
+				"digraph friends {",
+				`"bob@example.com";`,
+				`"alice@example.com" -> "bob@example.com" [label="Friend"];`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			mockGraphRepo := new(MockGraphRepository)
+			mockUserRepo := new(MockUserRepository)
+			ctrl := NewGraphController(mockGraphRepo, mockUserRepo)
+
+			var filter *graph.Filter
+			mockGraphRepo.On("StreamNodes", ctx, filter).Return(mockNodes, nil)
+			mockGraphRepo.On("StreamEdges", ctx, filter).Return(mockEdges, nil)
+
+			var buf bytes.Buffer
+			err := ctrl.Export(ctx, &graph.ExportRequest{Format: tt.format}, &buf)
+			assert.NoError(t, err)
+
+			for _, line := range tt.expected {
+				assert.Contains(t, buf.String(), line)
+			}
+			mockGraphRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// Tests that an email restricts the export to the user's ego network with the default depth.
+func TestExport_EgoNetwork(t *testing.T) {
+	ctx := context.Background()
+	mockGraphRepo := new(MockGraphRepository)
+	mockUserRepo := new(MockUserRepository)
+	ctrl := NewGraphController(mockGraphRepo, mockUserRepo)
+
+	filter := &graph.Filter{UserID: "1", Depth: graph.DefaultDepth}
+	mockUserRepo.On("GetUserByEmail", ctx, "alice@example.com").Return(&user.User{ID: "1", Email: "alice@example.com"}, nil)
+	mockGraphRepo.On("StreamNodes", ctx, filter).Return(mockNodes, nil)
+	mockGraphRepo.On("StreamEdges", ctx, filter).Return(mockEdges, nil)
+
+	var buf bytes.Buffer
+	err := ctrl.Export(ctx, &graph.ExportRequest{Format: graph.FormatNDJSON, Email: "alice@example.com"}, &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(buf.String(), "\n"))
+	mockGraphRepo.AssertExpectations(t)
+}
+
+// Tests that nothing is written when the ego user does not exist.
+func TestExport_UserNotFound(t *testing.T) {
+	ctx := context.Background()
+	mockGraphRepo := new(MockGraphRepository)
+	mockUserRepo := new(MockUserRepository)
+	ctrl := NewGraphController(mockGraphRepo, mockUserRepo)
+
+	mockUserRepo.On("GetUserByEmail", ctx, "ghost@example.com").Return(nil, domain.Errorf(domain.ErrUserNotFound, "user not found with email ghost@example.com"))
+
+	var buf bytes.Buffer
+	err := ctrl.Export(ctx, &graph.ExportRequest{Format: graph.FormatDOT, Email: "ghost@example.com", Depth: 2}, &buf)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	assert.Empty(t, buf.String())
+	mockGraphRepo.AssertNotCalled(t, "StreamNodes")
+}
+
+// Tests that a repository failure while streaming is reported.
+func TestExport_StreamError(t *testing.T) {
+	ctx := context.Background()
+	mockGraphRepo := new(MockGraphRepository)
+	mockUserRepo := new(MockUserRepository)
+	ctrl := NewGraphController(mockGraphRepo, mockUserRepo)
+
+	var filter *graph.Filter
+	mockGraphRepo.On("StreamNodes", ctx, filter).Return(mockNodes, nil)
+	mockGraphRepo.On("StreamEdges", ctx, filter).Return([]*graph.Edge{}, errors.New("database error"))
+
+	var buf bytes.Buffer
+	err := ctrl.Export(ctx, &graph.ExportRequest{Format: graph.FormatCSV}, &buf)
+	assert.EqualError(t, err, "failed to export edges: database error")
+}
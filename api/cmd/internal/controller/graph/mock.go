@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockGraphRepository is a mock implementation of a graph repository for testing purposes.
+// The nodes and edges registered on a call are replayed through the streaming callback.
+type MockGraphRepository struct {
+	mock.Mock
+}
+
+// StreamNodes mocks streaming the users of the graph.
+func (m *MockGraphRepository) StreamNodes(ctx context.Context, filter *graph.Filter, fn func(*graph.Node) error) error {
+	args := m.Called(ctx, filter)
+	for _, node := range args.Get(0).([]*graph.Node) {
+		if err := fn(node); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+// StreamEdges mocks streaming the relationships of the graph.
+func (m *MockGraphRepository) StreamEdges(ctx context.Context, filter *graph.Filter, fn func(*graph.Edge) error) error {
+	args := m.Called(ctx, filter)
+	for _, edge := range args.Get(0).([]*graph.Edge) {
+		if err := fn(edge); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+// MockUserRepository is a mock implementation of a user repository for testing purposes.
+type MockUserRepository struct {
+	mock.Mock
+}
+
+// GetUserByEmail mocks the retrieval of a user by email.
+func (m *MockUserRepository) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	args := m.Called(ctx, email)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+// GetUsersByEmails mocks the retrieval of several users by email.
+func (m *MockUserRepository) GetUsersByEmails(ctx context.Context, emails []string) ([]*user.User, []string, error) {
+	args := m.Called(ctx, emails)
+	if args.Error(2) != nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*user.User), args.Get(1).([]string), args.Error(2)
+}
+
+// CreateUser mocks the creation of a user.
+func (m *MockUserRepository) CreateUser(ctx context.Context, u *user.CreateUser) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
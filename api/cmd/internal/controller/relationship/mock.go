@@ -3,7 +3,9 @@ package relationship
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
 	"github.com/stretchr/testify/mock"
 )
@@ -31,7 +33,13 @@ func (m *MockRelationshipRepository) CheckBlockExists(ctx context.Context, reque
 	return args.Bool(0), args.Error(1)
 }
 
-// CheckSubscriptionExists mocks the check for whether a subscription exists between two users.
+// CheckBlocking mocks the check for whether the requestor blocks the target.
+func (m *MockRelationshipRepository) CheckBlocking(ctx context.Context, requestor_id string, target_id string) (bool, error) {
+	args := m.Called(ctx, requestor_id, target_id)
+	return args.Bool(0), args.Error(1)
+}
+
+// CheckSubscriptionExists mocks the check for whether the requestor is subscribed to the target.
 func (m *MockRelationshipRepository) CheckSubscriptionExists(ctx context.Context, requestor_id string, target_id string) (bool, error) {
 	args := m.Called(ctx, requestor_id, target_id)
 	return args.Bool(0), args.Error(1)
@@ -43,6 +51,18 @@ func (m *MockRelationshipRepository) Subscribe(ctx context.Context, requestor_id
 	return args.Error(0)
 }
 
+// Unsubscribe mocks the removal of a requestor's subscription to a target user.
+func (m *MockRelationshipRepository) Unsubscribe(ctx context.Context, requestor_id string, target_id string) error {
+	args := m.Called(ctx, requestor_id, target_id)
+	return args.Error(0)
+}
+
+// UnblockUpdates mocks the removal of a requestor's block on a target user.
+func (m *MockRelationshipRepository) UnblockUpdates(ctx context.Context, requestor_id string, target_id string) error {
+	args := m.Called(ctx, requestor_id, target_id)
+	return args.Error(0)
+}
+
 // GetFriends mocks the retrieval of a list of friends for a given email address.
 func (m *MockRelationshipRepository) GetFriends(ctx context.Context, email string) ([]string, error) {
 	args := m.Called(ctx, email)
@@ -67,6 +87,35 @@ func (m *MockRelationshipRepository) GetCommonFriends(ctx context.Context, users
 	return args.Get(0).([]string), args.Error(1)
 }
 
+// MockOutboxRepository is a mock implementation of an outbox repository for testing purposes.
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+// Append mocks recording a standalone event.
+func (m *MockOutboxRepository) Append(ctx context.Context, eventType string, data interface{}) error {
+	args := m.Called(ctx, eventType, data)
+	return args.Error(0)
+}
+
+// ClaimPending mocks leasing a batch of due events.
+func (m *MockOutboxRepository) ClaimPending(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]*event.Event, error) {
+	args := m.Called(ctx, limit, maxAttempts, lease)
+	return args.Get(0).([]*event.Event), args.Error(1)
+}
+
+// MarkPublished mocks stamping an event as published.
+func (m *MockOutboxRepository) MarkPublished(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MarkFailed mocks recording a failed delivery attempt.
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, id int64, cause error, retryAt time.Time, publishedSinks []string) error {
+	args := m.Called(ctx, id, cause, retryAt, publishedSinks)
+	return args.Error(0)
+}
+
 // MockUserRepository is a mock implementation of a user repository for testing purposes.
 type MockUserRepository struct {
 	ShouldFail bool
@@ -82,6 +131,15 @@ func (m *MockUserRepository) GetUserByEmail(ctx context.Context, email string) (
 	return args.Get(0).(*user.User), args.Error(1)
 }
 
+// GetUsersByEmails implements user.UserRepository.
+func (m *MockUserRepository) GetUsersByEmails(ctx context.Context, emails []string) ([]*user.User, []string, error) {
+	args := m.Called(ctx, emails)
+	if args.Error(2) != nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*user.User), args.Get(1).([]string), args.Error(2)
+}
+
 // CreateUser mocks the creation of a user.
 // It returns an error if ShouldFail is set to true, otherwise it returns nil (success).
 func (m *MockUserRepository) CreateUser(ctx context.Context, u *user.CreateUser) error {
@@ -90,3 +148,27 @@ func (m *MockUserRepository) CreateUser(ctx context.Context, u *user.CreateUser)
 	}
 	return nil
 }
+
+// DeleteFriend mocks the removal of a friendship.
+func (m *MockRelationshipRepository) DeleteFriend(ctx context.Context, requestor_id, target_id string) error {
+	args := m.Called(ctx, requestor_id, target_id)
+	return args.Error(0)
+}
+
+// GetFriendsByEmails mocks the retrieval of the friends of several users.
+func (m *MockRelationshipRepository) GetFriendsByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	args := m.Called(ctx, emails)
+	return args.Get(0).(map[string][]string), args.Error(1)
+}
+
+// GetSubscribersByEmails mocks the retrieval of the subscribers of several users.
+func (m *MockRelationshipRepository) GetSubscribersByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	args := m.Called(ctx, emails)
+	return args.Get(0).(map[string][]string), args.Error(1)
+}
+
+// GetBlockedByEmails mocks the retrieval of the users several users block.
+func (m *MockRelationshipRepository) GetBlockedByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	args := m.Called(ctx, emails)
+	return args.Get(0).(map[string][]string), args.Error(1)
+}
@@ -6,13 +6,19 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
 
-	response "github.com/koeylp/friends-management/cmd/internal/handler/rest/response"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database"
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/block"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/subscription"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
 	utils "github.com/koeylp/friends-management/cmd/internal/pkg/string_util"
+	outboxRepo "github.com/koeylp/friends-management/cmd/internal/repository/outbox"
 	relationshipRepo "github.com/koeylp/friends-management/cmd/internal/repository/relationship"
 	userRepo "github.com/koeylp/friends-management/cmd/internal/repository/user"
 )
@@ -20,27 +26,40 @@ import (
 // RelationshipController defines the interface for relationship-related operations.
 type RelationshipController interface {
 	CreateFriend(ctx context.Context, friend *friend.CreateFriend) error
+	Unfriend(ctx context.Context, friend *friend.CreateFriend) error
 	GetFriendListByEmail(ctx context.Context, email string) ([]string, error)
 	GetCommonList(ctx context.Context, friend *friend.CommonFriendListReq) ([]string, error)
 	Subscribe(ctx context.Context, subscribeReq *subscription.SubscribeRequest) error
+	Unsubscribe(ctx context.Context, subscribeReq *subscription.SubscribeRequest) error
 	BlockUpdates(ctx context.Context, blockReq *block.BlockRequest) error
+	UnblockUpdates(ctx context.Context, blockReq *block.BlockRequest) error
 	GetUpdatableEmailAddresses(ctx context.Context, recipientReq *subscription.RecipientRequest) ([]string, error)
+	PostUpdate(ctx context.Context, recipientReq *subscription.RecipientRequest) ([]string, error)
+	GetFriendListsByEmails(ctx context.Context, emails []string) (map[string][]string, error)
+	GetSubscribersByEmails(ctx context.Context, emails []string) (map[string][]string, error)
+	GetBlockedByEmails(ctx context.Context, emails []string) (map[string][]string, error)
 }
 
 // relationshipControllerImpl implements the RelationshipController interface.
+// Operations that change relationships read with database.WithPrimaryReads, so they find
+// users and relationships written a moment earlier even when reads go to a replica.
 type relationshipControllerImpl struct {
 	relationshipRepo relationshipRepo.RelationshipRepository
 	userRepo         userRepo.UserRepository
+	outboxRepo       outboxRepo.OutboxRepository
+	metrics          *metrics.Metrics
 }
 
 // NewRelationshipController creates a new instance of RelationshipController with the provided repositories.
-func NewRelationshipController(relationshipRepo relationshipRepo.RelationshipRepository, userRepo userRepo.UserRepository) RelationshipController {
-	return &relationshipControllerImpl{relationshipRepo: relationshipRepo, userRepo: userRepo}
+// New friendships, blocks and fanned out updates are counted in metrics.
+func NewRelationshipController(relationshipRepo relationshipRepo.RelationshipRepository, userRepo userRepo.UserRepository, outboxRepo outboxRepo.OutboxRepository, metrics *metrics.Metrics) RelationshipController {
+	return &relationshipControllerImpl{relationshipRepo: relationshipRepo, userRepo: userRepo, outboxRepo: outboxRepo, metrics: metrics}
 }
 
 // CreateFriend handles the creation of a new friendship between two users.
 // It checks if a friendship already exists or if there are any blocking updates before creating the friendship.
 func (s *relationshipControllerImpl) CreateFriend(ctx context.Context, friend *friend.CreateFriend) error {
+	ctx = database.WithPrimaryReads(ctx)
 	users, err := s.getUsersByEmails(ctx, friend.Friends)
 	if err != nil {
 		return err
@@ -51,7 +70,7 @@ func (s *relationshipControllerImpl) CreateFriend(ctx context.Context, friend *f
 	}
 
 	if exists {
-		return response.NewBadRequestError("friendship already exists between " + users[0].Email + " and " + users[1].Email)
+		return domain.Errorf(domain.ErrAlreadyFriends, "friendship already exists between %s and %s", users[0].Email, users[1].Email)
 	}
 
 	blockExists, err := s.relationshipRepo.CheckBlockExists(ctx, users[0].ID, users[1].ID)
@@ -60,10 +79,34 @@ func (s *relationshipControllerImpl) CreateFriend(ctx context.Context, friend *f
 	}
 
 	if blockExists {
-		return response.NewBadRequestError("blocking updates exists between " + users[0].Email + " and " + users[1].Email)
+		return domain.Errorf(domain.ErrBlocked, "blocking updates exists between %s and %s", users[0].Email, users[1].Email)
 	}
 
-	return s.relationshipRepo.CreateFriend(ctx, users[0].ID, users[1].ID)
+	if err := s.relationshipRepo.CreateFriend(ctx, users[0].ID, users[1].ID); err != nil {
+		return err
+	}
+	s.metrics.FriendshipCreated()
+	logRelationshipChange(ctx, "friendship created", users[0], users[1])
+	return nil
+}
+
+// Unfriend removes the friendship between two users.
+func (s *relationshipControllerImpl) Unfriend(ctx context.Context, friend *friend.CreateFriend) error {
+	ctx = database.WithPrimaryReads(ctx)
+	users, err := s.getUsersByEmails(ctx, friend.Friends)
+	if err != nil {
+		return err
+	}
+
+	err = s.relationshipRepo.DeleteFriend(ctx, users[0].ID, users[1].ID)
+	if errors.Is(err, domain.ErrNotFriends) {
+		return domain.Errorf(domain.ErrNotFriends, "%s and %s are not friends", users[0].Email, users[1].Email)
+	}
+	if err != nil {
+		return err
+	}
+	logRelationshipChange(ctx, "friendship removed", users[0], users[1])
+	return nil
 }
 
 // GetFriendListByEmail retrieves a list of friends for a user identified by their email.
@@ -71,7 +114,7 @@ func (s *relationshipControllerImpl) CreateFriend(ctx context.Context, friend *f
 func (s *relationshipControllerImpl) GetFriendListByEmail(ctx context.Context, email string) ([]string, error) {
 	foundUser, err := s.userRepo.GetUserByEmail(ctx, email)
 	if err != nil {
-		return nil, response.NewNotFoundError("user not found with email " + email)
+		return nil, err
 	}
 	friends, err := s.relationshipRepo.GetFriends(ctx, foundUser.Email)
 	if err != nil {
@@ -84,18 +127,21 @@ func (s *relationshipControllerImpl) GetFriendListByEmail(ctx context.Context, e
 	return friends, nil
 }
 
-// getUsersByEmails fetches user details for a list of email addresses.
-// It returns a slice of User objects or an error if any user is not found.
+// getUsersByEmails fetches user details for a list of email addresses in one lookup.
+// It returns the users in the order of emails, or domain.ErrUserNotFound naming the emails no user has.
 func (s *relationshipControllerImpl) getUsersByEmails(ctx context.Context, emails []string) ([]*user.User, error) {
-	users := make([]*user.User, len(emails))
-	var err error
-	for i, email := range emails {
-		users[i], err = s.userRepo.GetUserByEmail(ctx, email)
-		if err != nil {
-			return nil, response.NewBadRequestError("user not found with email " + email)
-		}
+	users, missing, err := s.userRepo.GetUsersByEmails(ctx, emails)
+	if err != nil {
+		return nil, err
+	}
+	switch len(missing) {
+	case 0:
+		return users, nil
+	case 1:
+		return nil, domain.Errorf(domain.ErrUserNotFound, "user not found with email %s", missing[0])
+	default:
+		return nil, domain.Errorf(domain.ErrUserNotFound, "users not found with emails %s", strings.Join(missing, ", "))
 	}
-	return users, nil
 }
 
 // GetCommonList retrieves a list of common friends between two users.
@@ -113,22 +159,12 @@ func (s *relationshipControllerImpl) GetCommonList(ctx context.Context, friend *
 }
 
 // Subscribe handles the subscription between two users.
-// It checks if the requestor and target users exist and if a subscription already exists.
+// It checks if the requestor and target users exist and if the requestor is already subscribed to the target.
 func (s *relationshipControllerImpl) Subscribe(ctx context.Context, subscribeReq *subscription.SubscribeRequest) error {
-	requestor, err := s.userRepo.GetUserByEmail(ctx, subscribeReq.Requestor)
+	ctx = database.WithPrimaryReads(ctx)
+	requestor, target, err := s.getRequestorAndTarget(ctx, subscribeReq.Requestor, subscribeReq.Target)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return response.NewBadRequestError("requestor not found")
-		}
-		return fmt.Errorf("failed to retrieve requestor: %w", err)
-	}
-
-	target, err := s.userRepo.GetUserByEmail(ctx, subscribeReq.Target)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return response.NewBadRequestError("target not found")
-		}
-		return fmt.Errorf("failed to retrieve target: %w", err)
+		return err
 	}
 
 	exists, err := s.relationshipRepo.CheckSubscriptionExists(ctx, requestor.ID, target.ID)
@@ -136,62 +172,147 @@ func (s *relationshipControllerImpl) Subscribe(ctx context.Context, subscribeReq
 		return fmt.Errorf("failed to check subcription exist: %w", err)
 	}
 	if exists {
-		return response.NewBadRequestError("subscription already exists between " + requestor.Email + " and " + target.Email)
+		return domain.Errorf(domain.ErrAlreadySubscribed, "subscription already exists between %s and %s", requestor.Email, target.Email)
 	}
 
-	return s.relationshipRepo.Subscribe(ctx, requestor.ID, target.ID)
+	if err := s.relationshipRepo.Subscribe(ctx, requestor.ID, target.ID); err != nil {
+		return err
+	}
+	logRelationshipChange(ctx, "subscription created", requestor, target)
+	return nil
 }
 
 // BlockUpdates handles the request to block updates from a target user.
-// It checks if the requestor and target users exist and if a block already exists.
+// It checks if the requestor and target users exist and if the requestor already blocks the target.
 func (s *relationshipControllerImpl) BlockUpdates(ctx context.Context, blockReq *block.BlockRequest) error {
-	requestor, err := s.userRepo.GetUserByEmail(ctx, blockReq.Requestor)
+	ctx = database.WithPrimaryReads(ctx)
+	requestor, target, err := s.getRequestorAndTarget(ctx, blockReq.Requestor, blockReq.Target)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return response.NewBadRequestError("requestor not found")
-		}
-		return fmt.Errorf("failed to retrieve requestor: %w", err)
+		return err
 	}
 
-	target, err := s.userRepo.GetUserByEmail(ctx, blockReq.Target)
+	exists, err := s.relationshipRepo.CheckBlocking(ctx, requestor.ID, target.ID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return response.NewBadRequestError("target not found")
-		}
-		return fmt.Errorf("failed to retrieve target: %w", err)
+		return fmt.Errorf("failed to check blocking updates exist: %w", err)
+	}
+	if exists {
+		return domain.Errorf(domain.ErrAlreadyBlocked, "blocking updates already exists between %s and %s", requestor.Email, target.Email)
 	}
 
-	exists, err := s.relationshipRepo.CheckBlockExists(ctx, requestor.ID, target.ID)
+	if err := s.relationshipRepo.BlockUpdates(ctx, requestor.ID, target.ID); err != nil {
+		return err
+	}
+	s.metrics.BlockCreated()
+	logRelationshipChange(ctx, "block created", requestor, target)
+	return nil
+}
+
+// Unsubscribe removes the requestor's subscription to the target's updates.
+func (s *relationshipControllerImpl) Unsubscribe(ctx context.Context, subscribeReq *subscription.SubscribeRequest) error {
+	ctx = database.WithPrimaryReads(ctx)
+	requestor, target, err := s.getRequestorAndTarget(ctx, subscribeReq.Requestor, subscribeReq.Target)
 	if err != nil {
-		return fmt.Errorf("failed to check blocking updates exist: %w", err)
+		return err
 	}
-	if exists {
-		return response.NewBadRequestError("blocking updates already exists between " + requestor.Email + " and " + target.Email)
+
+	err = s.relationshipRepo.Unsubscribe(ctx, requestor.ID, target.ID)
+	if errors.Is(err, domain.ErrNotSubscribed) {
+		return domain.Errorf(domain.ErrNotSubscribed, "%s is not subscribed to %s", requestor.Email, target.Email)
+	}
+	if err != nil {
+		return err
+	}
+	logRelationshipChange(ctx, "subscription removed", requestor, target)
+	return nil
+}
+
+// UnblockUpdates removes the requestor's block on the target's updates.
+func (s *relationshipControllerImpl) UnblockUpdates(ctx context.Context, blockReq *block.BlockRequest) error {
+	ctx = database.WithPrimaryReads(ctx)
+	requestor, target, err := s.getRequestorAndTarget(ctx, blockReq.Requestor, blockReq.Target)
+	if err != nil {
+		return err
+	}
+
+	err = s.relationshipRepo.UnblockUpdates(ctx, requestor.ID, target.ID)
+	if errors.Is(err, domain.ErrNotBlocked) {
+		return domain.Errorf(domain.ErrNotBlocked, "%s does not block updates from %s", requestor.Email, target.Email)
+	}
+	if err != nil {
+		return err
+	}
+	logRelationshipChange(ctx, "block removed", requestor, target)
+	return nil
+}
+
+// logRelationshipChange records a stored relationship change through the request's logger.
+func logRelationshipChange(ctx context.Context, msg string, requestor, target *user.User) {
+	logging.FromContext(ctx).InfoContext(ctx, msg, "requestor", requestor.Email, "target", target.Email)
+}
+
+// getRequestorAndTarget fetches both users of a directed relationship, reporting which one does not exist.
+func (s *relationshipControllerImpl) getRequestorAndTarget(ctx context.Context, requestorEmail, targetEmail string) (*user.User, *user.User, error) {
+	requestor, err := s.userRepo.GetUserByEmail(ctx, requestorEmail)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, nil, domain.Errorf(domain.ErrUserNotFound, "requestor not found")
+		}
+		return nil, nil, fmt.Errorf("failed to retrieve requestor: %w", err)
 	}
 
-	return s.relationshipRepo.BlockUpdates(ctx, requestor.ID, target.ID)
+	target, err := s.userRepo.GetUserByEmail(ctx, targetEmail)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, nil, domain.Errorf(domain.ErrUserNotFound, "target not found")
+		}
+		return nil, nil, fmt.Errorf("failed to retrieve target: %w", err)
+	}
+	return requestor, target, nil
 }
 
 // GetUpdatableEmailAddresses retrieves email addresses that can be updated based on the sender's context.
 // It analyzes mentioned emails in a text and checks if they can be updated.
+// It only reads; PostUpdate is what publishes the update.
 func (s *relationshipControllerImpl) GetUpdatableEmailAddresses(ctx context.Context, recipientReq *subscription.RecipientRequest) ([]string, error) {
+	_, recipients, err := s.recipients(ctx, recipientReq)
+	return recipients, err
+}
+
+// PostUpdate posts an update from the sender to the addresses GetUpdatableEmailAddresses returns.
+// The update and its recipients are published as an update.posted event.
+func (s *relationshipControllerImpl) PostUpdate(ctx context.Context, recipientReq *subscription.RecipientRequest) ([]string, error) {
+	sender, recipients, err := s.recipients(ctx, recipientReq)
+	if err != nil {
+		return nil, err
+	}
+
+	update := &event.UpdateData{Sender: sender.Email, Text: recipientReq.Text, Recipients: recipients}
+	if err := s.outboxRepo.Append(ctx, event.UpdatePosted, update); err != nil {
+		return nil, err
+	}
+	s.metrics.UpdateFannedOut(len(recipients))
+	return recipients, nil
+}
+
+// recipients looks up the sender and the email addresses that receive its update.
+func (s *relationshipControllerImpl) recipients(ctx context.Context, recipientReq *subscription.RecipientRequest) (*user.User, []string, error) {
 	sender, err := s.userRepo.GetUserByEmail(ctx, recipientReq.Sender)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, response.NewBadRequestError("sender not found")
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, nil, domain.Errorf(domain.ErrUserNotFound, "sender not found")
 		}
-		return nil, fmt.Errorf("failed to retrieve requestor: %w", err)
+		return nil, nil, fmt.Errorf("failed to retrieve requestor: %w", err)
 	}
 
 	mentionedEmails := utils.GetEmailFromText(recipientReq.Text)
 	users, err := s.getUsersByEmails(ctx, mentionedEmails)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	recipients, err := s.relationshipRepo.GetUpdatableEmailAddresses(ctx, sender.ID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for _, user := range users {
@@ -199,5 +320,33 @@ func (s *relationshipControllerImpl) GetUpdatableEmailAddresses(ctx context.Cont
 			recipients = append(recipients, user.Email)
 		}
 	}
-	return recipients, nil
+	return sender, recipients, nil
+}
+
+// GetFriendListsByEmails retrieves the friend lists of several users at once, keyed by email.
+// Users without friends, or that do not exist, are absent from the result.
+func (s *relationshipControllerImpl) GetFriendListsByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	friends, err := s.relationshipRepo.GetFriendsByEmails(ctx, emails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve friends: %w", err)
+	}
+	return friends, nil
+}
+
+// GetSubscribersByEmails retrieves the users subscribed to each of several users at once, keyed by email.
+func (s *relationshipControllerImpl) GetSubscribersByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	subscribers, err := s.relationshipRepo.GetSubscribersByEmails(ctx, emails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve subscribers: %w", err)
+	}
+	return subscribers, nil
+}
+
+// GetBlockedByEmails retrieves the users each of several users blocks at once, keyed by email.
+func (s *relationshipControllerImpl) GetBlockedByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	blocked, err := s.relationshipRepo.GetBlockedByEmails(ctx, emails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve blocked users: %w", err)
+	}
+	return blocked, nil
 }
@@ -2,15 +2,19 @@ package relationship
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"testing"
 
+	"github.com/koeylp/friends-management/cmd/internal/infra/database"
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/block"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/subscription"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // Tests various scenarios for creating a friend relationship, including:
@@ -20,12 +24,13 @@ import (
 //   - Database errors during checks.
 //   - User not found scenarios for both requestor and target.
 func TestCreateFriend(t *testing.T) {
-	ctx := context.Background()
+	// Changes look users and relationships up on the primary.
+	ctx := database.WithPrimaryReads(context.Background())
 
 	mockRelRepo := new(MockRelationshipRepository)
 	mockUserRepo := new(MockUserRepository)
 
-	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo)
+	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo, new(MockOutboxRepository), metrics.New())
 
 	inputEmails := []string{"requestor@example.com", "target@example.com"}
 	input := &friend.CreateFriend{
@@ -38,13 +43,13 @@ func TestCreateFriend(t *testing.T) {
 	}
 
 	// Case 1: Friendship already exists
-	mockUserRepo.On("GetUserByEmail", ctx, "requestor@example.com").Return(mockUsers[0], nil)
-	mockUserRepo.On("GetUserByEmail", ctx, "target@example.com").Return(mockUsers[1], nil)
+	mockUserRepo.On("GetUsersByEmails", ctx, inputEmails).Return(mockUsers, []string{}, nil)
 	mockRelRepo.On("CheckFriendshipExists", ctx, "1", "2").Return(true, nil)
 
 	err := ctrl.CreateFriend(ctx, input)
 	assert.NotNil(t, err)
-	assert.EqualError(t, err, "400: friendship already exists between requestor@example.com and target@example.com")
+	assert.ErrorIs(t, err, domain.ErrAlreadyFriends)
+	assert.EqualError(t, err, "friendship already exists between requestor@example.com and target@example.com")
 
 	mockRelRepo.ExpectedCalls = nil
 
@@ -64,7 +69,8 @@ func TestCreateFriend(t *testing.T) {
 
 	err = ctrl.CreateFriend(ctx, input)
 	assert.NotNil(t, err)
-	assert.EqualError(t, err, "400: blocking updates exists between requestor@example.com and target@example.com")
+	assert.ErrorIs(t, err, domain.ErrBlocked)
+	assert.EqualError(t, err, "blocking updates exists between requestor@example.com and target@example.com")
 
 	mockRelRepo.ExpectedCalls = nil
 
@@ -88,22 +94,37 @@ func TestCreateFriend(t *testing.T) {
 	mockUserRepo.ExpectedCalls = nil
 
 	// Case 6: User not found (requestor)
-	mockUserRepo.On("GetUserByEmail", ctx, "requestor@example.com").Return(nil, errors.New("user not found"))
-	mockUserRepo.On("GetUserByEmail", ctx, "target@example.com").Return(mockUsers[1], nil)
+	mockUserRepo.On("GetUsersByEmails", ctx, inputEmails).Return(mockUsers[1:], []string{"requestor@example.com"}, nil)
 
 	err = ctrl.CreateFriend(ctx, input)
-	assert.NotNil(t, err)
-	assert.EqualError(t, err, "400: user not found with email requestor@example.com")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	assert.EqualError(t, err, "user not found with email requestor@example.com")
 
 	mockUserRepo.ExpectedCalls = nil
 
 	// Case 7: User not found (target)
-	mockUserRepo.On("GetUserByEmail", ctx, "requestor@example.com").Return(mockUsers[0], nil)
-	mockUserRepo.On("GetUserByEmail", ctx, "target@example.com").Return(nil, errors.New("user not found"))
+	mockUserRepo.On("GetUsersByEmails", ctx, inputEmails).Return(mockUsers[:1], []string{"target@example.com"}, nil)
 
 	err = ctrl.CreateFriend(ctx, input)
-	assert.NotNil(t, err)
-	assert.EqualError(t, err, "400: user not found with email target@example.com")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	assert.EqualError(t, err, "user not found with email target@example.com")
+
+	mockUserRepo.ExpectedCalls = nil
+
+	// Case 8: Neither user found
+	mockUserRepo.On("GetUsersByEmails", ctx, inputEmails).Return([]*user.User{}, inputEmails, nil)
+
+	err = ctrl.CreateFriend(ctx, input)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	assert.EqualError(t, err, "users not found with emails requestor@example.com, target@example.com")
+
+	mockUserRepo.ExpectedCalls = nil
+
+	// Case 9: Error while looking the users up
+	mockUserRepo.On("GetUsersByEmails", ctx, inputEmails).Return([]*user.User(nil), []string(nil), errors.New("database error"))
+
+	err = ctrl.CreateFriend(ctx, input)
+	assert.EqualError(t, err, "database error")
 }
 
 // Tests the successful retrieval of a friend's list.
@@ -113,7 +134,7 @@ func TestGetFriendListByEmail_Success(t *testing.T) {
 
 	mockRelRepo := new(MockRelationshipRepository)
 	mockUserRepo := new(MockUserRepository)
-	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo)
+	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo, new(MockOutboxRepository), metrics.New())
 
 	mockUserRepo.On("GetUserByEmail", ctx, "user@example.com").Return(mockUser, nil)
 
@@ -133,7 +154,7 @@ func TestGetFriendListByEmail_NoFriendsFound(t *testing.T) {
 	mockRelRepo := new(MockRelationshipRepository)
 	mockUserRepo := new(MockUserRepository)
 	mockUser := &user.User{ID: "1", Email: "user@example.com"}
-	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo)
+	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo, new(MockOutboxRepository), metrics.New())
 	mockUserRepo.On("GetUserByEmail", ctx, "user@example.com").Return(mockUser, nil)
 	mockRelRepo.On("GetFriends", ctx, mockUser.Email).
 		Return([]string{}, nil)
@@ -151,7 +172,7 @@ func TestGetFriendListByEmail_ErrorFetchingFriends(t *testing.T) {
 	mockRelRepo := new(MockRelationshipRepository)
 	mockUserRepo := new(MockUserRepository)
 	mockUser := &user.User{ID: "1", Email: "user@example.com"}
-	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo)
+	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo, new(MockOutboxRepository), metrics.New())
 	mockUserRepo.On("GetUserByEmail", ctx, "user@example.com").Return(mockUser, nil)
 	mockRelRepo.On("GetFriends", ctx, "user@example.com").
 		Return([]string{}, errors.New("database error"))
@@ -168,7 +189,7 @@ func TestRelationshipctrl_GetCommonList(t *testing.T) {
 	ctx := context.Background()
 	mockRelRepo := new(MockRelationshipRepository)
 	mockUserRepo := new(MockUserRepository)
-	mockctrl := NewRelationshipController(mockRelRepo, mockUserRepo)
+	mockctrl := NewRelationshipController(mockRelRepo, mockUserRepo, new(MockOutboxRepository), metrics.New())
 
 	req := &friend.CommonFriendListReq{
 		Friends: []string{"user@example.com", "user1@example.com"},
@@ -178,8 +199,7 @@ func TestRelationshipctrl_GetCommonList(t *testing.T) {
 		{ID: "1", Email: "user@example.com"},
 		{ID: "2", Email: "user1@example.com"},
 	}
-	mockUserRepo.On("GetUserByEmail", ctx, "user@example.com").Return(users[0], nil)
-	mockUserRepo.On("GetUserByEmail", ctx, "user1@example.com").Return(users[1], nil)
+	mockUserRepo.On("GetUsersByEmails", ctx, req.Friends).Return(users, []string{}, nil)
 
 	expectedCommonFriends := []string{"common.friend1@example.com", "common.friend2@example.com"}
 
@@ -195,12 +215,12 @@ func TestRelationshipctrl_GetCommonList(t *testing.T) {
 
 // Tests the successful subscription between users.
 func TestSubcribe_Success(t *testing.T) {
-	ctx := context.Background()
+	ctx := database.WithPrimaryReads(context.Background())
 
 	mockRelRepo := new(MockRelationshipRepository)
 	mockUserRepo := new(MockUserRepository)
 
-	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo)
+	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo, new(MockOutboxRepository), metrics.New())
 
 	requestor := &user.User{ID: "123", Email: "requestor@example.com"}
 	target := &user.User{ID: "456", Email: "target@example.com"}
@@ -227,12 +247,12 @@ func TestSubcribe_Success(t *testing.T) {
 
 // Tests scenarios for blocking updates, including existing relationships and successful blocking
 func TestBlockUpdates(t *testing.T) {
-	ctx := context.Background()
+	ctx := database.WithPrimaryReads(context.Background())
 
 	mockRelRepo := new(MockRelationshipRepository)
 	mockUserRepo := new(MockUserRepository)
 
-	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo)
+	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo, new(MockOutboxRepository), metrics.New())
 
 	inputEmails := &block.BlockRequest{
 		Requestor: "requestor@example.com",
@@ -264,27 +284,125 @@ func TestBlockUpdates(t *testing.T) {
 	// Case 3: Block relationship already exists
 	mockUserRepo.On("GetUserByEmail", ctx, "requestor@example.com").Return(mockUsers[0], nil)
 	mockUserRepo.On("GetUserByEmail", ctx, "target@example.com").Return(mockUsers[1], nil)
-	mockRelRepo.On("CheckBlockExists", ctx, "1", "2").Return(true, nil)
+	mockRelRepo.On("CheckBlocking", ctx, "1", "2").Return(true, nil)
 	err = ctrl.BlockUpdates(ctx, inputEmails)
 	assert.NotNil(t, err)
-	assert.EqualError(t, err, "400: blocking updates already exists between requestor@example.com and target@example.com")
+	assert.ErrorIs(t, err, domain.ErrAlreadyBlocked)
+	assert.EqualError(t, err, "blocking updates already exists between requestor@example.com and target@example.com")
 
 	mockRelRepo.ExpectedCalls = nil
 
 	// Case 4: Successful block
-	mockRelRepo.On("CheckBlockExists", ctx, "1", "2").Return(false, nil)
+	mockRelRepo.On("CheckBlocking", ctx, "1", "2").Return(false, nil)
 	mockRelRepo.On("BlockUpdates", ctx, "1", "2").Return(nil)
 	err = ctrl.BlockUpdates(ctx, inputEmails)
 	assert.Nil(t, err)
 
 	// Case 5: Error while checking block existence
 	mockRelRepo.ExpectedCalls = nil
-	mockRelRepo.On("CheckBlockExists", ctx, "1", "2").Return(false, errors.New("database error"))
+	mockRelRepo.On("CheckBlocking", ctx, "1", "2").Return(false, errors.New("database error"))
 	err = ctrl.BlockUpdates(ctx, inputEmails)
 	assert.NotNil(t, err)
 	assert.EqualError(t, err, "failed to check blocking updates exist: database error")
 }
 
+// Tests removing a subscription, including when the requestor is not subscribed.
+func TestUnsubscribe(t *testing.T) {
+	ctx := database.WithPrimaryReads(context.Background())
+
+	mockRelRepo := new(MockRelationshipRepository)
+	mockUserRepo := new(MockUserRepository)
+
+	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo, new(MockOutboxRepository), metrics.New())
+
+	subscribeReq := &subscription.SubscribeRequest{
+		Requestor: "requestor@example.com",
+		Target:    "target@example.com",
+	}
+
+	mockUserRepo.On("GetUserByEmail", ctx, "requestor@example.com").Return(&user.User{ID: "1", Email: "requestor@example.com"}, nil)
+	mockUserRepo.On("GetUserByEmail", ctx, "target@example.com").Return(&user.User{ID: "2", Email: "target@example.com"}, nil)
+
+	// Case 1: Successful unsubscribe
+	mockRelRepo.On("Unsubscribe", ctx, "1", "2").Return(nil).Once()
+	err := ctrl.Unsubscribe(ctx, subscribeReq)
+	assert.NoError(t, err)
+
+	// Case 2: No subscription to remove
+	mockRelRepo.On("Unsubscribe", ctx, "1", "2").Return(domain.Errorf(domain.ErrNotSubscribed, "subscription not found")).Once()
+	err = ctrl.Unsubscribe(ctx, subscribeReq)
+	assert.ErrorIs(t, err, domain.ErrNotSubscribed)
+	assert.EqualError(t, err, "requestor@example.com is not subscribed to target@example.com")
+
+	mockRelRepo.AssertExpectations(t)
+}
+
+// Tests removing a friendship, including when the two users are not friends.
+func TestUnfriend(t *testing.T) {
+	ctx := database.WithPrimaryReads(context.Background())
+
+	mockRelRepo := new(MockRelationshipRepository)
+	mockUserRepo := new(MockUserRepository)
+
+	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo, new(MockOutboxRepository), metrics.New())
+
+	unfriendReq := &friend.CreateFriend{Friends: []string{"andy@example.com", "john@example.com"}}
+
+	mockUserRepo.On("GetUsersByEmails", ctx, unfriendReq.Friends).Return([]*user.User{
+		{ID: "1", Email: "andy@example.com"},
+		{ID: "2", Email: "john@example.com"},
+	}, []string{}, nil)
+
+	// Case 1: Successful removal
+	mockRelRepo.On("DeleteFriend", ctx, "1", "2").Return(nil).Once()
+	err := ctrl.Unfriend(ctx, unfriendReq)
+	assert.NoError(t, err)
+
+	// Case 2: No friendship to remove
+	mockRelRepo.On("DeleteFriend", ctx, "1", "2").Return(domain.Errorf(domain.ErrNotFriends, "friendship not found")).Once()
+	err = ctrl.Unfriend(ctx, unfriendReq)
+	assert.ErrorIs(t, err, domain.ErrNotFriends)
+	assert.EqualError(t, err, "andy@example.com and john@example.com are not friends")
+
+	mockRelRepo.AssertExpectations(t)
+}
+
+// Tests removing a block, including when the target does not exist.
+func TestUnblockUpdates(t *testing.T) {
+	ctx := database.WithPrimaryReads(context.Background())
+
+	mockRelRepo := new(MockRelationshipRepository)
+	mockUserRepo := new(MockUserRepository)
+
+	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo, new(MockOutboxRepository), metrics.New())
+
+	blockReq := &block.BlockRequest{
+		Requestor: "requestor@example.com",
+		Target:    "target@example.com",
+	}
+
+	// Case 1: Target not found
+	mockUserRepo.On("GetUserByEmail", ctx, "requestor@example.com").Return(&user.User{ID: "1", Email: "requestor@example.com"}, nil)
+	mockUserRepo.On("GetUserByEmail", ctx, "target@example.com").Return(nil, domain.Errorf(domain.ErrUserNotFound, "user not found with email target@example.com")).Once()
+	err := ctrl.UnblockUpdates(ctx, blockReq)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	assert.EqualError(t, err, "target not found")
+
+	// Case 2: No block to remove
+	mockUserRepo.On("GetUserByEmail", ctx, "target@example.com").Return(&user.User{ID: "2", Email: "target@example.com"}, nil)
+	mockRelRepo.On("UnblockUpdates", ctx, "1", "2").Return(domain.Errorf(domain.ErrNotBlocked, "blocking updates not found")).Once()
+	err = ctrl.UnblockUpdates(ctx, blockReq)
+	assert.ErrorIs(t, err, domain.ErrNotBlocked)
+	assert.EqualError(t, err, "requestor@example.com does not block updates from target@example.com")
+
+	// Case 3: Successful unblock
+	mockRelRepo.On("UnblockUpdates", ctx, "1", "2").Return(nil).Once()
+	err = ctrl.UnblockUpdates(ctx, blockReq)
+	assert.NoError(t, err)
+
+	mockRelRepo.AssertExpectations(t)
+}
+
 // Tests the retrieval of updatable email addresses based on a message from a sender,
 // including error handling for missing sender information.
 func TestGetUpdatableEmailAddresses(t *testing.T) {
@@ -292,7 +410,8 @@ func TestGetUpdatableEmailAddresses(t *testing.T) {
 
 	mockRelRepo := new(MockRelationshipRepository)
 	mockUserRepo := new(MockUserRepository)
-	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo)
+	mockOutboxRepo := new(MockOutboxRepository)
+	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo, mockOutboxRepo, metrics.New())
 
 	recipientReq := &subscription.RecipientRequest{
 		Sender: "sender@example.com",
@@ -303,34 +422,111 @@ func TestGetUpdatableEmailAddresses(t *testing.T) {
 	updatableEmails := []string{"existing@example.com"}
 
 	// Case 1: Sender not found
-	mockUserRepo.On("GetUserByEmail", ctx, "sender@example.com").Return(nil, sql.ErrNoRows)
+	mockUserRepo.On("GetUserByEmail", ctx, "sender@example.com").Return(nil, domain.Errorf(domain.ErrUserNotFound, "user not found with email sender@example.com"))
 	recipients, err := ctrl.GetUpdatableEmailAddresses(ctx, recipientReq)
 	assert.Nil(t, recipients)
 	assert.NotNil(t, err)
-	assert.EqualError(t, err, "400: sender not found")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	assert.EqualError(t, err, "sender not found")
 
 	mockUserRepo.ExpectedCalls = nil
 
 	// Case 2: Successful retrieval of updatable email addresses
 	mockUserRepo.On("GetUserByEmail", ctx, "sender@example.com").Return(sender, nil)
-	mockUserRepo.On("GetUserByEmail", ctx, "some@example.com").Return(userMentioned, nil)
+	mockUserRepo.On("GetUsersByEmails", ctx, []string{"some@example.com"}).Return([]*user.User{userMentioned}, []string{}, nil)
 	mockRelRepo.On("GetUpdatableEmailAddresses", ctx, sender.ID).Return(updatableEmails, nil)
 
 	recipients, err = ctrl.GetUpdatableEmailAddresses(ctx, recipientReq)
 	assert.Nil(t, err)
 	assert.Contains(t, recipients, "existing@example.com")
 	assert.Contains(t, recipients, "some@example.com")
+	mockOutboxRepo.AssertNotCalled(t, "Append", mock.Anything, mock.Anything, mock.Anything)
 
 	mockUserRepo.ExpectedCalls = nil
 	mockRelRepo.ExpectedCalls = nil
 
 	// Case 3: Error in retrieving updatable email addresses
 	mockUserRepo.On("GetUserByEmail", ctx, "sender@example.com").Return(sender, nil)
-	mockUserRepo.On("GetUserByEmail", ctx, "some@example.com").Return(userMentioned, nil)
+	mockUserRepo.On("GetUsersByEmails", ctx, []string{"some@example.com"}).Return([]*user.User{userMentioned}, []string{}, nil)
 	mockRelRepo.On("GetUpdatableEmailAddresses", ctx, sender.ID).Return([]string(nil), errors.New("db error"))
 
 	recipients, err = ctrl.GetUpdatableEmailAddresses(ctx, recipientReq)
 	assert.Nil(t, recipients)
 	assert.NotNil(t, err)
 	assert.EqualError(t, err, "db error")
+
+}
+
+// Tests that posting an update publishes it with its recipients as an update.posted event.
+func TestPostUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	mockRelRepo := new(MockRelationshipRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockOutboxRepo := new(MockOutboxRepository)
+	ctrl := NewRelationshipController(mockRelRepo, mockUserRepo, mockOutboxRepo, metrics.New())
+
+	recipientReq := &subscription.RecipientRequest{
+		Sender: "sender@example.com",
+		Text:   "This is a test email with some@example.com",
+	}
+	sender := &user.User{ID: "1", Email: "sender@example.com"}
+	userMentioned := &user.User{ID: "2", Email: "some@example.com"}
+
+	// Case 1: Sender not found, nothing is published
+	mockUserRepo.On("GetUserByEmail", ctx, "sender@example.com").Return(nil, domain.Errorf(domain.ErrUserNotFound, "user not found with email sender@example.com"))
+	recipients, err := ctrl.PostUpdate(ctx, recipientReq)
+	assert.Nil(t, recipients)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	mockOutboxRepo.AssertNotCalled(t, "Append", mock.Anything, mock.Anything, mock.Anything)
+
+	mockUserRepo.ExpectedCalls = nil
+
+	// Case 2: The update is published with its recipients
+	mockUserRepo.On("GetUserByEmail", ctx, "sender@example.com").Return(sender, nil)
+	mockUserRepo.On("GetUsersByEmails", ctx, []string{"some@example.com"}).Return([]*user.User{userMentioned}, []string{}, nil)
+	mockRelRepo.On("GetUpdatableEmailAddresses", ctx, sender.ID).Return([]string{"existing@example.com"}, nil)
+	mockOutboxRepo.On("Append", ctx, event.UpdatePosted, &event.UpdateData{
+		Sender:     "sender@example.com",
+		Text:       recipientReq.Text,
+		Recipients: []string{"existing@example.com", "some@example.com"},
+	}).Return(nil).Once()
+
+	recipients, err = ctrl.PostUpdate(ctx, recipientReq)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"existing@example.com", "some@example.com"}, recipients)
+	mockOutboxRepo.AssertExpectations(t)
+
+	// Case 3: Error while publishing the update
+	mockOutboxRepo.On("Append", ctx, event.UpdatePosted, mock.Anything).Return(errors.New("failed to record update.posted event: db error"))
+
+	recipients, err = ctrl.PostUpdate(ctx, recipientReq)
+	assert.Nil(t, recipients)
+	assert.EqualError(t, err, "failed to record update.posted event: db error")
+}
+
+// Tests that the batch lookups delegate to the repository and wrap its errors.
+func TestBatchLookups(t *testing.T) {
+	ctx := context.Background()
+	emails := []string{"andy@example.com", "john@example.com"}
+
+	mockRelRepo := new(MockRelationshipRepository)
+	ctrl := NewRelationshipController(mockRelRepo, new(MockUserRepository), new(MockOutboxRepository), metrics.New())
+
+	mockRelRepo.On("GetFriendsByEmails", ctx, emails).Return(map[string][]string{"andy@example.com": {"john@example.com"}}, nil)
+	mockRelRepo.On("GetSubscribersByEmails", ctx, emails).Return(map[string][]string{}, nil)
+	mockRelRepo.On("GetBlockedByEmails", ctx, emails).Return(map[string][]string(nil), errors.New("connection reset"))
+
+	friends, err := ctrl.GetFriendListsByEmails(ctx, emails)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"andy@example.com": {"john@example.com"}}, friends)
+
+	subscribers, err := ctrl.GetSubscribersByEmails(ctx, emails)
+	assert.NoError(t, err)
+	assert.Empty(t, subscribers)
+
+	_, err = ctrl.GetBlockedByEmails(ctx, emails)
+	assert.EqualError(t, err, "failed to retrieve blocked users: connection reset")
+
+	mockRelRepo.AssertExpectations(t)
 }
@@ -0,0 +1,98 @@
+package relationship
+
+import (
+	"context"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/tracing"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/block"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/subscription"
+)
+
+// tracedRelationshipController records every call to the wrapped controller as a span.
+type tracedRelationshipController struct {
+	next RelationshipController
+}
+
+// NewTracedRelationshipController wraps ctrl so each call is a span named "RelationshipController.<method>".
+func NewTracedRelationshipController(ctrl RelationshipController) RelationshipController {
+	return &tracedRelationshipController{next: ctrl}
+}
+
+func (c *tracedRelationshipController) CreateFriend(ctx context.Context, friend *friend.CreateFriend) (err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipController.CreateFriend")
+	defer tracing.End(span, &err)
+	return c.next.CreateFriend(ctx, friend)
+}
+
+func (c *tracedRelationshipController) Unfriend(ctx context.Context, friend *friend.CreateFriend) (err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipController.Unfriend")
+	defer tracing.End(span, &err)
+	return c.next.Unfriend(ctx, friend)
+}
+
+func (c *tracedRelationshipController) GetFriendListByEmail(ctx context.Context, email string) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipController.GetFriendListByEmail")
+	defer tracing.End(span, &err)
+	return c.next.GetFriendListByEmail(ctx, email)
+}
+
+func (c *tracedRelationshipController) GetCommonList(ctx context.Context, friend *friend.CommonFriendListReq) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipController.GetCommonList")
+	defer tracing.End(span, &err)
+	return c.next.GetCommonList(ctx, friend)
+}
+
+func (c *tracedRelationshipController) Subscribe(ctx context.Context, subscribeReq *subscription.SubscribeRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipController.Subscribe")
+	defer tracing.End(span, &err)
+	return c.next.Subscribe(ctx, subscribeReq)
+}
+
+func (c *tracedRelationshipController) Unsubscribe(ctx context.Context, subscribeReq *subscription.SubscribeRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipController.Unsubscribe")
+	defer tracing.End(span, &err)
+	return c.next.Unsubscribe(ctx, subscribeReq)
+}
+
+func (c *tracedRelationshipController) BlockUpdates(ctx context.Context, blockReq *block.BlockRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipController.BlockUpdates")
+	defer tracing.End(span, &err)
+	return c.next.BlockUpdates(ctx, blockReq)
+}
+
+func (c *tracedRelationshipController) UnblockUpdates(ctx context.Context, blockReq *block.BlockRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipController.UnblockUpdates")
+	defer tracing.End(span, &err)
+	return c.next.UnblockUpdates(ctx, blockReq)
+}
+
+func (c *tracedRelationshipController) GetUpdatableEmailAddresses(ctx context.Context, recipientReq *subscription.RecipientRequest) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipController.GetUpdatableEmailAddresses")
+	defer tracing.End(span, &err)
+	return c.next.GetUpdatableEmailAddresses(ctx, recipientReq)
+}
+
+func (c *tracedRelationshipController) PostUpdate(ctx context.Context, recipientReq *subscription.RecipientRequest) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipController.PostUpdate")
+	defer tracing.End(span, &err)
+	return c.next.PostUpdate(ctx, recipientReq)
+}
+
+func (c *tracedRelationshipController) GetFriendListsByEmails(ctx context.Context, emails []string) (_ map[string][]string, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipController.GetFriendListsByEmails")
+	defer tracing.End(span, &err)
+	return c.next.GetFriendListsByEmails(ctx, emails)
+}
+
+func (c *tracedRelationshipController) GetSubscribersByEmails(ctx context.Context, emails []string) (_ map[string][]string, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipController.GetSubscribersByEmails")
+	defer tracing.End(span, &err)
+	return c.next.GetSubscribersByEmails(ctx, emails)
+}
+
+func (c *tracedRelationshipController) GetBlockedByEmails(ctx context.Context, emails []string) (_ map[string][]string, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipController.GetBlockedByEmails")
+	defer tracing.End(span, &err)
+	return c.next.GetBlockedByEmails(ctx, emails)
+}
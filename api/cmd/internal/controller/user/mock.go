@@ -22,3 +22,12 @@ func (m *MockUserRepository) CreateUser(ctx context.Context, u *user.CreateUser)
 	}
 	return nil
 }
+
+// GetUsersByEmails mocks the retrieval of several users by email.
+func (m *MockUserRepository) GetUsersByEmails(ctx context.Context, emails []string) ([]*user.User, []string, error) {
+	args := m.Called(ctx, emails)
+	if args.Error(2) != nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*user.User), args.Get(1).([]string), args.Error(2)
+}
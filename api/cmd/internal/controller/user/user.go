@@ -2,7 +2,11 @@ package user
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
 	userRepo "github.com/koeylp/friends-management/cmd/internal/repository/user"
 )
@@ -24,13 +28,23 @@ func NewUserController(userRepo userRepo.UserRepository) UserController {
 }
 
 // CreateUser handles the creation of a new user.
+// It fails with a conflict if a user with the same email already exists.
 func (s *userControllerImpl) CreateUser(ctx context.Context, user *user.CreateUser) error {
-	err := s.userRepo.CreateUser(ctx, user)
+	_, err := s.userRepo.GetUserByEmail(ctx, user.Email)
+	if err == nil {
+		return domain.Errorf(domain.ErrUserExists, "user already exists with email %s", user.Email)
+	}
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return fmt.Errorf("failed to check user exist: %w", err)
+	}
+
+	err = s.userRepo.CreateUser(ctx, user)
 	if err != nil {
 		return err
 	}
 
-	return err
+	logging.FromContext(ctx).InfoContext(ctx, "user created", "email", user.Email)
+	return nil
 }
 
 // GetUserByEmail retrieves a user by their email address.
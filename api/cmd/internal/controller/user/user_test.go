@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -27,6 +28,7 @@ func TestCreateUser_Success(t *testing.T) {
 	sampleUser := &user.CreateUser{
 		Email: "test@example.com",
 	}
+	mockRepo.On("GetUserByEmail", mock.Anything, "test@example.com").Return(nil, domain.ErrUserNotFound)
 
 	err := userController.CreateUser(context.Background(), sampleUser)
 	assert.NoError(t, err, "expected no error, got %v", err)
@@ -40,12 +42,26 @@ func TestCreateUser_Failure(t *testing.T) {
 	sampleUser := &user.CreateUser{
 		Email: "fail@example.com",
 	}
+	mockRepo.On("GetUserByEmail", mock.Anything, "fail@example.com").Return(nil, domain.ErrUserNotFound)
 
 	err := userController.CreateUser(context.Background(), sampleUser)
 
 	assert.Error(t, err, "expected an error, got nil")
 }
 
+// TestCreateUser_AlreadyExists tests that creating a user with a taken email fails with domain.ErrUserExists.
+func TestCreateUser_AlreadyExists(t *testing.T) {
+	mockRepo := &MockUserRepository{ShouldFail: false}
+	userController := NewUserController(mockRepo)
+
+	mockRepo.On("GetUserByEmail", mock.Anything, "taken@example.com").Return(&user.User{ID: "123", Email: "taken@example.com"}, nil)
+
+	err := userController.CreateUser(context.Background(), &user.CreateUser{Email: "taken@example.com"})
+
+	assert.ErrorIs(t, err, domain.ErrUserExists)
+	assert.EqualError(t, err, "user already exists with email taken@example.com")
+}
+
 // TestGetUserByEmail tests retrieving a user by email successfully.
 func TestGetUserByEmail(t *testing.T) {
 	mockRepo := &MockUserRepository{ShouldFail: false}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookRepository is a mock implementation of a webhook repository for testing purposes.
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+// CreateSubscription mocks storing a subscription.
+func (m *MockWebhookRepository) CreateSubscription(ctx context.Context, subscription *webhook.Subscription) error {
+	args := m.Called(ctx, subscription)
+	return args.Error(0)
+}
+
+// GetSubscription mocks retrieving a subscription by id.
+func (m *MockWebhookRepository) GetSubscription(ctx context.Context, id string) (*webhook.Subscription, error) {
+	args := m.Called(ctx, id)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.Subscription), args.Error(1)
+}
+
+// ListSubscriptions mocks retrieving the active subscriptions.
+func (m *MockWebhookRepository) ListSubscriptions(ctx context.Context) ([]*webhook.Subscription, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*webhook.Subscription), args.Error(1)
+}
+
+// DeactivateSubscription mocks deactivating a subscription.
+func (m *MockWebhookRepository) DeactivateSubscription(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// EnqueueDeliveries mocks queuing an event for a set of subscriptions.
+func (m *MockWebhookRepository) EnqueueDeliveries(ctx context.Context, eventID int64, subscriptionIDs []string) error {
+	args := m.Called(ctx, eventID, subscriptionIDs)
+	return args.Error(0)
+}
+
+// ClaimDueDeliveries mocks leasing a batch of due deliveries.
+func (m *MockWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*webhook.PendingDelivery, error) {
+	args := m.Called(ctx, limit, lease)
+	return args.Get(0).([]*webhook.PendingDelivery), args.Error(1)
+}
+
+// RecordAttempt mocks recording a delivery attempt.
+func (m *MockWebhookRepository) RecordAttempt(ctx context.Context, deliveryID string, attempt *webhook.DeliveryAttempt, status string, nextAttemptAt time.Time) error {
+	args := m.Called(ctx, deliveryID, attempt, status, nextAttemptAt)
+	return args.Error(0)
+}
+
+// ListDeliveries mocks retrieving the deliveries of a subscription.
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*webhook.Delivery, error) {
+	args := m.Called(ctx, subscriptionID, limit)
+	return args.Get(0).([]*webhook.Delivery), args.Error(1)
+}
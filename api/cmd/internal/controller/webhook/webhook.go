@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+	webhookRepo "github.com/koeylp/friends-management/cmd/internal/repository/webhook"
+)
+
+// deliveryHistoryLimit caps how many recent deliveries are returned for a subscription.
+const deliveryHistoryLimit = 50
+
+// WebhookController defines the interface for webhook subscription operations.
+type WebhookController interface {
+	Register(ctx context.Context, req *webhook.CreateSubscriptionRequest) (*webhook.Subscription, error)
+	List(ctx context.Context) ([]*webhook.Subscription, error)
+	Unregister(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, id string) ([]*webhook.Delivery, error)
+}
+
+// webhookControllerImpl implements the WebhookController interface.
+type webhookControllerImpl struct {
+	webhookRepo webhookRepo.WebhookRepository
+}
+
+// NewWebhookController creates a new instance of WebhookController with the provided repository.
+func NewWebhookController(webhookRepo webhookRepo.WebhookRepository) WebhookController {
+	return &webhookControllerImpl{webhookRepo: webhookRepo}
+}
+
+// Register creates a subscription with a freshly generated signing secret.
+// The secret is only ever returned here, so the integrator must store it.
+func (s *webhookControllerImpl) Register(ctx context.Context, req *webhook.CreateSubscriptionRequest) (*webhook.Subscription, error) {
+	secret, err := newSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	subscription := &webhook.Subscription{
+		ID:        uuid.New().String(),
+		URL:       req.URL,
+		Events:    req.Events,
+		Secret:    secret,
+		Active:    true,
+		CreatedAt: time.Now(),
+	}
+	if err := s.webhookRepo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+// List retrieves every active subscription.
+func (s *webhookControllerImpl) List(ctx context.Context) ([]*webhook.Subscription, error) {
+	return s.webhookRepo.ListSubscriptions(ctx)
+}
+
+// Unregister deactivates a subscription. Pending deliveries to it are abandoned.
+func (s *webhookControllerImpl) Unregister(ctx context.Context, id string) error {
+	return s.webhookRepo.DeactivateSubscription(ctx, id)
+}
+
+// ListDeliveries retrieves the recent deliveries of a subscription with their status history.
+func (s *webhookControllerImpl) ListDeliveries(ctx context.Context, id string) ([]*webhook.Delivery, error) {
+	if _, err := s.webhookRepo.GetSubscription(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrWebhookNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to retrieve webhook subscription: %w", err)
+	}
+	return s.webhookRepo.ListDeliveries(ctx, id, deliveryHistoryLimit)
+}
+
+// newSecret returns a random 256-bit hex encoded signing secret.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that registering a webhook stores an active subscription with a generated secret.
+func TestRegister(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockWebhookRepository)
+	ctrl := NewWebhookController(mockRepo)
+
+	req := &webhook.CreateSubscriptionRequest{URL: "https://example.com/hook", Events: []string{"friend.created"}}
+	mockRepo.On("CreateSubscription", ctx, mock.MatchedBy(func(s *webhook.Subscription) bool {
+		return s.URL == req.URL && s.Active && len(s.Secret) == 64 && s.ID != ""
+	})).Return(nil)
+
+	subscription, err := ctrl.Register(ctx, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"friend.created"}, subscription.Events)
+	assert.Len(t, subscription.Secret, 64)
+	mockRepo.AssertExpectations(t)
+
+	// Case: Database error
+	mockRepo.ExpectedCalls = nil
+	mockRepo.On("CreateSubscription", ctx, mock.Anything).Return(errors.New("db error"))
+
+	_, err = ctrl.Register(ctx, req)
+	assert.EqualError(t, err, "failed to create webhook subscription: db error")
+}
+
+// Tests unregistering existing and unknown subscriptions.
+func TestUnregister(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockWebhookRepository)
+	ctrl := NewWebhookController(mockRepo)
+
+	mockRepo.On("DeactivateSubscription", ctx, "sub-1").Return(nil)
+	mockRepo.On("DeactivateSubscription", ctx, "missing").Return(domain.Errorf(domain.ErrWebhookNotFound, "webhook subscription not found with id missing"))
+
+	assert.NoError(t, ctrl.Unregister(ctx, "sub-1"))
+	assert.ErrorIs(t, ctrl.Unregister(ctx, "missing"), domain.ErrWebhookNotFound)
+}
+
+// Tests listing the delivery history of a subscription.
+func TestListDeliveries(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockWebhookRepository)
+	ctrl := NewWebhookController(mockRepo)
+
+	deliveries := []*webhook.Delivery{{ID: "del-1", Status: webhook.StatusSucceeded}}
+	mockRepo.On("GetSubscription", ctx, "sub-1").Return(&webhook.Subscription{ID: "sub-1"}, nil)
+	mockRepo.On("ListDeliveries", ctx, "sub-1", deliveryHistoryLimit).Return(deliveries, nil)
+	mockRepo.On("GetSubscription", ctx, "missing").Return(nil, domain.Errorf(domain.ErrWebhookNotFound, "webhook subscription not found with id missing"))
+
+	result, err := ctrl.ListDeliveries(ctx, "sub-1")
+	assert.NoError(t, err)
+	assert.Equal(t, deliveries, result)
+
+	_, err = ctrl.ListDeliveries(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrWebhookNotFound)
+}
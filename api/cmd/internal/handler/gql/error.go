@@ -0,0 +1,30 @@
+package gql
+
+import (
+	"context"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+)
+
+// resolverError is a field error reported to the client. Its code is exposed in the
+// error's extensions, using the codes the REST API reports, from the domain package.
+type resolverError struct {
+	message string
+	code    string
+}
+
+func (e *resolverError) Error() string {
+	return e.message
+}
+
+// Extensions is read by the GraphQL executor and added to the error in the response.
+func (e *resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+// internalError logs err and hides its details from the client.
+func internalError(ctx context.Context, err error) error {
+	logging.FromContext(ctx).ErrorContext(ctx, "graphql resolver failed", "error", err)
+	return &resolverError{message: "internal server error", code: domain.CodeInternal}
+}
@@ -0,0 +1,59 @@
+package gql
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+	relationshipCtrl "github.com/koeylp/friends-management/cmd/internal/controller/relationship"
+)
+
+// loaders batches the relationship lookups of one query, so resolving a field for every
+// user in a list costs one query instead of one per user.
+type loaders struct {
+	friends     *dataloader.Loader[string, []string]
+	subscribers *dataloader.Loader[string, []string]
+	blocked     *dataloader.Loader[string, []string]
+}
+
+// newLoaders creates the loaders of one query. They cache what they load, so they must not
+// outlive it.
+func newLoaders(relationshipCtrl relationshipCtrl.RelationshipController) *loaders {
+	return &loaders{
+		friends:     dataloader.NewBatchedLoader(batchFunc(relationshipCtrl.GetFriendListsByEmails)),
+		subscribers: dataloader.NewBatchedLoader(batchFunc(relationshipCtrl.GetSubscribersByEmails)),
+		blocked:     dataloader.NewBatchedLoader(batchFunc(relationshipCtrl.GetBlockedByEmails)),
+	}
+}
+
+// batchFunc adapts a lookup keyed by email to a dataloader batch function.
+// Emails absent from the lookup resolve to an empty list.
+func batchFunc(lookup func(ctx context.Context, emails []string) (map[string][]string, error)) dataloader.BatchFunc[string, []string] {
+	return func(ctx context.Context, emails []string) []*dataloader.Result[[]string] {
+		results := make([]*dataloader.Result[[]string], len(emails))
+		related, err := lookup(ctx, emails)
+		for i, email := range emails {
+			if err != nil {
+				results[i] = &dataloader.Result[[]string]{Error: err}
+				continue
+			}
+			emails := related[email]
+			if emails == nil {
+				emails = []string{}
+			}
+			results[i] = &dataloader.Result[[]string]{Data: emails}
+		}
+		return results
+	}
+}
+
+type loadersKey struct{}
+
+// withLoaders returns a copy of ctx carrying l.
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// loadersFrom returns the loaders of the query being executed.
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
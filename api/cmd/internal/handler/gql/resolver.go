@@ -0,0 +1,84 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+)
+
+// resolver resolves the Query type.
+type resolver struct {
+	schema *Schema
+}
+
+// User looks a user up by email. A user that does not exist resolves to null.
+func (r *resolver) User(ctx context.Context, args struct{ Email string }) (*userResolver, error) {
+	found, err := r.schema.userCtrl.GetUserByEmail(ctx, args.Email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, nil
+		}
+		return nil, internalError(ctx, err)
+	}
+	return &userResolver{email: found.Email}, nil
+}
+
+// userResolver resolves the User type. Relationship fields go through the query's loaders.
+type userResolver struct {
+	email string
+}
+
+// Email returns the user's email address.
+func (u *userResolver) Email() string {
+	return u.email
+}
+
+// Friends returns the user's friends.
+func (u *userResolver) Friends(ctx context.Context) ([]*userResolver, error) {
+	return load(ctx, loadersFrom(ctx).friends, u.email)
+}
+
+// CommonFriends returns the friends the user has in common with another user.
+func (u *userResolver) CommonFriends(ctx context.Context, args struct{ With string }) ([]*userResolver, error) {
+	friendsLoader := loadersFrom(ctx).friends
+	friendLists, errs := friendsLoader.LoadMany(ctx, []string{u.email, args.With})()
+	for _, err := range errs {
+		if err != nil {
+			return nil, internalError(ctx, err)
+		}
+	}
+
+	common := make([]*userResolver, 0)
+	for _, email := range friendLists[0] {
+		if slices.Contains(friendLists[1], email) {
+			common = append(common, &userResolver{email: email})
+		}
+	}
+	return common, nil
+}
+
+// Subscribers returns the users subscribed to updates from the user.
+func (u *userResolver) Subscribers(ctx context.Context) ([]*userResolver, error) {
+	return load(ctx, loadersFrom(ctx).subscribers, u.email)
+}
+
+// Blocked returns the users whose updates the user blocks.
+func (u *userResolver) Blocked(ctx context.Context) ([]*userResolver, error) {
+	return load(ctx, loadersFrom(ctx).blocked, u.email)
+}
+
+// load resolves the users related to email through loader.
+func load(ctx context.Context, loader *dataloader.Loader[string, []string], email string) ([]*userResolver, error) {
+	emails, err := loader.Load(ctx, email)()
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	users := make([]*userResolver, len(emails))
+	for i, email := range emails {
+		users[i] = &userResolver{email: email}
+	}
+	return users, nil
+}
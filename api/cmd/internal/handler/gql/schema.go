@@ -0,0 +1,47 @@
+package gql
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/graph-gophers/graphql-go"
+	relationshipCtrl "github.com/koeylp/friends-management/cmd/internal/controller/relationship"
+	userCtrl "github.com/koeylp/friends-management/cmd/internal/controller/user"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+)
+
+//go:embed schema.graphql
+var schemaString string
+
+// maxDepth bounds how deeply queries may nest, since every level of friends can multiply
+// the number of users resolved.
+const maxDepth = 6
+
+// maxParallelism bounds how many fields resolve at once. A loader batches only the lookups
+// made while the others wait, so this is also the largest batch.
+const maxParallelism = 100
+
+// Schema executes GraphQL queries against the controllers.
+type Schema struct {
+	schema           *graphql.Schema
+	userCtrl         userCtrl.UserController
+	relationshipCtrl relationshipCtrl.RelationshipController
+}
+
+// NewSchema parses the GraphQL schema and binds it to the provided controllers.
+func NewSchema(userCtrl userCtrl.UserController, relationshipCtrl relationshipCtrl.RelationshipController) (*Schema, error) {
+	s := &Schema{userCtrl: userCtrl, relationshipCtrl: relationshipCtrl}
+	schema, err := graphql.ParseSchema(schemaString, &resolver{schema: s}, graphql.MaxDepth(maxDepth), graphql.MaxParallelism(maxParallelism))
+	if err != nil {
+		return nil, err
+	}
+	s.schema = schema
+	return s, nil
+}
+
+// Exec runs a query with loaders of its own, so lookups are batched within the query
+// but nothing is cached across queries.
+func (s *Schema) Exec(ctx context.Context, req *graph.QueryRequest) *graphql.Response {
+	ctx = withLoaders(ctx, newLoaders(s.relationshipCtrl))
+	return s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+}
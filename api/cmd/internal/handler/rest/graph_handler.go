@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	graphCtrl "github.com/koeylp/friends-management/cmd/internal/controller/graph"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/middleware"
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+	utils "github.com/koeylp/friends-management/cmd/internal/pkg/error_util"
+	exportUtils "github.com/koeylp/friends-management/cmd/internal/pkg/export_util"
+)
+
+// GraphHandler handles HTTP requests for social graph export.
+type GraphHandler struct {
+	graphCtrl graphCtrl.GraphController
+}
+
+// NewGraphHandler initializes a new GraphHandler with the provided controller.
+func NewGraphHandler(graphCtrl graphCtrl.GraphController) *GraphHandler {
+	return &GraphHandler{graphCtrl: graphCtrl}
+}
+
+// ExportGraphHandler streams the social graph, or one user's ego network, in the requested format.
+func (h *GraphHandler) ExportGraphHandler(w http.ResponseWriter, r *http.Request) {
+	exportReq := middleware.Body[graph.ExportRequest](r)
+
+	w.Header().Set("Content-Type", exportUtils.ContentTypes[exportReq.Format])
+	w.Header().Set("Content-Disposition", "attachment; filename=graph."+exportReq.Format)
+
+	sw := &startedWriter{ResponseWriter: w}
+	err := h.graphCtrl.Export(r.Context(), exportReq, sw)
+	if err != nil {
+		if sw.started {
+			// The status line is already on the wire, so the stream is simply cut short.
+			logging.FromContext(r.Context()).ErrorContext(r.Context(), "graph export aborted", "error", err)
+			return
+		}
+		w.Header().Del("Content-Disposition")
+		utils.HandleError(w, r, err)
+	}
+}
+
+// startedWriter records whether any bytes have been written to the response.
+type startedWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *startedWriter) Write(p []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(p)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test for exporting the social graph.
+func TestExportGraphHandler(t *testing.T) {
+	mockService := &MockGraphService{
+		ExportFunc: func(ctx context.Context, req *graph.ExportRequest, w io.Writer) error {
+			if req.Email == "ghost@example.com" {
+				return domain.Errorf(domain.ErrUserNotFound, "user not found with email %s", req.Email)
+			}
+			_, err := io.WriteString(w, "digraph friends {\n}\n")
+			return err
+		},
+	}
+	handler := setupGraphHandler(mockService)
+
+	tests := []struct {
+		name                string
+		input               graph.ExportRequest
+		expectedStatus      int
+		expectedContentType string
+	}{
+		{
+			name:                "Valid request",
+			input:               graph.ExportRequest{Format: graph.FormatDOT},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/vnd.graphviz",
+		},
+		{
+			name:                "Valid ego network request",
+			input:               graph.ExportRequest{Format: graph.FormatDOT, Email: "user@example.com", Depth: 2},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/vnd.graphviz",
+		},
+		{
+			name:                "Invalid request - unknown format",
+			input:               graph.ExportRequest{Format: "xlsx"},
+			expectedStatus:      http.StatusBadRequest,
+			expectedContentType: "application/problem+json",
+		},
+		{
+			name:                "Invalid request - depth too large",
+			input:               graph.ExportRequest{Format: graph.FormatCSV, Email: "user@example.com", Depth: 50},
+			expectedStatus:      http.StatusBadRequest,
+			expectedContentType: "application/problem+json",
+		},
+		{
+			name:                "User not found",
+			input:               graph.ExportRequest{Format: graph.FormatCSV, Email: "ghost@example.com"},
+			expectedStatus:      http.StatusNotFound,
+			expectedContentType: "application/problem+json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.input)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/graph/export", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			withRequestValidation(http.MethodPost, "/api/v1/graph/export", handler.ExportGraphHandler).ServeHTTP(w, req)
+
+			res := w.Result()
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			assert.Equal(t, tt.expectedContentType, res.Header.Get("Content-Type"))
+		})
+	}
+}
+
+// Test that a failure after streaming has started keeps the original status.
+func TestExportGraphHandler_AbortedStream(t *testing.T) {
+	mockService := &MockGraphService{
+		ExportFunc: func(ctx context.Context, req *graph.ExportRequest, w io.Writer) error {
+			io.WriteString(w, "kind,id,email,source,target,relationship_type,created_at\n")
+			return errors.New("connection reset")
+		},
+	}
+	handler := setupGraphHandler(mockService)
+
+	body, _ := json.Marshal(graph.ExportRequest{Format: graph.FormatCSV})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/graph/export", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	withRequestValidation(http.MethodPost, "/api/v1/graph/export", handler.ExportGraphHandler).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/koeylp/friends-management/cmd/internal/handler/gql"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/middleware"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+)
+
+// GraphQLHandler handles HTTP requests for GraphQL queries.
+type GraphQLHandler struct {
+	schema *gql.Schema
+}
+
+// NewGraphQLHandler initializes a new GraphQLHandler with the provided schema.
+func NewGraphQLHandler(schema *gql.Schema) *GraphQLHandler {
+	return &GraphQLHandler{schema: schema}
+}
+
+// QueryHandler executes a GraphQL query. The result is a standard GraphQL response rather than
+// an envelope, and field errors are reported in it with 200 OK, as GraphQL clients expect.
+func (h *GraphQLHandler) QueryHandler(w http.ResponseWriter, r *http.Request) {
+	queryReq := middleware.Body[graph.QueryRequest](r)
+
+	result := h.schema.Exec(r.Context(), queryReq)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
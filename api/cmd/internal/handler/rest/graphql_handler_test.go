@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// graphQLResponse is the shape of a GraphQL response body.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// postQuery sends a GraphQL query through the handler and decodes the response.
+func postQuery(t *testing.T, handler *GraphQLHandler, req graph.QueryRequest) (*httptest.ResponseRecorder, graphQLResponse) {
+	body, _ := json.Marshal(req)
+	httpReq, _ := http.NewRequest(http.MethodPost, "/api/v1/graphql", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	withRequestValidation(http.MethodPost, "/api/v1/graphql", handler.QueryHandler).ServeHTTP(rr, httpReq)
+
+	var resp graphQLResponse
+	if rr.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	}
+	return rr, resp
+}
+
+// existingUsers looks users up as the user controller would.
+func existingUsers(emails ...string) *MockUserService {
+	return &MockUserService{
+		GetUserByEmailFunc: func(ctx context.Context, email string) (*user.User, error) {
+			for _, existing := range emails {
+				if email == existing {
+					return &user.User{ID: email, Email: email}, nil
+				}
+			}
+			return nil, domain.Errorf(domain.ErrUserNotFound, "user not found with email %s", email)
+		},
+	}
+}
+
+// Test that nested fields over a list of friends are batched into one lookup per field.
+func TestQueryHandler_BatchesNestedFields(t *testing.T) {
+	friends := map[string][]string{
+		"andy@example.com":   {"john@example.com", "kate@example.com", "common@example.com"},
+		"john@example.com":   {"andy@example.com", "common@example.com"},
+		"kate@example.com":   {"andy@example.com"},
+		"common@example.com": {"andy@example.com", "john@example.com"},
+	}
+	var mu sync.Mutex
+	var friendLookups, subscriberLookups [][]string
+	relationshipService := &MockRelationshipService{
+		GetFriendListsByEmailsFunc: func(ctx context.Context, emails []string) (map[string][]string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			friendLookups = append(friendLookups, emails)
+			return friends, nil
+		},
+		GetSubscribersByEmailsFunc: func(ctx context.Context, emails []string) (map[string][]string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			subscriberLookups = append(subscriberLookups, emails)
+			return map[string][]string{"john@example.com": {"andy@example.com"}}, nil
+		},
+	}
+	handler := setupGraphQLHandler(existingUsers("andy@example.com"), relationshipService)
+
+	rr, resp := postQuery(t, handler, graph.QueryRequest{
+		Query: `query($email: String!) {
+			user(email: $email) {
+				email
+				friends { email subscribers { email } commonFriends(with: $email) { email } }
+			}
+		}`,
+		Variables: map[string]interface{}{"email": "andy@example.com"},
+	})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"user": {"email": "andy@example.com", "friends": [
+		{"email": "john@example.com", "subscribers": [{"email": "andy@example.com"}], "commonFriends": [{"email": "common@example.com"}]},
+		{"email": "kate@example.com", "subscribers": [], "commonFriends": []},
+		{"email": "common@example.com", "subscribers": [], "commonFriends": [{"email": "john@example.com"}]}
+	]}}`, string(resp.Data))
+
+	// One lookup for andy's friends, then one for the friends of all three friends at once.
+	assert.Len(t, friendLookups, 2)
+	assert.ElementsMatch(t, []string{"john@example.com", "kate@example.com", "common@example.com"}, friendLookups[1])
+	require.Len(t, subscriberLookups, 1)
+	assert.ElementsMatch(t, []string{"john@example.com", "kate@example.com", "common@example.com"}, subscriberLookups[0])
+}
+
+// Test that an unknown user resolves to null and lookup failures are reported as field errors.
+func TestQueryHandler_Errors(t *testing.T) {
+	relationshipService := &MockRelationshipService{
+		GetBlockedByEmailsFunc: func(ctx context.Context, emails []string) (map[string][]string, error) {
+			return nil, errors.New("connection reset")
+		},
+	}
+	handler := setupGraphQLHandler(existingUsers("andy@example.com"), relationshipService)
+
+	_, resp := postQuery(t, handler, graph.QueryRequest{Query: `{ user(email: "ghost@example.com") { email } }`})
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"user": null}`, string(resp.Data))
+
+	_, resp = postQuery(t, handler, graph.QueryRequest{Query: `{ user(email: "andy@example.com") { blocked { email } } }`})
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "internal server error", resp.Errors[0].Message)
+	assert.Equal(t, "INTERNAL_ERROR", resp.Errors[0].Extensions["code"])
+
+	_, resp = postQuery(t, handler, graph.QueryRequest{Query: `{ user(email: "andy@example.com") { phone } }`})
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, `Cannot query field "phone"`)
+
+	rr, _ := postQuery(t, handler, graph.QueryRequest{})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
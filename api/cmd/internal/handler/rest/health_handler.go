@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/health"
+)
+
+// HealthHandler serves the liveness and readiness probes.
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+// NewHealthHandler initializes a new HealthHandler reporting the checks of checker.
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// LivenessHandler reports that the process is up and serving HTTP. It checks nothing else,
+// so a database outage does not get the process restarted.
+func (h *HealthHandler) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, http.StatusOK, &health.Report{Status: health.StatusOK})
+}
+
+// ReadinessHandler reports whether the application can serve requests, with
+// 503 Service Unavailable and the failed checks when it cannot.
+func (h *HealthHandler) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	report := h.checker.Ready(r.Context())
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	writeReport(w, status, report)
+}
+
+// writeReport writes a health report; probes must not be cached.
+func writeReport(w http.ResponseWriter, status int, report *health.Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/koeylp/friends-management/cmd/internal/infra/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that liveness is reported without checking anything.
+func TestLivenessHandler(t *testing.T) {
+	handler := NewHealthHandler(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	w := httptest.NewRecorder()
+
+	handler.LivenessHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
+
+// Test that an application that is not serving yet is reported unavailable, with its failed checks.
+func TestReadinessHandler_Unavailable(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer db.Close()
+	checker, err := health.NewChecker(db)
+	require.NoError(t, err)
+	handler := NewHealthHandler(checker)
+
+	mock.ExpectPing()
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	w := httptest.NewRecorder()
+
+	handler.ReadinessHandler(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"status":"unavailable"`)
+	assert.Contains(t, w.Body.String(), `"serving":"not serving"`)
+	assert.Contains(t, w.Body.String(), `"database":"ok"`)
+	assert.Contains(t, w.Body.String(), `"migrations":"pending: 000001_db_init`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
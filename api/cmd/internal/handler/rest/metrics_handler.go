@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+)
+
+// MetricsHandler serves the application metrics to Prometheus.
+type MetricsHandler struct {
+	handler http.Handler
+}
+
+// NewMetricsHandler initializes a new MetricsHandler serving m.
+func NewMetricsHandler(m *metrics.Metrics) *MetricsHandler {
+	return &MetricsHandler{handler: m.Handler()}
+}
+
+// MetricsHandler serves the metrics in the Prometheus exposition format.
+func (h *MetricsHandler) MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	h.handler.ServeHTTP(w, r)
+}
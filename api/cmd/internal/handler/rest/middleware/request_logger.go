@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader carries the request ID, both from clients that already have one and back in every response.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestLogger assigns every request an ID and logs the request once it has been served.
+type RequestLogger struct {
+	logger *slog.Logger
+}
+
+// NewRequestLogger creates a new RequestLogger logging through logger.
+func NewRequestLogger(logger *slog.Logger) *RequestLogger {
+	return &RequestLogger{logger: logger}
+}
+
+// Middleware reuses the client's X-Request-ID when it is valid and generates one otherwise.
+// The ID is returned in the response header and put in the request context with a logger
+// that adds it to every record, so controller, repository and SQL logs can be correlated.
+// Within a trace, the trace ID is added as well.
+func (l *RequestLogger) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !logging.ValidRequestID(id) {
+			id = logging.NewRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		ctx := logging.WithRequestID(logging.NewContext(r.Context(), l.logger), id)
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+			ctx = logging.NewContext(ctx, logging.FromContext(ctx).With("trace_id", spanContext.TraceID().String()))
+		}
+		ww := chiMiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		started := time.Now()
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := responseStatus(ww)
+		logging.FromContext(ctx).InfoContext(ctx, "request served",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", status),
+			slog.Int("bytes", ww.BytesWritten()),
+			slog.Duration("duration", time.Since(started)),
+		)
+	})
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that the client's request ID is reused when valid, replaced otherwise, and that it
+// reaches the handler's context and every record logged for the request.
+func TestRequestLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		reused    bool
+	}{
+		{"Client ID", "req-42", true},
+		{"No ID", "", false},
+		{"Invalid ID", "has spaces", false},
+		{"Too long", strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+			var received string
+			handler := NewRequestLogger(logger).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = logging.RequestID(r.Context())
+				logging.FromContext(r.Context()).Info("handled")
+				w.WriteHeader(http.StatusCreated)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/users", nil)
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			id := rr.Header().Get(RequestIDHeader)
+			assert.Equal(t, id, received)
+			if tt.reused {
+				assert.Equal(t, tt.requestID, id)
+			} else {
+				assert.NotEmpty(t, id)
+				assert.NotEqual(t, tt.requestID, id)
+			}
+
+			var records []map[string]interface{}
+			decoder := json.NewDecoder(&logs)
+			for decoder.More() {
+				var record map[string]interface{}
+				require.NoError(t, decoder.Decode(&record))
+				records = append(records, record)
+			}
+			require.Len(t, records, 2)
+			assert.Equal(t, "handled", records[0]["msg"])
+			assert.Equal(t, id, records[0][logging.RequestIDKey])
+			assert.Equal(t, "request served", records[1]["msg"])
+			assert.Equal(t, id, records[1][logging.RequestIDKey])
+			assert.Equal(t, float64(http.StatusCreated), records[1]["status"])
+			assert.Equal(t, "/api/v1/users", records[1]["path"])
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+)
+
+// unmatchedRoute labels requests that matched no route, so unknown paths do not each get a series.
+const unmatchedRoute = "unmatched"
+
+// RequestMetrics records the duration and status of every request, labelled with the matched route pattern.
+type RequestMetrics struct {
+	metrics *metrics.Metrics
+}
+
+// NewRequestMetrics creates a new RequestMetrics recording into m.
+func NewRequestMetrics(m *metrics.Metrics) *RequestMetrics {
+	return &RequestMetrics{metrics: m}
+}
+
+// Middleware records the request once it has been served. The route pattern is read after
+// the router has matched it, so "/api/v2/users/{email}/friends" is one series for every user.
+func (m *RequestMetrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := chiMiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		started := time.Now()
+		next.ServeHTTP(ww, r)
+
+		m.metrics.ObserveRequest(r.Method, routePattern(r), responseStatus(ww), time.Since(started))
+	})
+}
+
+// routePattern returns the pattern of the route the request matched, or unmatchedRoute.
+// It is only complete once the router has served the request.
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		return rctx.RoutePattern()
+	}
+	return unmatchedRoute
+}
+
+// responseStatus returns the status written to w, which is 200 OK when the handler wrote none.
+func responseStatus(w chiMiddleware.WrapResponseWriter) int {
+	if status := w.Status(); status != 0 {
+		return status
+	}
+	return http.StatusOK
+}
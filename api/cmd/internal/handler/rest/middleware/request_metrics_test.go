@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that requests are recorded under their route pattern, and unknown paths under one label.
+func TestRequestMetrics(t *testing.T) {
+	m := metrics.New()
+	r := chi.NewRouter()
+	r.Use(NewRequestMetrics(m).Middleware)
+	r.Get("/api/v2/users/{email}/friends", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, path := range []string{"/api/v2/users/andy@example.com/friends", "/api/v2/users/john@example.com/friends", "/unknown"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rr := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rr.Body.String()
+	assert.Contains(t, body, `friends_http_request_duration_seconds_count{method="GET",route="/api/v2/users/{email}/friends",status="200"} 2`)
+	assert.Contains(t, body, `friends_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+	assert.False(t, strings.Contains(body, "andy@example.com"))
+}
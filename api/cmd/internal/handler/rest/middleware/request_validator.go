@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/openapi"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/response"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	utils "github.com/koeylp/friends-management/cmd/internal/pkg/error_util"
+	validation "github.com/koeylp/friends-management/cmd/internal/pkg/validation_util"
+)
+
+// bodyKey is the context key of the validated request body.
+type bodyKey struct{}
+
+// RequestValidator decodes and validates the JSON body of every route whose OpenAPI description
+// has a request body, so handlers receive a DTO that already passed its validate tags.
+type RequestValidator struct {
+	bodies       map[string]reflect.Type
+	maxBodyBytes int64
+}
+
+// NewRequestValidator builds a validator for the request bodies described by openapi.Routes.
+func NewRequestValidator(cfg *config.ServerConfig) *RequestValidator {
+	bodies := make(map[string]reflect.Type)
+	for _, route := range openapi.Routes {
+		if route.Request == nil {
+			continue
+		}
+		bodies[routeKey(route.Method, route.Path)] = reflect.TypeOf(route.Request)
+	}
+	return &RequestValidator{bodies: bodies, maxBodyBytes: cfg.MaxBodyBytes}
+}
+
+// Middleware validates the body of the matched route before calling next. It rejects bodies
+// larger than the configured limit, bodies that are not a single JSON object of the request
+// type, fields the request type does not have, and values failing its validate tags.
+// Requests to routes without a request body are passed through untouched.
+func (v *RequestValidator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyType, ok := v.bodies[routeKey(r.Method, matchedPattern(r))]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body := reflect.New(bodyType).Interface()
+		if err := v.decode(w, r, body); err != nil {
+			utils.HandleError(w, r, err)
+			return
+		}
+		if err := validation.Validator().Struct(body); err != nil {
+			utils.HandleError(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), bodyKey{}, body)))
+	})
+}
+
+// decode reads exactly one JSON object from the request body into body.
+func (v *RequestValidator) decode(w http.ResponseWriter, r *http.Request, body interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, v.maxBodyBytes))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(body); err != nil {
+		return decodeError(err)
+	}
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return decodeError(err)
+		}
+		return response.NewInvalidPayloadError("Request body must contain a single JSON object")
+	}
+	return nil
+}
+
+// decodeError translates a JSON decoding failure into a problem naming the field at fault when there is one.
+func decodeError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return response.NewPayloadTooLargeError(fmt.Sprintf("Request body must not be larger than %d bytes", maxBytesErr.Limit))
+	case errors.Is(err, io.EOF):
+		return response.NewInvalidPayloadError("Request body is required")
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return response.NewInvalidPayloadError("Request body is not valid JSON")
+	case errors.As(err, &typeErr):
+		message := fmt.Sprintf("%s must be a %s", typeErr.Field, jsonType(typeErr.Type))
+		if typeErr.Field == "" {
+			message = "Request body must be a JSON object"
+		}
+		return response.NewInvalidPayloadError(message, validation.FieldError{Field: typeErr.Field, Rule: "type", Message: message})
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for unknown fields.
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		message := field + " is not a known field"
+		return response.NewInvalidPayloadError(message, validation.FieldError{Field: field, Rule: "unknown", Message: message})
+	default:
+		return response.NewInvalidPayloadError("Invalid request payload")
+	}
+}
+
+// jsonType names the JSON type a Go type is decoded from.
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return "number"
+	}
+}
+
+// Body returns the validated request body stored by the RequestValidator. It panics when the
+// route has no request body in openapi.Routes, since the handler would otherwise read an
+// unvalidated request.
+func Body[T any](r *http.Request) *T {
+	body, ok := r.Context().Value(bodyKey{}).(*T)
+	if !ok {
+		panic(fmt.Sprintf("middleware: no validated %T body for %s %s", body, r.Method, r.URL.Path))
+	}
+	return body
+}
+
+// matchedPattern returns the pattern of the route r is sent to, without a trailing slash.
+// The middleware runs before chi routes the request, so the route is matched here.
+func matchedPattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return ""
+	}
+	match := chi.NewRouteContext()
+	if !rctx.Routes.Match(match, r.Method, r.URL.Path) {
+		return ""
+	}
+	return match.RoutePattern()
+}
+
+func routeKey(method, pattern string) string {
+	return method + " " + strings.TrimSuffix(pattern, "/")
+}
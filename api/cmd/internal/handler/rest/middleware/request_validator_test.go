@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/block"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupRouter routes the block and webhook list endpoints through the request validator.
+// The block handler echoes the validated body back.
+func setupRouter(maxBodyBytes int64) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(NewRequestValidator(&config.ServerConfig{MaxBodyBytes: maxBodyBytes}).Middleware)
+	r.Route("/api/v1/block", func(r chi.Router) {
+		r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(Body[block.BlockRequest](r))
+		})
+	})
+	r.Get("/api/v1/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return r
+}
+
+func TestRequestValidator(t *testing.T) {
+	r := setupRouter(128)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedCode   string
+		expectedField  string
+		expectedRule   string
+	}{
+		{
+			name:           "Valid request",
+			body:           `{"requestor":"andy@example.com","target":"john@example.com"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unknown field",
+			body:           `{"requestor":"andy@example.com","target":"john@example.com","reason":"spam"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_PAYLOAD",
+			expectedField:  "reason",
+			expectedRule:   "unknown",
+		},
+		{
+			name:           "Wrong type",
+			body:           `{"requestor":"andy@example.com","target":42}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_PAYLOAD",
+			expectedField:  "target",
+			expectedRule:   "type",
+		},
+		{
+			name:           "Malformed JSON",
+			body:           `{"requestor":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_PAYLOAD",
+		},
+		{
+			name:           "Empty body",
+			body:           ``,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_PAYLOAD",
+		},
+		{
+			name:           "Trailing data",
+			body:           `{"requestor":"andy@example.com","target":"john@example.com"}{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_PAYLOAD",
+		},
+		{
+			name:           "Same requestor and target",
+			body:           `{"requestor":"andy@example.com","target":"andy@example.com"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "VALIDATION_FAILED",
+			expectedField:  "target",
+			expectedRule:   "nefield",
+		},
+		{
+			name:           "Too large",
+			body:           `{"requestor":"` + strings.Repeat("a", 128) + `@example.com","target":"john@example.com"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedCode:   "PAYLOAD_TOO_LARGE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/block", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedCode == "" {
+				assert.JSONEq(t, tt.body, w.Body.String())
+				return
+			}
+
+			var problem struct {
+				Code   string `json:"code"`
+				Errors []struct {
+					Field string `json:"field"`
+					Rule  string `json:"rule"`
+				} `json:"errors"`
+			}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+			assert.Equal(t, tt.expectedCode, problem.Code)
+			if tt.expectedField != "" {
+				require.Len(t, problem.Errors, 1)
+				assert.Equal(t, tt.expectedField, problem.Errors[0].Field)
+				assert.Equal(t, tt.expectedRule, problem.Errors[0].Rule)
+			}
+		})
+	}
+}
+
+// Test that routes without a request body in the OpenAPI document are passed through.
+func TestRequestValidator_NoBody(t *testing.T) {
+	r := setupRouter(128)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
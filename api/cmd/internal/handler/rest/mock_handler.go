@@ -2,28 +2,45 @@ package handlers
 
 import (
 	"context"
+	"io"
+	"net/http"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/koeylp/friends-management/cmd/internal/handler/gql"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/middleware"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/block"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/subscription"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
 )
 
 // MockRelationshipService is a mock implementation of a relationship service for testing purposes.
 // It allows defining custom behaviors for its methods using function types.
 type MockRelationshipService struct {
 	CreateFriendFunc               func(ctx context.Context, req *friend.CreateFriend) error
+	UnfriendFunc                   func(ctx context.Context, req *friend.CreateFriend) error
 	GetFriendListByEmailFunc       func(ctx context.Context, email string) ([]string, error)
 	GetCommonListFunc              func(ctx context.Context, req *friend.CommonFriendListReq) ([]string, error)
 	SubscribeFunc                  func(ctx context.Context, req *subscription.SubscribeRequest) error
+	UnsubscribeFunc                func(ctx context.Context, req *subscription.SubscribeRequest) error
 	BlockUpdatesFunc               func(ctx context.Context, req *block.BlockRequest) error
+	UnblockUpdatesFunc             func(ctx context.Context, req *block.BlockRequest) error
 	GetUpdatableEmailAddressesFunc func(ctx context.Context, req *subscription.RecipientRequest) ([]string, error)
+	PostUpdateFunc                 func(ctx context.Context, req *subscription.RecipientRequest) ([]string, error)
+	GetFriendListsByEmailsFunc     func(ctx context.Context, emails []string) (map[string][]string, error)
+	GetSubscribersByEmailsFunc     func(ctx context.Context, emails []string) (map[string][]string, error)
+	GetBlockedByEmailsFunc         func(ctx context.Context, emails []string) (map[string][]string, error)
 }
 
 // MockUserService is a mock implementation of a user service for testing purposes.
 // It allows defining custom behavior for user-related methods.
 type MockUserService struct {
-	CreateUserFunc func(ctx context.Context, req *user.CreateUser) error
+	CreateUserFunc     func(ctx context.Context, req *user.CreateUser) error
+	GetUserByEmailFunc func(ctx context.Context, email string) (*user.User, error)
 }
 
 // setupRelationshipHandler initializes a RelationshipHandler with the provided mock relationship service.
@@ -36,10 +53,14 @@ func (m *MockRelationshipService) CreateFriend(ctx context.Context, req *friend.
 	return m.CreateFriendFunc(ctx, req)
 }
 
-// GetUserByEmail is a method that needs to be implemented in the MockUserService.
-// Currently, it panics if called, indicating it's not intended for use in this mock.
+// Unfriend calls the custom UnfriendFunc defined in the MockRelationshipService.
+func (m *MockRelationshipService) Unfriend(ctx context.Context, req *friend.CreateFriend) error {
+	return m.UnfriendFunc(ctx, req)
+}
+
+// GetUserByEmail calls the custom GetUserByEmailFunc defined in the MockUserService.
 func (m *MockUserService) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
-	panic("unimplemented")
+	return m.GetUserByEmailFunc(ctx, email)
 }
 
 // CreateUser calls the custom CreateUserFunc defined in the MockUserService.
@@ -67,6 +88,16 @@ func (m *MockRelationshipService) Subscribe(ctx context.Context, req *subscripti
 	return m.SubscribeFunc(ctx, req)
 }
 
+// Unsubscribe calls the custom UnsubscribeFunc defined in the MockRelationshipService.
+func (m *MockRelationshipService) Unsubscribe(ctx context.Context, req *subscription.SubscribeRequest) error {
+	return m.UnsubscribeFunc(ctx, req)
+}
+
+// UnblockUpdates calls the custom UnblockUpdatesFunc defined in the MockRelationshipService.
+func (m *MockRelationshipService) UnblockUpdates(ctx context.Context, req *block.BlockRequest) error {
+	return m.UnblockUpdatesFunc(ctx, req)
+}
+
 // BlockUpdates calls the custom BlockUpdatesFunc defined in the MockRelationshipService.
 func (m *MockRelationshipService) BlockUpdates(ctx context.Context, req *block.BlockRequest) error {
 	return m.BlockUpdatesFunc(ctx, req)
@@ -76,3 +107,89 @@ func (m *MockRelationshipService) BlockUpdates(ctx context.Context, req *block.B
 func (m *MockRelationshipService) GetUpdatableEmailAddresses(ctx context.Context, req *subscription.RecipientRequest) ([]string, error) {
 	return m.GetUpdatableEmailAddressesFunc(ctx, req)
 }
+
+// PostUpdate calls the custom PostUpdateFunc defined in the MockRelationshipService.
+func (m *MockRelationshipService) PostUpdate(ctx context.Context, req *subscription.RecipientRequest) ([]string, error) {
+	return m.PostUpdateFunc(ctx, req)
+}
+
+// GetFriendListsByEmails calls the custom GetFriendListsByEmailsFunc defined in the MockRelationshipService.
+func (m *MockRelationshipService) GetFriendListsByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	return m.GetFriendListsByEmailsFunc(ctx, emails)
+}
+
+// GetSubscribersByEmails calls the custom GetSubscribersByEmailsFunc defined in the MockRelationshipService.
+func (m *MockRelationshipService) GetSubscribersByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	return m.GetSubscribersByEmailsFunc(ctx, emails)
+}
+
+// GetBlockedByEmails calls the custom GetBlockedByEmailsFunc defined in the MockRelationshipService.
+func (m *MockRelationshipService) GetBlockedByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	return m.GetBlockedByEmailsFunc(ctx, emails)
+}
+
+// MockGraphService is a mock implementation of a graph service for testing purposes.
+type MockGraphService struct {
+	ExportFunc func(ctx context.Context, req *graph.ExportRequest, w io.Writer) error
+}
+
+// Export calls the custom ExportFunc defined in the MockGraphService.
+func (m *MockGraphService) Export(ctx context.Context, req *graph.ExportRequest, w io.Writer) error {
+	return m.ExportFunc(ctx, req, w)
+}
+
+// setupGraphHandler initializes a GraphHandler with the provided mock graph service.
+func setupGraphHandler(mockService *MockGraphService) *GraphHandler {
+	return NewGraphHandler(mockService)
+}
+
+// setupGraphQLHandler initializes a GraphQLHandler whose schema resolves through the provided mock services.
+func setupGraphQLHandler(userService *MockUserService, relationshipService *MockRelationshipService) *GraphQLHandler {
+	schema, err := gql.NewSchema(userService, relationshipService)
+	if err != nil {
+		panic(err)
+	}
+	return NewGraphQLHandler(schema)
+}
+
+// MockWebhookService is a mock implementation of a webhook service for testing purposes.
+type MockWebhookService struct {
+	RegisterFunc       func(ctx context.Context, req *webhook.CreateSubscriptionRequest) (*webhook.Subscription, error)
+	ListFunc           func(ctx context.Context) ([]*webhook.Subscription, error)
+	UnregisterFunc     func(ctx context.Context, id string) error
+	ListDeliveriesFunc func(ctx context.Context, id string) ([]*webhook.Delivery, error)
+}
+
+// Register calls the custom RegisterFunc defined in the MockWebhookService.
+func (m *MockWebhookService) Register(ctx context.Context, req *webhook.CreateSubscriptionRequest) (*webhook.Subscription, error) {
+	return m.RegisterFunc(ctx, req)
+}
+
+// List calls the custom ListFunc defined in the MockWebhookService.
+func (m *MockWebhookService) List(ctx context.Context) ([]*webhook.Subscription, error) {
+	return m.ListFunc(ctx)
+}
+
+// Unregister calls the custom UnregisterFunc defined in the MockWebhookService.
+func (m *MockWebhookService) Unregister(ctx context.Context, id string) error {
+	return m.UnregisterFunc(ctx, id)
+}
+
+// ListDeliveries calls the custom ListDeliveriesFunc defined in the MockWebhookService.
+func (m *MockWebhookService) ListDeliveries(ctx context.Context, id string) ([]*webhook.Delivery, error) {
+	return m.ListDeliveriesFunc(ctx, id)
+}
+
+// setupWebhookHandler initializes a WebhookHandler with the provided mock webhook service.
+func setupWebhookHandler(mockService *MockWebhookService) *WebhookHandler {
+	return NewWebhookHandler(mockService)
+}
+
+// withRequestValidation routes requests for pattern to h through the request validator,
+// as the router does, so the handler receives a validated body.
+func withRequestValidation(method, pattern string, h http.HandlerFunc) http.Handler {
+	r := chi.NewRouter()
+	r.Use(middleware.NewRequestValidator(&config.ServerConfig{MaxBodyBytes: 1 << 20}).Middleware)
+	r.Method(method, pattern, h)
+	return r
+}
@@ -0,0 +1,221 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/health"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/block"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/subscription"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+	utils "github.com/koeylp/friends-management/cmd/internal/pkg/export_util"
+)
+
+// Routes describes every route registered by the router. The router test fails when
+// the two disagree, so a new route must be described here.
+var Routes = []Route{
+	{
+		Method: http.MethodPost, Path: "/api/v1/users", ID: "createUser", Tag: "users",
+		Summary: "Create a user",
+		Request: user.CreateUser{}, Status: http.StatusCreated,
+		Errors: []int{http.StatusBadRequest, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/friends", ID: "createFriend", Tag: "friends",
+		Summary: "Create a friend connection between two users",
+		Request: friend.CreateFriend{}, Status: http.StatusCreated,
+		Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/friends/list", ID: "listFriends", Tag: "friends",
+		Summary: "Retrieve the friends list for an email address",
+		Request: friend.EmailRequest{}, Status: http.StatusOK, Response: []string{},
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/friends/common-list", ID: "listCommonFriends", Tag: "friends",
+		Summary: "Retrieve the common friends list between two email addresses",
+		Request: friend.CommonFriendListReq{}, Status: http.StatusOK, Response: []string{},
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/subscription", ID: "subscribe", Tag: "subscriptions",
+		Summary: "Subscribe to updates from an email address",
+		Request: subscription.SubscribeRequest{}, Status: http.StatusCreated,
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/subscription/recipients", ID: "listRecipients", Tag: "subscriptions",
+		Summary: "Retrieve every email address that can receive updates from a sender; nothing is recorded, see postUpdate",
+		Request: subscription.RecipientRequest{}, Status: http.StatusOK, Response: []string{},
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/subcription", ID: "subscribeMisspelled", Tag: "subscriptions",
+		Summary: "Subscribe to updates from an email address; use /api/v1/subscription instead",
+		Request: subscription.SubscribeRequest{}, Status: http.StatusCreated,
+		Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+		Deprecated: true,
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/subcription/recipients", ID: "listRecipientsMisspelled", Tag: "subscriptions",
+		Summary: "Retrieve every email address that can receive updates from a sender; nothing is recorded. Use /api/v1/subscription/recipients instead",
+		Request: subscription.RecipientRequest{}, Status: http.StatusOK, Response: []string{},
+		Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		Deprecated: true,
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/updates", ID: "postUpdate", Tag: "subscriptions",
+		Summary: "Post an update from a sender and retrieve its recipients; the update is recorded as an update.posted event for webhooks and the event stream",
+		Request: subscription.RecipientRequest{}, Status: http.StatusOK, Response: []string{},
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/block", ID: "blockUpdates", Tag: "blocks",
+		Summary: "Block updates from an email address",
+		Request: block.BlockRequest{}, Status: http.StatusCreated,
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/graph/export", ID: "exportGraph", Tag: "graph",
+		Summary: "Stream the social graph, or one user's ego network, as a file",
+		Request: graph.ExportRequest{}, Status: http.StatusOK, Produces: exportMediaTypes(),
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/graphql", ID: "queryGraphQL", Tag: "graph",
+		Summary: "Run a GraphQL query over users and their relationships; field errors are reported in the GraphQL response",
+		Request: graph.QueryRequest{}, Status: http.StatusOK, Produces: []string{"application/json"},
+		Errors: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/webhooks", ID: "registerWebhook", Tag: "webhooks",
+		Summary: "Register a webhook subscription; the signing secret is only returned here",
+		Request: webhook.CreateSubscriptionRequest{}, Status: http.StatusCreated, Response: webhook.Subscription{},
+		Errors: []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/webhooks", ID: "listWebhooks", Tag: "webhooks",
+		Summary: "List active webhook subscriptions",
+		Status:  http.StatusOK, Response: []webhook.Subscription{},
+		Errors: []int{http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/webhooks/{id}", ID: "unregisterWebhook", Tag: "webhooks",
+		Summary:    "Unregister a webhook subscription",
+		Parameters: []*Parameter{idParameter()},
+		Status:     http.StatusOK,
+		Errors:     []int{http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/webhooks/{id}/deliveries", ID: "listWebhookDeliveries", Tag: "webhooks",
+		Summary:    "List the recent deliveries of a webhook subscription with their attempt history",
+		Parameters: []*Parameter{idParameter()},
+		Status:     http.StatusOK, Response: []webhook.Delivery{},
+		Errors: []int{http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/events/stream", ID: "streamEvents", Tag: "events",
+		Summary: "Stream the events addressed to a user as server-sent events",
+		Parameters: []*Parameter{
+			{Name: "email", In: "query", Required: true, Schema: &Schema{Type: "string", Format: "email"}},
+			{Name: "last_event_id", In: "query", Description: "Resume after this event id.", Schema: &Schema{Type: "integer", Format: "int64"}},
+			{Name: "Last-Event-ID", In: "header", Description: "Resume after this event id; sent by browsers on reconnect.", Schema: &Schema{Type: "integer", Format: "int64"}},
+		},
+		Status: http.StatusOK, Produces: []string{"text/event-stream"},
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/openapi.json", ID: "getOpenAPI", Tag: "docs",
+		Summary: "This OpenAPI document",
+		Status:  http.StatusOK, Produces: []string{"application/json"},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/docs", ID: "getDocs", Tag: "docs",
+		Summary: "Interactive API documentation",
+		Status:  http.StatusOK, Produces: []string{"text/html"},
+	},
+	{
+		Method: http.MethodGet, Path: "/metrics", ID: "getMetrics", Tag: "operations",
+		Summary: "Prometheus metrics: request and repository call durations, connection pool usage and business counters",
+		Status:  http.StatusOK, Produces: []string{"text/plain"},
+	},
+	{
+		Method: http.MethodGet, Path: "/healthz", ID: "getLiveness", Tag: "operations",
+		Summary: "Liveness probe: the process is up and serving HTTP",
+		Status:  http.StatusOK, Response: health.Report{}, Plain: true,
+	},
+	{
+		Method: http.MethodGet, Path: "/readyz", ID: "getReadiness", Tag: "operations",
+		Summary: "Readiness probe: the database answers, its migrations are applied and the application is not shutting down. The failed checks are returned with 503",
+		Status:  http.StatusOK, Response: health.Report{}, Plain: true,
+		Errors: []int{http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v2/users/{email}/friends", ID: "listUserFriends", Tag: "users",
+		Summary:    "Retrieve the friends of a user",
+		Parameters: []*Parameter{emailParameter("email", "The user's email address.")},
+		Status:     http.StatusOK, Response: []string{},
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v2/users/{email}/common-friends/{other}", ID: "listUserCommonFriends", Tag: "users",
+		Summary:    "Retrieve the friends two users have in common",
+		Parameters: []*Parameter{emailParameter("email", "The user's email address."), emailParameter("other", "The other user's email address.")},
+		Status:     http.StatusOK, Response: []string{},
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v2/users/{email}/subscriptions/{target}", ID: "putUserSubscription", Tag: "users",
+		Summary:    "Subscribe a user to updates from the target; answers 200 OK when already subscribed",
+		Parameters: targetParameters("The email address to receive updates from."),
+		Status:     http.StatusCreated,
+		Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v2/users/{email}/subscriptions/{target}", ID: "deleteUserSubscription", Tag: "users",
+		Summary:    "Unsubscribe a user from updates from the target",
+		Parameters: targetParameters("The email address to stop receiving updates from."),
+		Status:     http.StatusOK,
+		Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v2/users/{email}/blocks/{target}", ID: "putUserBlock", Tag: "users",
+		Summary:    "Block updates from the target; answers 200 OK when already blocked",
+		Parameters: targetParameters("The email address to block."),
+		Status:     http.StatusCreated,
+		Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v2/users/{email}/blocks/{target}", ID: "deleteUserBlock", Tag: "users",
+		Summary:    "Stop blocking updates from the target",
+		Parameters: targetParameters("The email address to unblock."),
+		Status:     http.StatusOK,
+		Errors:     []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+}
+
+func emailParameter(name, description string) *Parameter {
+	return &Parameter{Name: name, In: "path", Required: true, Description: description, Schema: &Schema{Type: "string", Format: "email"}}
+}
+
+func targetParameters(targetDescription string) []*Parameter {
+	return []*Parameter{emailParameter("email", "The requestor's email address."), emailParameter("target", targetDescription)}
+}
+
+func idParameter() *Parameter {
+	return &Parameter{Name: "id", In: "path", Required: true, Description: "Webhook subscription id.", Schema: &Schema{Type: "string"}}
+}
+
+// exportMediaTypes returns the media types of every export format.
+func exportMediaTypes() []string {
+	mediaTypes := make([]string, 0, len(utils.ContentTypes))
+	for _, mediaType := range utils.ContentTypes {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	return mediaTypes
+}
@@ -0,0 +1,167 @@
+package openapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/response"
+)
+
+// Document is an OpenAPI 3 document.
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Route describes one endpoint. Request and Response are example values whose types are
+// reflected into schemas: Request is the JSON body, Response the payload sent in the envelope's
+// data (a slice payload also gets the list meta). Routes that do not answer with an envelope
+// list their media types in Produces instead, or set Plain when they answer with Response as
+// is, error statuses included. The request validator middleware decodes and validates Request
+// before the handler runs.
+type Route struct {
+	Method     string
+	Path       string
+	ID         string
+	Summary    string
+	Tag        string
+	Parameters []*Parameter
+	Request    interface{}
+	Status     int
+	Response   interface{}
+	Produces   []string
+	Plain      bool
+	Errors     []int
+	Deprecated bool
+}
+
+// Build generates the document describing routes.
+func Build(routes []Route) *Document {
+	s := newSchemas()
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "Friends Management API",
+			Version:     "1.0.0",
+			Description: "Manage friendships, subscriptions and blocks between users. Errors are RFC 7807 problems.",
+		},
+		Paths: make(map[string]map[string]*Operation),
+	}
+
+	for _, route := range routes {
+		op := &Operation{
+			OperationID: route.ID,
+			Summary:     route.Summary,
+			Tags:        []string{route.Tag},
+			Parameters:  route.Parameters,
+			Responses:   make(map[string]*Response),
+			Deprecated:  route.Deprecated,
+		}
+		if route.Request != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{"application/json": {Schema: s.of(route.Request)}},
+			}
+		}
+
+		success := &Response{Description: http.StatusText(route.Status), Content: make(map[string]*MediaType)}
+		if len(route.Produces) > 0 {
+			for _, mediaType := range route.Produces {
+				success.Content[mediaType] = &MediaType{Schema: &Schema{Type: "string"}}
+			}
+		} else if route.Plain {
+			success.Content["application/json"] = &MediaType{Schema: s.of(route.Response)}
+		} else {
+			success.Content["application/json"] = &MediaType{Schema: envelope(s, route.Response)}
+		}
+		op.Responses[strconv.Itoa(route.Status)] = success
+
+		errors := route.Errors
+		if route.Request != nil {
+			// Bodies are checked against the server's size limit before the handler runs.
+			errors = append(errors[:len(errors):len(errors)], http.StatusRequestEntityTooLarge)
+		}
+		for _, status := range errors {
+			content := map[string]*MediaType{response.ProblemContentType: {Schema: s.of(response.ErrorResponse{})}}
+			if route.Plain {
+				content = success.Content
+			}
+			op.Responses[strconv.Itoa(status)] = &Response{Description: http.StatusText(status), Content: content}
+		}
+
+		path := strings.TrimSuffix(route.Path, "/")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*Operation)
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = op
+	}
+
+	doc.Components.Schemas = s.components
+	return doc
+}
+
+// envelope describes a response.Envelope carrying the given payload.
+func envelope(s *schemas, payload interface{}) *Schema {
+	if payload == nil {
+		payload = response.Empty{}
+	}
+	data := s.of(payload)
+	schema := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"success": {Type: "boolean"},
+			"data":    data,
+		},
+		Required: []string{"success", "data"},
+	}
+	if data.Type == "array" {
+		schema.Properties["meta"] = s.of(response.Meta{})
+		schema.Required = append(schema.Required, "meta")
+	}
+	return schema
+}
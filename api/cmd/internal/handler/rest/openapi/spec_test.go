@@ -0,0 +1,76 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuild_RequestSchemasFollowValidateTags tests that request schemas carry the constraints of the DTO validate tags.
+func TestBuild_RequestSchemasFollowValidateTags(t *testing.T) {
+	doc := Build([]Route{
+		{Method: http.MethodPost, Path: "/webhooks", Request: webhook.CreateSubscriptionRequest{}, Status: http.StatusCreated},
+		{Method: http.MethodPost, Path: "/export", Request: graph.ExportRequest{}, Status: http.StatusOK, Produces: []string{"text/csv"}},
+		{Method: http.MethodPost, Path: "/friends", Request: friend.CreateFriend{}, Status: http.StatusCreated},
+	})
+	schemas := doc.Components.Schemas
+
+	subscription := schemas["CreateSubscriptionRequest"]
+	require.NotNil(t, subscription)
+	assert.ElementsMatch(t, []string{"url", "events"}, subscription.Required)
+	assert.Equal(t, "uri", subscription.Properties["url"].Format)
+	events := subscription.Properties["events"]
+	assert.Equal(t, "array", events.Type)
+	assert.Equal(t, 1, *events.MinItems)
+	assert.Contains(t, events.Items.Enum, "friend.created")
+
+	export := schemas["ExportRequest"]
+	require.NotNil(t, export)
+	assert.Equal(t, []string{"format"}, export.Required)
+	assert.Equal(t, []string{"ndjson", "csv", "graphml", "dot"}, export.Properties["format"].Enum)
+	assert.Equal(t, "email", export.Properties["email"].Format)
+	assert.Equal(t, 1.0, *export.Properties["depth"].Minimum)
+	assert.Equal(t, 5.0, *export.Properties["depth"].Maximum)
+
+	friends := schemas["CreateFriend"].Properties["friends"]
+	assert.Equal(t, "email", friends.Items.Format)
+}
+
+// TestBuild_Responses tests that payloads are wrapped in the envelope and errors are problems.
+func TestBuild_Responses(t *testing.T) {
+	doc := Build([]Route{
+		{Method: http.MethodGet, Path: "/webhooks/", Status: http.StatusOK, Response: []webhook.Subscription{}, Errors: []int{http.StatusInternalServerError}},
+	})
+
+	op := doc.Paths["/webhooks"]["get"]
+	require.NotNil(t, op)
+
+	body := op.Responses["200"].Content["application/json"].Schema
+	assert.Equal(t, "#/components/schemas/Subscription", body.Properties["data"].Items.Ref)
+	assert.Equal(t, "#/components/schemas/Meta", body.Properties["meta"].Ref)
+
+	problem := op.Responses["500"].Content["application/problem+json"].Schema
+	assert.Equal(t, "#/components/schemas/ErrorResponse", problem.Ref)
+	assert.Contains(t, doc.Components.Schemas["ErrorResponse"].Properties, "code")
+}
+
+// Test that plain routes describe their payload as is, for error statuses too.
+func TestBuild_PlainResponses(t *testing.T) {
+	type report struct {
+		Status string `json:"status"`
+	}
+	doc := Build([]Route{
+		{Method: http.MethodGet, Path: "/readyz", Status: http.StatusOK, Response: report{}, Plain: true, Errors: []int{http.StatusServiceUnavailable}},
+	})
+
+	op := doc.Paths["/readyz"]["get"]
+	require.NotNil(t, op)
+	assert.Equal(t, "#/components/schemas/report", op.Responses["200"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/report", op.Responses["503"].Content["application/json"].Schema.Ref)
+	assert.NotContains(t, op.Responses["503"].Content, "application/problem+json")
+}
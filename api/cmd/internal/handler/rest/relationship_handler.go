@@ -1,11 +1,10 @@
 package handlers
 
 import (
-	"context"
-	"encoding/json"
 	"net/http"
 
 	relationshipCtrl "github.com/koeylp/friends-management/cmd/internal/controller/relationship"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/middleware"
 	"github.com/koeylp/friends-management/cmd/internal/handler/rest/response"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/block"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
@@ -25,138 +24,99 @@ func NewRelationshipHandler(relationshipCtrl relationshipCtrl.RelationshipContro
 
 // CreateFriendHandler handles the creation of a friendship relationship.
 func (h *RelationshipHandler) CreateFriendHandler(w http.ResponseWriter, r *http.Request) {
-	var createFriendReq friend.CreateFriend
-	err := json.NewDecoder(r.Body).Decode(&createFriendReq)
-	if err != nil || len(createFriendReq.Friends) != 2 || createFriendReq.Friends[0] == createFriendReq.Friends[1] {
-		response.NewBadRequestError("Invalid request payload").Send(w)
-		return
-	}
-	if err := friend.ValidateCreateFriendRequest(&createFriendReq); err != nil {
-		response.NewBadRequestError(err.Error()).Send(w)
-		return
-	}
+	createFriendReq := middleware.Body[friend.CreateFriend](r)
 
-	err = h.relationshipCtrl.CreateFriend(context.Background(), &createFriendReq)
+	err := h.relationshipCtrl.CreateFriend(r.Context(), createFriendReq)
 	if err != nil {
-		utils.HandleError(w, err)
+		utils.HandleError(w, r, err)
 		return
 	}
 
-	createdResponse := response.NewCREATED(nil)
+	createdResponse := response.NewCREATED(response.Empty{})
 	createdResponse.Send(w)
 }
 
 // GetFriendListByEmailHandler handles retrieving a friend list by user email.
 func (h *RelationshipHandler) GetFriendListByEmailHandler(w http.ResponseWriter, r *http.Request) {
-	var emailReq friend.EmailRequest
-	err := json.NewDecoder(r.Body).Decode(&emailReq)
-	if err != nil {
-		response.NewBadRequestError("Invalid request payload: unable to decode JSON").Send(w)
-		return
-	}
+	emailReq := middleware.Body[friend.EmailRequest](r)
 
-	if err := friend.ValidateEmailRequest(&emailReq); err != nil {
-		response.NewBadRequestError(err.Error()).Send(w)
-		return
-	}
-
-	friends, err := h.relationshipCtrl.GetFriendListByEmail(context.Background(), emailReq.Email)
+	friends, err := h.relationshipCtrl.GetFriendListByEmail(r.Context(), emailReq.Email)
 	if err != nil {
-		utils.HandleError(w, err)
+		utils.HandleError(w, r, err)
 		return
 	}
 
-	okResponse := response.NewOK(map[string]interface{}{"friends": friends})
+	okResponse := response.NewList(friends)
 	okResponse.Send(w)
 }
 
 // GetCommonListHandler handles retrieving a common friend list for two users.
 func (h *RelationshipHandler) GetCommonListHandler(w http.ResponseWriter, r *http.Request) {
-	var commonFriendsReq friend.CommonFriendListReq
-	err := json.NewDecoder(r.Body).Decode(&commonFriendsReq)
-	if err != nil || len(commonFriendsReq.Friends) != 2 || commonFriendsReq.Friends[0] == commonFriendsReq.Friends[1] {
-		response.NewBadRequestError("Invalid request payload").Send(w)
-		return
-	}
-	if err := friend.ValidateCommonFriendListRequest(&commonFriendsReq); err != nil {
-		response.NewBadRequestError(err.Error()).Send(w)
-		return
-	}
+	commonFriendsReq := middleware.Body[friend.CommonFriendListReq](r)
 
-	commonList, err := h.relationshipCtrl.GetCommonList(context.Background(), &commonFriendsReq)
+	commonList, err := h.relationshipCtrl.GetCommonList(r.Context(), commonFriendsReq)
 	if err != nil {
-		utils.HandleError(w, err)
+		utils.HandleError(w, r, err)
 		return
 	}
 
-	okResponse := response.NewOK(map[string]interface{}{"friends": commonList})
+	okResponse := response.NewList(commonList)
 	okResponse.Send(w)
 }
 
 // SubscribeHandler handles the subscription of updates between users.
 func (h *RelationshipHandler) SubscribeHandler(w http.ResponseWriter, r *http.Request) {
-	var subcribeReq subscription.SubscribeRequest
-	if err := json.NewDecoder(r.Body).Decode(&subcribeReq); err != nil || subcribeReq.Requestor == subcribeReq.Target {
-		response.NewBadRequestError("Invalid request payload").Send(w)
-		return
-	}
-	if err := subscription.ValidateSubscribeRequest(&subcribeReq); err != nil {
-		response.NewBadRequestError(err.Error()).Send(w)
-		return
-	}
+	subcribeReq := middleware.Body[subscription.SubscribeRequest](r)
 
-	err := h.relationshipCtrl.Subscribe(context.Background(), &subcribeReq)
+	err := h.relationshipCtrl.Subscribe(r.Context(), subcribeReq)
 	if err != nil {
-		utils.HandleError(w, err)
+		utils.HandleError(w, r, err)
 		return
 	}
 
-	createdResponse := response.NewCREATED(nil)
+	createdResponse := response.NewCREATED(response.Empty{})
 	createdResponse.Send(w)
 }
 
 // BlockUpdatesHandler handles blocking updates between users.
 func (h *RelationshipHandler) BlockUpdatesHandler(w http.ResponseWriter, r *http.Request) {
-	var blockReq block.BlockRequest
-	if err := json.NewDecoder(r.Body).Decode(&blockReq); err != nil || blockReq.Requestor == blockReq.Target {
-		response.NewBadRequestError("Invalid request payload").Send(w)
-		return
-	}
-
-	if err := block.ValidateBlockRequest(&blockReq); err != nil {
-		response.NewBadRequestError(err.Error()).Send(w)
-		return
-	}
+	blockReq := middleware.Body[block.BlockRequest](r)
 
-	err := h.relationshipCtrl.BlockUpdates(context.Background(), &blockReq)
+	err := h.relationshipCtrl.BlockUpdates(r.Context(), blockReq)
 	if err != nil {
-		utils.HandleError(w, err)
+		utils.HandleError(w, r, err)
 		return
 	}
 
-	createdResponse := response.NewCREATED(nil)
+	createdResponse := response.NewCREATED(response.Empty{})
 	createdResponse.Send(w)
 }
 
-// GetUpdatableEmailAddressesHandler retrieves emails that can receive updates.
+// GetUpdatableEmailAddressesHandler retrieves emails that can receive updates. It records nothing.
 func (h *RelationshipHandler) GetUpdatableEmailAddressesHandler(w http.ResponseWriter, r *http.Request) {
-	var recipientsReq subscription.RecipientRequest
-	if err := json.NewDecoder(r.Body).Decode(&recipientsReq); err != nil {
-		response.NewBadRequestError("Invalid request payload").Send(w)
-		return
-	}
+	recipientsReq := middleware.Body[subscription.RecipientRequest](r)
 
-	if err := subscription.ValidateRecipientRequest(&recipientsReq); err != nil {
-		response.NewBadRequestError(err.Error()).Send(w)
+	recipients, err := h.relationshipCtrl.GetUpdatableEmailAddresses(r.Context(), recipientsReq)
+	if err != nil {
+		utils.HandleError(w, r, err)
 		return
 	}
 
-	recipients, err := h.relationshipCtrl.GetUpdatableEmailAddresses(context.Background(), &recipientsReq)
+	okResponse := response.NewList(recipients)
+	okResponse.Send(w)
+}
+
+// PostUpdateHandler posts an update from the sender and returns the emails that receive it.
+// The update is recorded as an update.posted event.
+func (h *RelationshipHandler) PostUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	updateReq := middleware.Body[subscription.RecipientRequest](r)
+
+	recipients, err := h.relationshipCtrl.PostUpdate(r.Context(), updateReq)
 	if err != nil {
-		utils.HandleError(w, err)
+		utils.HandleError(w, r, err)
 		return
 	}
 
-	okResponse := response.NewOK(map[string]interface{}{"recipients": recipients})
+	okResponse := response.NewList(recipients)
 	okResponse.Send(w)
 }
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/block"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/subscription"
@@ -49,10 +50,10 @@ func TestCreateFriendHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			body, _ := json.Marshal(tt.input)
-			req := httptest.NewRequest(http.MethodPost, "/friends", bytes.NewBuffer(body))
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/friends", bytes.NewBuffer(body))
 			w := httptest.NewRecorder()
 
-			handler.CreateFriendHandler(w, req)
+			withRequestValidation(http.MethodPost, "/api/v1/friends", handler.CreateFriendHandler).ServeHTTP(w, req)
 
 			res := w.Result()
 			assert.Equal(t, tt.expectedStatus, res.StatusCode)
@@ -60,6 +61,34 @@ func TestCreateFriendHandler(t *testing.T) {
 	}
 }
 
+// Test that a domain error is sent as a problem with its code and the request path.
+func TestCreateFriendHandler_Problem(t *testing.T) {
+	mockService := &MockRelationshipService{
+		CreateFriendFunc: func(ctx context.Context, req *friend.CreateFriend) error {
+			return domain.Errorf(domain.ErrAlreadyFriends, "friendship already exists between user1@example.com and user2@example.com")
+		},
+	}
+	handler := setupRelationshipHandler(mockService)
+
+	body, _ := json.Marshal(friend.CreateFriend{Friends: []string{"user1@example.com", "user2@example.com"}})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/friends", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	withRequestValidation(http.MethodPost, "/api/v1/friends", handler.CreateFriendHandler).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+	var problem map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&problem)
+	assert.Equal(t, "/problems/friendship-exists", problem["type"])
+	assert.Equal(t, "Conflict", problem["title"])
+	assert.Equal(t, float64(http.StatusConflict), problem["status"])
+	assert.Equal(t, "friendship already exists between user1@example.com and user2@example.com", problem["detail"])
+	assert.Equal(t, "/api/v1/friends", problem["instance"])
+	assert.Equal(t, "FRIENDSHIP_EXISTS", problem["code"])
+}
+
 // Test for retrieving a list of friends for a specific email address.
 func TestGetFriendListByEmailHandler(t *testing.T) {
 	mockService := &MockRelationshipService{
@@ -91,10 +120,10 @@ func TestGetFriendListByEmailHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			body, _ := json.Marshal(tt.input)
-			req := httptest.NewRequest(http.MethodPost, "/friends/list", bytes.NewBuffer(body))
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/friends/list", bytes.NewBuffer(body))
 			w := httptest.NewRecorder()
 
-			handler.GetFriendListByEmailHandler(w, req)
+			withRequestValidation(http.MethodPost, "/api/v1/friends/list", handler.GetFriendListByEmailHandler).ServeHTTP(w, req)
 
 			res := w.Result()
 			assert.Equal(t, tt.expectedStatus, res.StatusCode)
@@ -103,7 +132,7 @@ func TestGetFriendListByEmailHandler(t *testing.T) {
 				var response map[string]interface{}
 				json.NewDecoder(res.Body).Decode(&response)
 				sort.Strings(tt.expectedFriends)
-				friends := response["friends"].([]interface{})
+				friends := response["data"].([]interface{})
 				actualFriends := make([]string, len(friends))
 				for i, friend := range friends {
 					actualFriends[i] = friend.(string)
@@ -115,6 +144,25 @@ func TestGetFriendListByEmailHandler(t *testing.T) {
 	}
 }
 
+// Test that an empty list is sent as [] with a zero count.
+func TestGetFriendListByEmailHandler_Empty(t *testing.T) {
+	mockService := &MockRelationshipService{
+		GetFriendListByEmailFunc: func(ctx context.Context, email string) ([]string, error) {
+			return nil, nil
+		},
+	}
+	handler := setupRelationshipHandler(mockService)
+
+	body, _ := json.Marshal(friend.EmailRequest{Email: "user@example.com"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/friends/list", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	withRequestValidation(http.MethodPost, "/api/v1/friends/list", handler.GetFriendListByEmailHandler).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"success":true,"data":[],"meta":{"count":0}}`, w.Body.String())
+}
+
 // Test for retrieving a common list of friends between two users.
 func TestGetCommonListHandler(t *testing.T) {
 	mockService := &MockRelationshipService{
@@ -146,10 +194,10 @@ func TestGetCommonListHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			body, _ := json.Marshal(tt.input)
-			req := httptest.NewRequest(http.MethodPost, "/friends/common", bytes.NewBuffer(body))
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/friends/common-list", bytes.NewBuffer(body))
 			w := httptest.NewRecorder()
 
-			handler.GetCommonListHandler(w, req)
+			withRequestValidation(http.MethodPost, "/api/v1/friends/common-list", handler.GetCommonListHandler).ServeHTTP(w, req)
 
 			res := w.Result()
 			assert.Equal(t, tt.expectedStatus, res.StatusCode)
@@ -158,8 +206,8 @@ func TestGetCommonListHandler(t *testing.T) {
 				var response map[string]interface{}
 				json.NewDecoder(res.Body).Decode(&response)
 
-				actualFriends := make([]string, len(response["friends"].([]interface{})))
-				for i, friend := range response["friends"].([]interface{}) {
+				actualFriends := make([]string, len(response["data"].([]interface{})))
+				for i, friend := range response["data"].([]interface{}) {
 					actualFriends[i] = friend.(string)
 				}
 
@@ -206,10 +254,10 @@ func TestSubscribeHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			body, _ := json.Marshal(tt.input)
-			req := httptest.NewRequest(http.MethodPost, "/subscribe", bytes.NewBuffer(body))
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/subcription", bytes.NewBuffer(body))
 			w := httptest.NewRecorder()
 
-			handler.SubscribeHandler(w, req)
+			withRequestValidation(http.MethodPost, "/api/v1/subcription", handler.SubscribeHandler).ServeHTTP(w, req)
 
 			res := w.Result()
 			assert.Equal(t, tt.expectedStatus, res.StatusCode)
@@ -251,10 +299,10 @@ func TestBlockUpdatesHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			body, _ := json.Marshal(tt.input)
-			req := httptest.NewRequest(http.MethodPost, "/block", bytes.NewBuffer(body))
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/block", bytes.NewBuffer(body))
 			w := httptest.NewRecorder()
 
-			handler.BlockUpdatesHandler(w, req)
+			withRequestValidation(http.MethodPost, "/api/v1/block", handler.BlockUpdatesHandler).ServeHTTP(w, req)
 
 			res := w.Result()
 			assert.Equal(t, tt.expectedStatus, res.StatusCode)
@@ -262,6 +310,37 @@ func TestBlockUpdatesHandler(t *testing.T) {
 	}
 }
 
+// Test that validation failures list each invalid field by its JSON name.
+func TestBlockUpdatesHandler_FieldErrors(t *testing.T) {
+	handler := setupRelationshipHandler(&MockRelationshipService{})
+
+	body, _ := json.Marshal(map[string]string{"requestor": "user@example.com", "target": "not-an-email"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/block", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	withRequestValidation(http.MethodPost, "/api/v1/block", handler.BlockUpdatesHandler).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var problem struct {
+		Code   string `json:"code"`
+		Detail string `json:"detail"`
+		Errors []struct {
+			Field   string `json:"field"`
+			Rule    string `json:"rule"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	json.NewDecoder(w.Body).Decode(&problem)
+	assert.Equal(t, domain.CodeValidationFailed, problem.Code)
+	assert.Equal(t, "target must be a valid email address", problem.Detail)
+	if assert.Len(t, problem.Errors, 1) {
+		assert.Equal(t, "target", problem.Errors[0].Field)
+		assert.Equal(t, "email", problem.Errors[0].Rule)
+		assert.Equal(t, "target must be a valid email address", problem.Errors[0].Message)
+	}
+}
+
 // Test for retrieving updatable email addresses based on sender's updates.
 func TestGetUpdatableEmailAddressesHandler(t *testing.T) {
 	mockService := &MockRelationshipService{
@@ -293,10 +372,10 @@ func TestGetUpdatableEmailAddressesHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			body, _ := json.Marshal(tt.input)
-			req := httptest.NewRequest(http.MethodPost, "/recipients", bytes.NewBuffer(body))
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/subcription/recipients", bytes.NewBuffer(body))
 			w := httptest.NewRecorder()
 
-			handler.GetUpdatableEmailAddressesHandler(w, req)
+			withRequestValidation(http.MethodPost, "/api/v1/subcription/recipients", handler.GetUpdatableEmailAddressesHandler).ServeHTTP(w, req)
 
 			res := w.Result()
 			assert.Equal(t, tt.expectedStatus, res.StatusCode)
@@ -306,7 +385,7 @@ func TestGetUpdatableEmailAddressesHandler(t *testing.T) {
 				err := json.NewDecoder(res.Body).Decode(&response)
 				assert.NoError(t, err)
 
-				actualEmails := response["recipients"].([]interface{})
+				actualEmails := response["data"].([]interface{})
 				var actualEmailsStr []string
 				for _, email := range actualEmails {
 					actualEmailsStr = append(actualEmailsStr, email.(string))
@@ -317,3 +396,31 @@ func TestGetUpdatableEmailAddressesHandler(t *testing.T) {
 		})
 	}
 }
+
+// Test that posting an update goes through PostUpdate and returns its recipients.
+func TestPostUpdateHandler(t *testing.T) {
+	var posted *subscription.RecipientRequest
+	mockService := &MockRelationshipService{
+		PostUpdateFunc: func(ctx context.Context, req *subscription.RecipientRequest) ([]string, error) {
+			posted = req
+			return []string{"recipient1@example.com"}, nil
+		},
+	}
+	handler := setupRelationshipHandler(mockService)
+
+	body, _ := json.Marshal(subscription.RecipientRequest{Sender: "user@example.com", Text: "Hello kate@example.com"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/updates", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	withRequestValidation(http.MethodPost, "/api/v1/updates", handler.PostUpdateHandler).ServeHTTP(w, req)
+
+	res := w.Result()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, &subscription.RecipientRequest{Sender: "user@example.com", Text: "Hello kate@example.com"}, posted)
+
+	var response struct {
+		Data []string `json:"data"`
+	}
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&response))
+	assert.Equal(t, []string{"recipient1@example.com"}, response.Data)
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/response"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/block"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/subscription"
+	utils "github.com/koeylp/friends-management/cmd/internal/pkg/error_util"
+	validation "github.com/koeylp/friends-management/cmd/internal/pkg/validation_util"
+)
+
+// The handlers below serve the v2 resource routes, where users and their relationships are
+// addressed by path and the HTTP method says what to do with them. They share the controllers
+// of the v1 handlers, so both APIs apply the same rules.
+
+// ListFriendsHandler handles GET /users/{email}/friends.
+func (h *RelationshipHandler) ListFriendsHandler(w http.ResponseWriter, r *http.Request) {
+	email, err := pathEmail(r, "email")
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	friends, err := h.relationshipCtrl.GetFriendListByEmail(r.Context(), email)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	okResponse := response.NewList(friends)
+	okResponse.Send(w)
+}
+
+// ListCommonFriendsHandler handles GET /users/{email}/common-friends/{other}.
+func (h *RelationshipHandler) ListCommonFriendsHandler(w http.ResponseWriter, r *http.Request) {
+	emails, err := pathEmails(r, "email", "other")
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+	commonFriendsReq := &friend.CommonFriendListReq{Friends: emails}
+	if err := friend.ValidateCommonFriendListRequest(commonFriendsReq); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	commonList, err := h.relationshipCtrl.GetCommonList(r.Context(), commonFriendsReq)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	okResponse := response.NewList(commonList)
+	okResponse.Send(w)
+}
+
+// PutSubscriptionHandler handles PUT /users/{email}/subscriptions/{target}. It answers
+// 201 Created when the subscription is new and 200 OK when it already existed.
+func (h *RelationshipHandler) PutSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	subscribeReq, err := subscribeRequestFromPath(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	err = h.relationshipCtrl.Subscribe(r.Context(), subscribeReq)
+	if errors.Is(err, domain.ErrAlreadySubscribed) {
+		okResponse := response.NewOK(response.Empty{})
+		okResponse.Send(w)
+		return
+	}
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	createdResponse := response.NewCREATED(response.Empty{})
+	createdResponse.Send(w)
+}
+
+// DeleteSubscriptionHandler handles DELETE /users/{email}/subscriptions/{target}.
+func (h *RelationshipHandler) DeleteSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	subscribeReq, err := subscribeRequestFromPath(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	err = h.relationshipCtrl.Unsubscribe(r.Context(), subscribeReq)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	okResponse := response.NewOK(response.Empty{})
+	okResponse.Send(w)
+}
+
+// PutBlockHandler handles PUT /users/{email}/blocks/{target}. It answers
+// 201 Created when the block is new and 200 OK when it already existed.
+func (h *RelationshipHandler) PutBlockHandler(w http.ResponseWriter, r *http.Request) {
+	blockReq, err := blockRequestFromPath(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	err = h.relationshipCtrl.BlockUpdates(r.Context(), blockReq)
+	if errors.Is(err, domain.ErrAlreadyBlocked) {
+		okResponse := response.NewOK(response.Empty{})
+		okResponse.Send(w)
+		return
+	}
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	createdResponse := response.NewCREATED(response.Empty{})
+	createdResponse.Send(w)
+}
+
+// DeleteBlockHandler handles DELETE /users/{email}/blocks/{target}.
+func (h *RelationshipHandler) DeleteBlockHandler(w http.ResponseWriter, r *http.Request) {
+	blockReq, err := blockRequestFromPath(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	err = h.relationshipCtrl.UnblockUpdates(r.Context(), blockReq)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	okResponse := response.NewOK(response.Empty{})
+	okResponse.Send(w)
+}
+
+// subscribeRequestFromPath builds the subscription between the {email} and {target} path parameters.
+func subscribeRequestFromPath(r *http.Request) (*subscription.SubscribeRequest, error) {
+	emails, err := pathEmails(r, "email", "target")
+	if err != nil {
+		return nil, err
+	}
+	subscribeReq := &subscription.SubscribeRequest{Requestor: emails[0], Target: emails[1]}
+	if err := subscription.ValidateSubscribeRequest(subscribeReq); err != nil {
+		return nil, err
+	}
+	return subscribeReq, nil
+}
+
+// blockRequestFromPath builds the block between the {email} and {target} path parameters.
+func blockRequestFromPath(r *http.Request) (*block.BlockRequest, error) {
+	emails, err := pathEmails(r, "email", "target")
+	if err != nil {
+		return nil, err
+	}
+	blockReq := &block.BlockRequest{Requestor: emails[0], Target: emails[1]}
+	if err := block.ValidateBlockRequest(blockReq); err != nil {
+		return nil, err
+	}
+	return blockReq, nil
+}
+
+// pathEmails returns the email addresses in the named path parameters, reporting every invalid one.
+func pathEmails(r *http.Request, names ...string) ([]string, error) {
+	emails := make([]string, len(names))
+	var fieldErrs []validation.FieldError
+	var messages []string
+	for i, name := range names {
+		email, err := url.PathUnescape(chi.URLParam(r, name))
+		if err == nil {
+			err = validation.Validator().Var(email, "required,email")
+		}
+		if err != nil {
+			message := name + " must be a valid email address"
+			fieldErrs = append(fieldErrs, validation.FieldError{Field: name, Rule: "email", Message: message})
+			messages = append(messages, message)
+		}
+		emails[i] = email
+	}
+	if len(fieldErrs) > 0 {
+		return nil, response.NewValidationError(strings.Join(messages, "; "), fieldErrs...)
+	}
+	return emails, nil
+}
+
+// pathEmail returns the email address in the named path parameter.
+func pathEmail(r *http.Request, name string) (string, error) {
+	emails, err := pathEmails(r, name)
+	if err != nil {
+		return "", err
+	}
+	return emails[0], nil
+}
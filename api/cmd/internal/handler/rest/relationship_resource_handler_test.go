@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	relationshipCtrl "github.com/koeylp/friends-management/cmd/internal/controller/relationship"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/memory"
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/block"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/subscription"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	outboxRepo "github.com/koeylp/friends-management/cmd/internal/repository/outbox"
+	relationshipRepo "github.com/koeylp/friends-management/cmd/internal/repository/relationship"
+	userRepo "github.com/koeylp/friends-management/cmd/internal/repository/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test for listing a user's friends by the email in the path.
+func TestListFriendsHandler(t *testing.T) {
+	mockService := &MockRelationshipService{
+		GetFriendListByEmailFunc: func(ctx context.Context, email string) ([]string, error) {
+			if email == "unknown@example.com" {
+				return nil, domain.Errorf(domain.ErrUserNotFound, "user not found with email %s", email)
+			}
+			return []string{"friend@example.com"}, nil
+		},
+	}
+	handler := setupRelationshipHandler(mockService)
+	route := withRequestValidation(http.MethodGet, "/api/v2/users/{email}/friends", handler.ListFriendsHandler)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{"Valid request", "/api/v2/users/user@example.com/friends", http.StatusOK},
+		{"Escaped email", "/api/v2/users/user%40example.com/friends", http.StatusOK},
+		{"Invalid email", "/api/v2/users/not-an-email/friends", http.StatusBadRequest},
+		{"Unknown user", "/api/v2/users/unknown@example.com/friends", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			route.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+// Test that both path emails are passed to the common friends lookup.
+func TestListCommonFriendsHandler(t *testing.T) {
+	var received *friend.CommonFriendListReq
+	mockService := &MockRelationshipService{
+		GetCommonListFunc: func(ctx context.Context, req *friend.CommonFriendListReq) ([]string, error) {
+			received = req
+			return []string{"common@example.com"}, nil
+		},
+	}
+	handler := setupRelationshipHandler(mockService)
+	route := withRequestValidation(http.MethodGet, "/api/v2/users/{email}/common-friends/{other}", handler.ListCommonFriendsHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/users/a@example.com/common-friends/b@example.com", nil)
+	w := httptest.NewRecorder()
+
+	route.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"success":true,"data":["common@example.com"],"meta":{"count":1}}`, w.Body.String())
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, received.Friends)
+}
+
+// Test that PUT creates a subscription and treats an existing one as success.
+func TestPutSubscriptionHandler(t *testing.T) {
+	exists := false
+	mockService := &MockRelationshipService{
+		SubscribeFunc: func(ctx context.Context, req *subscription.SubscribeRequest) error {
+			if exists {
+				return domain.Errorf(domain.ErrAlreadySubscribed, "subscription already exists")
+			}
+			exists = true
+			return nil
+		},
+	}
+	handler := setupRelationshipHandler(mockService)
+	route := withRequestValidation(http.MethodPut, "/api/v2/users/{email}/subscriptions/{target}", handler.PutSubscriptionHandler)
+
+	for _, expectedStatus := range []int{http.StatusCreated, http.StatusOK} {
+		req := httptest.NewRequest(http.MethodPut, "/api/v2/users/a@example.com/subscriptions/b@example.com", nil)
+		w := httptest.NewRecorder()
+
+		route.ServeHTTP(w, req)
+
+		assert.Equal(t, expectedStatus, w.Code)
+	}
+}
+
+// Test that a subscription to oneself is rejected before reaching the controller.
+func TestPutSubscriptionHandler_SameUser(t *testing.T) {
+	handler := setupRelationshipHandler(&MockRelationshipService{})
+	route := withRequestValidation(http.MethodPut, "/api/v2/users/{email}/subscriptions/{target}", handler.PutSubscriptionHandler)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v2/users/a@example.com/subscriptions/a@example.com", nil)
+	w := httptest.NewRecorder()
+
+	route.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// Test that deleting a subscription that does not exist is reported as not found.
+func TestDeleteSubscriptionHandler(t *testing.T) {
+	mockService := &MockRelationshipService{
+		UnsubscribeFunc: func(ctx context.Context, req *subscription.SubscribeRequest) error {
+			return domain.Errorf(domain.ErrNotSubscribed, "%s is not subscribed to %s", req.Requestor, req.Target)
+		},
+	}
+	handler := setupRelationshipHandler(mockService)
+	route := withRequestValidation(http.MethodDelete, "/api/v2/users/{email}/subscriptions/{target}", handler.DeleteSubscriptionHandler)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v2/users/a@example.com/subscriptions/b@example.com", nil)
+	w := httptest.NewRecorder()
+
+	route.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var problem map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+	assert.Equal(t, "SUBSCRIPTION_NOT_FOUND", problem["code"])
+	assert.Equal(t, "a@example.com is not subscribed to b@example.com", problem["detail"])
+}
+
+// Test blocking and unblocking through the resource routes.
+func TestBlockResourceHandlers(t *testing.T) {
+	var blocked, unblocked *block.BlockRequest
+	mockService := &MockRelationshipService{
+		BlockUpdatesFunc: func(ctx context.Context, req *block.BlockRequest) error {
+			blocked = req
+			return nil
+		},
+		UnblockUpdatesFunc: func(ctx context.Context, req *block.BlockRequest) error {
+			unblocked = req
+			return nil
+		},
+	}
+	handler := setupRelationshipHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v2/users/a@example.com/blocks/b@example.com", nil)
+	w := httptest.NewRecorder()
+	withRequestValidation(http.MethodPut, "/api/v2/users/{email}/blocks/{target}", handler.PutBlockHandler).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, &block.BlockRequest{Requestor: "a@example.com", Target: "b@example.com"}, blocked)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v2/users/a@example.com/blocks/b@example.com", nil)
+	w = httptest.NewRecorder()
+	withRequestValidation(http.MethodDelete, "/api/v2/users/{email}/blocks/{target}", handler.DeleteBlockHandler).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, blocked, unblocked)
+}
+
+// Test that a subscription or block held by the target does not stop the requestor from
+// creating and then removing their own.
+func TestResourceHandlers_ReverseRelationship(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := userRepo.NewMemoryUserRepository(store)
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		require.NoError(t, users.CreateUser(ctx, &user.CreateUser{Email: email}))
+	}
+	controller := relationshipCtrl.NewRelationshipController(
+		relationshipRepo.NewMemoryRelationshipRepository(store), users, outboxRepo.NewMemoryOutboxRepository(store), metrics.New())
+	handler := NewRelationshipHandler(controller)
+
+	require.NoError(t, controller.Subscribe(ctx, &subscription.SubscribeRequest{Requestor: "b@example.com", Target: "a@example.com"}))
+	require.NoError(t, controller.BlockUpdates(ctx, &block.BlockRequest{Requestor: "b@example.com", Target: "a@example.com"}))
+
+	tests := []struct {
+		pattern     string
+		path        string
+		put, delete http.HandlerFunc
+	}{
+		{"/api/v2/users/{email}/subscriptions/{target}", "/api/v2/users/a@example.com/subscriptions/b@example.com", handler.PutSubscriptionHandler, handler.DeleteSubscriptionHandler},
+		{"/api/v2/users/{email}/blocks/{target}", "/api/v2/users/a@example.com/blocks/b@example.com", handler.PutBlockHandler, handler.DeleteBlockHandler},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			withRequestValidation(http.MethodPut, tt.pattern, tt.put).ServeHTTP(w, httptest.NewRequest(http.MethodPut, tt.path, nil))
+			assert.Equal(t, http.StatusCreated, w.Code)
+
+			w = httptest.NewRecorder()
+			withRequestValidation(http.MethodDelete, tt.pattern, tt.delete).ServeHTTP(w, httptest.NewRequest(http.MethodDelete, tt.path, nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+}
@@ -3,9 +3,12 @@ package response
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	validation "github.com/koeylp/friends-management/cmd/internal/pkg/validation_util"
 )
 
 const (
@@ -13,6 +16,8 @@ const (
 	StatusNotFound     = http.StatusNotFound
 	StatusBadRequest   = http.StatusBadRequest
 	StatusUnauthorized = http.StatusUnauthorized
+	StatusConflict     = http.StatusConflict
+	StatusTooLarge     = http.StatusRequestEntityTooLarge
 	StatusInternal     = http.StatusInternalServerError
 )
 
@@ -21,31 +26,53 @@ var (
 	ReasonNotFound     = "Not Found"
 	ReasonForbidden    = "Access Denied"
 	ReasonUnauthorized = "Unauthorized"
+	ReasonConflict     = "Conflict"
+	ReasonTooLarge     = "Payload Too Large"
 	ReasonInternal     = "Internal Server Error"
 )
 
+// ProblemContentType is the media type of error bodies, as defined by RFC 7807.
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix is the base of the problem type URI; the code is appended in kebab case.
+const problemTypePrefix = "/problems/"
+
+// ErrorResponse is an RFC 7807 problem details object extended with a machine-readable code
+// and, for validation failures, the rule each invalid field failed.
 type ErrorResponse struct {
-	Message string
-	Status  int `json:"-"`
-	Time    time.Time
+	Type     string                  `json:"type"`
+	Title    string                  `json:"title"`
+	Status   int                     `json:"status"`
+	Detail   string                  `json:"detail"`
+	Instance string                  `json:"instance,omitempty"`
+	Code     string                  `json:"code"`
+	Errors   []validation.FieldError `json:"errors,omitempty"`
+	Time     time.Time               `json:"timestamp"`
 }
 
-func NewErrorResponse(message string, status int) *ErrorResponse {
+func NewErrorResponse(message string, status int, title string, code string) *ErrorResponse {
 	err := &ErrorResponse{
-		Message: message,
-		Status:  status,
-		Time:    time.Now(),
+		Title:  title,
+		Status: status,
+		Detail: message,
+		Time:   time.Now(),
 	}
-	log.Printf("ERROR: %d -- %s \n", err.Status, err.Message)
+	err.setCode(code)
 	return err
 }
 
+func (e *ErrorResponse) setCode(code string) {
+	e.Code = code
+	e.Type = problemTypePrefix + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+}
+
 func (e *ErrorResponse) Error() string {
-	return fmt.Sprintf("%d: %s", e.Status, e.Message)
+	return fmt.Sprintf("%d: %s", e.Status, e.Detail)
 }
 
+// Send writes the problem to w. Instance is included when it has been set, usually to the request path.
 func (e *ErrorResponse) Send(w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", ProblemContentType)
 	w.WriteHeader(e.Status)
 	json.NewEncoder(w).Encode(e)
 }
@@ -58,7 +85,27 @@ func NewBadRequestError(message string) *BadRequestError {
 	if message == "" {
 		message = ReasonBadRequest
 	}
-	return &BadRequestError{NewErrorResponse(message, StatusBadRequest)}
+	return &BadRequestError{NewErrorResponse(message, StatusBadRequest, ReasonBadRequest, domain.CodeBadRequest)}
+}
+
+// NewInvalidPayloadError reports a request body that could not be decoded, with the fields at fault when known.
+func NewInvalidPayloadError(message string, fieldErrs ...validation.FieldError) *BadRequestError {
+	err := NewBadRequestError(message).WithCode(domain.CodeInvalidPayload)
+	err.Errors = fieldErrs
+	return err
+}
+
+// NewValidationError reports a decoded request that failed validation, with the fields at fault.
+func NewValidationError(message string, fieldErrs ...validation.FieldError) *BadRequestError {
+	err := NewBadRequestError(message).WithCode(domain.CodeValidationFailed)
+	err.Errors = fieldErrs
+	return err
+}
+
+// WithCode replaces the default code of the error.
+func (e *BadRequestError) WithCode(code string) *BadRequestError {
+	e.setCode(code)
+	return e
 }
 
 type ForbiddenError struct {
@@ -69,7 +116,13 @@ func NewForbiddenError(message string) *ForbiddenError {
 	if message == "" {
 		message = ReasonForbidden
 	}
-	return &ForbiddenError{NewErrorResponse(message, StatusForbidden)}
+	return &ForbiddenError{NewErrorResponse(message, StatusForbidden, ReasonForbidden, domain.CodeForbidden)}
+}
+
+// WithCode replaces the default code of the error.
+func (e *ForbiddenError) WithCode(code string) *ForbiddenError {
+	e.setCode(code)
+	return e
 }
 
 type NotFoundError struct {
@@ -80,7 +133,13 @@ func NewNotFoundError(message string) *NotFoundError {
 	if message == "" {
 		message = ReasonNotFound
 	}
-	return &NotFoundError{NewErrorResponse(message, StatusNotFound)}
+	return &NotFoundError{NewErrorResponse(message, StatusNotFound, ReasonNotFound, domain.CodeNotFound)}
+}
+
+// WithCode replaces the default code of the error.
+func (e *NotFoundError) WithCode(code string) *NotFoundError {
+	e.setCode(code)
+	return e
 }
 
 type UnauthorizedError struct {
@@ -91,7 +150,42 @@ func NewUnauthorizedError(message string) *UnauthorizedError {
 	if message == "" {
 		message = ReasonUnauthorized
 	}
-	return &UnauthorizedError{NewErrorResponse(message, StatusUnauthorized)}
+	return &UnauthorizedError{NewErrorResponse(message, StatusUnauthorized, ReasonUnauthorized, domain.CodeUnauthorized)}
+}
+
+// WithCode replaces the default code of the error.
+func (e *UnauthorizedError) WithCode(code string) *UnauthorizedError {
+	e.setCode(code)
+	return e
+}
+
+type ConflictError struct {
+	*ErrorResponse
+}
+
+func NewConflictError(message string) *ConflictError {
+	if message == "" {
+		message = ReasonConflict
+	}
+	return &ConflictError{NewErrorResponse(message, StatusConflict, ReasonConflict, domain.CodeConflict)}
+}
+
+// WithCode replaces the default code of the error.
+func (e *ConflictError) WithCode(code string) *ConflictError {
+	e.setCode(code)
+	return e
+}
+
+type PayloadTooLargeError struct {
+	*ErrorResponse
+}
+
+// NewPayloadTooLargeError reports a request body larger than the server accepts.
+func NewPayloadTooLargeError(message string) *PayloadTooLargeError {
+	if message == "" {
+		message = ReasonTooLarge
+	}
+	return &PayloadTooLargeError{NewErrorResponse(message, StatusTooLarge, ReasonTooLarge, domain.CodePayloadTooLarge)}
 }
 
 type InternalServerError struct {
@@ -102,5 +196,5 @@ func NewInternalServerError(message string) *InternalServerError {
 	if message == "" {
 		message = ReasonInternal
 	}
-	return &InternalServerError{NewErrorResponse(message, StatusInternal)}
+	return &InternalServerError{NewErrorResponse(message, StatusInternal, ReasonInternal, domain.CodeInternal)}
 }
@@ -3,76 +3,81 @@ package response
 import (
 	"encoding/json"
 	"net/http"
-	"reflect"
 )
 
-type SuccessResponse struct {
-	Success bool                   `json:"success"`
-	Data    map[string]interface{} `json:"metaData,omitempty"`
-	Status  int                    `json:"-"`
-}
-
 const (
 	STATUS_OK      = http.StatusOK
 	STATUS_CREATED = http.StatusCreated
 )
 
-func NewSuccessResponse(success bool, statusCode int, data map[string]interface{}) SuccessResponse {
-	return SuccessResponse{
-		Success: success,
-		Status:  statusCode,
-		Data:    data,
-	}
+// Envelope is the body of every successful JSON response:
+//
+//	{"success": true, "data": <T>, "meta": {"count": 2}}
+//
+// Data holds the typed payload. Meta is only present for list payloads.
+type Envelope[T any] struct {
+	Success bool  `json:"success"`
+	Data    T     `json:"data"`
+	Meta    *Meta `json:"meta,omitempty"`
+	Status  int   `json:"-"`
 }
 
-func (sr *SuccessResponse) Send(w http.ResponseWriter) {
-	response := make(map[string]interface{})
+// Meta describes a list payload.
+type Meta struct {
+	Count      int         `json:"count"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
 
-	for key, value := range sr.Data {
-		if reflect.ValueOf(value).IsNil() {
-			response[key] = []interface{}{}
-			response["count"] = 0
-			break
-		}
+// Pagination describes which page of a larger result a list payload is.
+type Pagination struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
 
-		switch reflect.TypeOf(value).Kind() {
-		case reflect.Slice, reflect.Array:
-			slice := reflect.ValueOf(value)
-			convertedSlice := make([]interface{}, slice.Len())
-			for i := 0; i < slice.Len(); i++ {
-				convertedSlice[i] = slice.Index(i).Interface()
-			}
+// Empty is the payload of responses that carry no data; it is sent as {}.
+type Empty struct{}
 
-			response[key] = convertedSlice
-			response["count"] = len(convertedSlice)
-		default:
-			response[key] = value
-		}
+// NewEnvelope wraps a payload in an envelope with the given status.
+func NewEnvelope[T any](statusCode int, data T) Envelope[T] {
+	return Envelope[T]{
+		Success: true,
+		Data:    data,
+		Status:  statusCode,
 	}
+}
 
-	response["success"] = sr.Success
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(sr.Status)
-	json.NewEncoder(w).Encode(response)
+// NewOK wraps a payload in a 200 OK envelope.
+func NewOK[T any](data T) Envelope[T] {
+	return NewEnvelope(STATUS_OK, data)
 }
 
-type OK struct {
-	SuccessResponse
+// NewCREATED wraps a payload in a 201 Created envelope.
+func NewCREATED[T any](data T) Envelope[T] {
+	return NewEnvelope(STATUS_CREATED, data)
 }
 
-func NewOK(data map[string]interface{}) OK {
-	return OK{
-		SuccessResponse: NewSuccessResponse(true, STATUS_OK, data),
+// NewList wraps a list in a 200 OK envelope with its count. A nil list is sent as [].
+func NewList[T any](items []T) Envelope[[]T] {
+	if items == nil {
+		items = []T{}
 	}
+	envelope := NewOK(items)
+	envelope.Meta = &Meta{Count: len(items)}
+	return envelope
 }
 
-type CREATED struct {
-	SuccessResponse
+// WithPagination records which page of a larger result a list envelope holds.
+func (e Envelope[T]) WithPagination(pagination Pagination) Envelope[T] {
+	if e.Meta == nil {
+		e.Meta = &Meta{}
+	}
+	e.Meta.Pagination = &pagination
+	return e
 }
 
-func NewCREATED(data map[string]interface{}) CREATED {
-	return CREATED{
-		SuccessResponse: NewSuccessResponse(true, STATUS_CREATED, data),
-	}
+func (e Envelope[T]) Send(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(e)
 }
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	userCtrl "github.com/koeylp/friends-management/cmd/internal/controller/user"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/response"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/stream"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+	utils "github.com/koeylp/friends-management/cmd/internal/pkg/error_util"
+	validation "github.com/koeylp/friends-management/cmd/internal/pkg/validation_util"
+)
+
+// clientRetry is the reconnection delay, in milliseconds, suggested to SSE clients.
+const clientRetry = 3000
+
+// StreamHandler handles server-sent event streams of updates and relationship notifications.
+type StreamHandler struct {
+	hub            *stream.Hub
+	userController userCtrl.UserController
+	config         *config.StreamConfig
+}
+
+// NewStreamHandler initializes a new StreamHandler with the provided hub and user controller.
+func NewStreamHandler(hub *stream.Hub, userController userCtrl.UserController, config *config.StreamConfig) *StreamHandler {
+	return &StreamHandler{hub: hub, userController: userController, config: config}
+}
+
+// StreamEventsHandler streams the events addressed to the user given by the email query parameter.
+// Clients resume after a disconnect by sending the id of the last event they received in the
+// Last-Event-ID header (browsers do this automatically) or the last_event_id query parameter.
+func (h *StreamHandler) StreamEventsHandler(w http.ResponseWriter, r *http.Request) {
+	email := r.URL.Query().Get("email")
+	if email == "" {
+		fieldErr := validation.FieldError{Field: "email", Rule: "required", Message: "email is a required field"}
+		utils.HandleError(w, r, response.NewValidationError(fieldErr.Message, fieldErr))
+		return
+	}
+
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		fieldErr := validation.FieldError{Field: "last_event_id", Rule: "numeric", Message: "last_event_id must be a valid event id"}
+		utils.HandleError(w, r, response.NewValidationError(fieldErr.Message, fieldErr))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		utils.HandleError(w, r, response.NewInternalServerError("streaming is not supported"))
+		return
+	}
+
+	if _, err := h.userController.GetUserByEmail(r.Context(), email); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	client := h.hub.Subscribe(email, lastEventID)
+	defer h.hub.Unsubscribe(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", clientRetry)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.config.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case e, ok := <-client.Events:
+			if !ok {
+				// The hub dropped a client that fell behind; it reconnects with its last event id.
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent writes a single event in the text/event-stream format.
+func writeEvent(w http.ResponseWriter, e *event.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
+
+// parseLastEventID reads the resume position from the Last-Event-ID header or last_event_id query parameter.
+func parseLastEventID(r *http.Request) (int64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
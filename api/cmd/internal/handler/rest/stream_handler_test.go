@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/stream"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupStreamServer serves a StreamHandler backed by a fresh hub, for users in knownEmails.
+func setupStreamServer(t *testing.T, heartbeat time.Duration, knownEmails ...string) (*httptest.Server, *stream.Hub) {
+	streamConfig := &config.StreamConfig{HeartbeatInterval: heartbeat, ReplayBufferSize: 10, ClientBufferSize: 10}
+	hub := stream.NewHub(streamConfig)
+	mockUsers := &MockUserService{
+		GetUserByEmailFunc: func(ctx context.Context, email string) (*user.User, error) {
+			for _, known := range knownEmails {
+				if known == email {
+					return &user.User{Email: email}, nil
+				}
+			}
+			return nil, domain.Errorf(domain.ErrUserNotFound, "user not found with email %s", email)
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(NewStreamHandler(hub, mockUsers, streamConfig).StreamEventsHandler))
+	t.Cleanup(server.Close)
+	return server, hub
+}
+
+// readFrame reads one server-sent event frame, up to the blank line that terminates it.
+func readFrame(t *testing.T, reader *bufio.Reader) []string {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func publishFriendEvent(t *testing.T, hub *stream.Hub, id int64) {
+	data, _ := json.Marshal(event.RelationshipData{RelationshipID: "r1", Requestor: "alice@example.com", Target: "bob@example.com"})
+	require.NoError(t, hub.Publish(context.Background(), &event.Event{ID: id, Type: event.FriendCreated, Data: data}))
+}
+
+// Test that connected users receive their events as server-sent events.
+func TestStreamEventsHandler(t *testing.T) {
+	server, hub := setupStreamServer(t, time.Hour, "alice@example.com")
+
+	res, err := http.Get(server.URL + "?email=alice@example.com")
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(res.Body)
+	assert.Equal(t, []string{"retry: 3000"}, readFrame(t, reader))
+
+	publishFriendEvent(t, hub, 7)
+
+	frame := readFrame(t, reader)
+	require.Len(t, frame, 3)
+	assert.Equal(t, "id: 7", frame[0])
+	assert.Equal(t, "event: friend.created", frame[1])
+	assert.Contains(t, frame[2], `"requestor":"alice@example.com"`)
+}
+
+// Test that a reconnecting client resumes after the Last-Event-ID it sends.
+func TestStreamEventsHandler_Resume(t *testing.T) {
+	server, hub := setupStreamServer(t, time.Hour, "bob@example.com")
+	publishFriendEvent(t, hub, 1)
+	publishFriendEvent(t, hub, 2)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"?email=bob@example.com", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	reader := bufio.NewReader(res.Body)
+	readFrame(t, reader)
+	assert.Equal(t, "id: 2", readFrame(t, reader)[0])
+}
+
+// Test that idle streams receive heartbeat comments.
+func TestStreamEventsHandler_Heartbeat(t *testing.T) {
+	server, _ := setupStreamServer(t, 10*time.Millisecond, "alice@example.com")
+
+	res, err := http.Get(server.URL + "?email=alice@example.com")
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	reader := bufio.NewReader(res.Body)
+	readFrame(t, reader)
+	assert.Equal(t, []string{": heartbeat"}, readFrame(t, reader))
+}
+
+// Test that invalid stream requests are rejected before streaming starts.
+func TestStreamEventsHandler_Invalid(t *testing.T) {
+	server, _ := setupStreamServer(t, time.Hour, "alice@example.com")
+
+	tests := map[string]int{
+		"":                         http.StatusBadRequest,
+		"?email=ghost@example.com": http.StatusNotFound,
+		"?email=alice@example.com&last_event_id=abc": http.StatusBadRequest,
+	}
+	for query, expectedStatus := range tests {
+		res, err := http.Get(server.URL + query)
+		require.NoError(t, err)
+		res.Body.Close()
+		assert.Equal(t, expectedStatus, res.StatusCode, query)
+	}
+}
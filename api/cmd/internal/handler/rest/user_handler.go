@@ -1,14 +1,13 @@
 package handlers
 
 import (
-	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
 
 	userCtrl "github.com/koeylp/friends-management/cmd/internal/controller/user"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/middleware"
 	"github.com/koeylp/friends-management/cmd/internal/handler/rest/response"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	utils "github.com/koeylp/friends-management/cmd/internal/pkg/error_util"
 )
 
 // UserHandler handles HTTP requests related to user operations
@@ -23,24 +22,14 @@ func NewUserHandler(userController userCtrl.UserController) *UserHandler {
 
 // CreateUserHandler handles the creation of a new user
 func (h *UserHandler) CreateUserHandler(w http.ResponseWriter, r *http.Request) {
-	var createUserReq user.CreateUser
+	createUserReq := middleware.Body[user.CreateUser](r)
 
-	err := json.NewDecoder(r.Body).Decode(&createUserReq)
+	err := h.userController.CreateUser(r.Context(), createUserReq)
 	if err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
-		return
-	}
-	if err := user.ValidateCreateUserRequest(&createUserReq); err != nil {
-		response.NewBadRequestError(err.Error()).Send(w)
-		return
-	}
-	err = h.userController.CreateUser(context.Background(), &createUserReq)
-	if err != nil {
-		fmt.Println(err)
-		http.Error(w, "Failed to create user", http.StatusInternalServerError)
+		utils.HandleError(w, r, err)
 		return
 	}
 
-	createdResponse := response.NewCREATED(nil)
+	createdResponse := response.NewCREATED(response.Empty{})
 	createdResponse.Send(w)
 }
@@ -47,7 +47,7 @@ func TestCreateUserHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			body, _ := json.Marshal(tt.input)
-			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewBuffer(body))
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/users", bytes.NewBuffer(body))
 			w := httptest.NewRecorder()
 
 			if tt.name == "User creation failure" {
@@ -60,7 +60,7 @@ func TestCreateUserHandler(t *testing.T) {
 				}
 			}
 
-			handler.CreateUserHandler(w, req)
+			withRequestValidation(http.MethodPost, "/api/v1/users", handler.CreateUserHandler).ServeHTTP(w, req)
 
 			res := w.Result()
 			assert.Equal(t, tt.expectedStatus, res.StatusCode)
@@ -69,6 +69,8 @@ func TestCreateUserHandler(t *testing.T) {
 				var response map[string]interface{}
 				err := json.NewDecoder(res.Body).Decode(&response)
 				assert.NoError(t, err)
+				assert.Equal(t, true, response["success"])
+				assert.Equal(t, map[string]interface{}{}, response["data"])
 			}
 		})
 	}
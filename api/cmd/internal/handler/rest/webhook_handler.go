@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	webhookCtrl "github.com/koeylp/friends-management/cmd/internal/controller/webhook"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/middleware"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/response"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+	utils "github.com/koeylp/friends-management/cmd/internal/pkg/error_util"
+)
+
+// WebhookHandler handles HTTP requests for webhook subscriptions.
+type WebhookHandler struct {
+	webhookCtrl webhookCtrl.WebhookController
+}
+
+// NewWebhookHandler initializes a new WebhookHandler with the provided controller.
+func NewWebhookHandler(webhookCtrl webhookCtrl.WebhookController) *WebhookHandler {
+	return &WebhookHandler{webhookCtrl: webhookCtrl}
+}
+
+// RegisterWebhookHandler handles registering a webhook URL for a set of event types.
+func (h *WebhookHandler) RegisterWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	createReq := middleware.Body[webhook.CreateSubscriptionRequest](r)
+
+	subscription, err := h.webhookCtrl.Register(r.Context(), createReq)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	createdResponse := response.NewCREATED(subscription)
+	createdResponse.Send(w)
+}
+
+// ListWebhooksHandler handles listing the active webhook subscriptions.
+func (h *WebhookHandler) ListWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.webhookCtrl.List(r.Context())
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	okResponse := response.NewList(subscriptions)
+	okResponse.Send(w)
+}
+
+// UnregisterWebhookHandler handles deactivating a webhook subscription.
+func (h *WebhookHandler) UnregisterWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	err := h.webhookCtrl.Unregister(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	okResponse := response.NewOK(response.Empty{})
+	okResponse.Send(w)
+}
+
+// ListWebhookDeliveriesHandler handles retrieving the delivery history of a webhook subscription.
+func (h *WebhookHandler) ListWebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := h.webhookCtrl.ListDeliveries(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	okResponse := response.NewList(deliveries)
+	okResponse.Send(w)
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/response"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test for registering a webhook subscription.
+func TestRegisterWebhookHandler(t *testing.T) {
+	mockService := &MockWebhookService{
+		RegisterFunc: func(ctx context.Context, req *webhook.CreateSubscriptionRequest) (*webhook.Subscription, error) {
+			return &webhook.Subscription{ID: "sub-1", URL: req.URL, Events: req.Events, Secret: "s3cr3t", Active: true}, nil
+		},
+	}
+	handler := setupWebhookHandler(mockService)
+
+	tests := []struct {
+		name           string
+		input          webhook.CreateSubscriptionRequest
+		expectedStatus int
+	}{
+		{
+			name:           "Valid request",
+			input:          webhook.CreateSubscriptionRequest{URL: "https://example.com/hook", Events: []string{"friend.created", "update.posted"}},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Valid request - all events",
+			input:          webhook.CreateSubscriptionRequest{URL: "https://example.com/hook", Events: []string{"*"}},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Invalid request - unknown event",
+			input:          webhook.CreateSubscriptionRequest{URL: "https://example.com/hook", Events: []string{"friend.deleted.forever"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid request - no events",
+			input:          webhook.CreateSubscriptionRequest{URL: "https://example.com/hook", Events: []string{}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid request - bad url",
+			input:          webhook.CreateSubscriptionRequest{URL: "not a url", Events: []string{"*"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.input)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			withRequestValidation(http.MethodPost, "/api/v1/webhooks", handler.RegisterWebhookHandler).ServeHTTP(w, req)
+
+			res := w.Result()
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			if tt.expectedStatus == http.StatusCreated {
+				var body response.Envelope[webhook.Subscription]
+				json.NewDecoder(res.Body).Decode(&body)
+				assert.True(t, body.Success)
+				assert.Equal(t, "s3cr3t", body.Data.Secret)
+			}
+		})
+	}
+}
+
+// Test for unregistering a webhook subscription by id.
+func TestUnregisterWebhookHandler(t *testing.T) {
+	mockService := &MockWebhookService{
+		UnregisterFunc: func(ctx context.Context, id string) error {
+			if id != "sub-1" {
+				return domain.Errorf(domain.ErrWebhookNotFound, "webhook subscription not found with id %s", id)
+			}
+			return nil
+		},
+	}
+	handler := setupWebhookHandler(mockService)
+
+	r := chi.NewRouter()
+	r.Delete("/webhooks/{id}", handler.UnregisterWebhookHandler)
+
+	for id, expectedStatus := range map[string]int{"sub-1": http.StatusOK, "missing": http.StatusNotFound} {
+		req := httptest.NewRequest(http.MethodDelete, "/webhooks/"+id, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, expectedStatus, w.Code, id)
+	}
+}
+
+// Test for retrieving the delivery history of a webhook subscription.
+func TestListWebhookDeliveriesHandler(t *testing.T) {
+	statusCode := http.StatusOK
+	mockService := &MockWebhookService{
+		ListDeliveriesFunc: func(ctx context.Context, id string) ([]*webhook.Delivery, error) {
+			return []*webhook.Delivery{{
+				ID:             "del-1",
+				SubscriptionID: id,
+				Status:         webhook.StatusSucceeded,
+				History:        []*webhook.DeliveryAttempt{{Attempt: 1, StatusCode: &statusCode}},
+			}}, nil
+		},
+	}
+	handler := setupWebhookHandler(mockService)
+
+	r := chi.NewRouter()
+	r.Get("/webhooks/{id}/deliveries", handler.ListWebhookDeliveriesHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/sub-1/deliveries", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body response.Envelope[[]webhook.Delivery]
+	json.NewDecoder(w.Body).Decode(&body)
+	assert.Equal(t, 1, body.Meta.Count)
+	if assert.Len(t, body.Data, 1) {
+		assert.Equal(t, "sub-1", body.Data[0].SubscriptionID)
+		assert.Len(t, body.Data[0].History, 1)
+	}
+}
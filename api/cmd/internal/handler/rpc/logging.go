@@ -0,0 +1,42 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDMetadataKey carries the request ID in call metadata, as X-Request-ID does over HTTP.
+const RequestIDMetadataKey = "x-request-id"
+
+// loggingInterceptor assigns every call a request ID, reusing the caller's when it is valid,
+// and returns it in the response header. The ID is put in the context with a logger that adds
+// it to every record, and the call is logged with its status code once it completes.
+func loggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var id string
+		if values := metadata.ValueFromIncomingContext(ctx, RequestIDMetadataKey); len(values) > 0 {
+			id = values[0]
+		}
+		if !logging.ValidRequestID(id) {
+			id = logging.NewRequestID()
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, id))
+
+		ctx = logging.WithRequestID(logging.NewContext(ctx, logger), id)
+		started := time.Now()
+		resp, err := handler(ctx, req)
+
+		logging.FromContext(ctx).InfoContext(ctx, "call served",
+			slog.String("method", info.FullMethod),
+			slog.String("code", status.Code(err).String()),
+			slog.Duration("duration", time.Since(started)),
+		)
+		return resp, err
+	}
+}
@@ -0,0 +1,109 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/block"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/subscription"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+)
+
+// MockRelationshipController is a mock implementation of the relationship controller for testing purposes.
+// It allows defining custom behaviors for its methods using function types.
+type MockRelationshipController struct {
+	CreateFriendFunc               func(ctx context.Context, req *friend.CreateFriend) error
+	UnfriendFunc                   func(ctx context.Context, req *friend.CreateFriend) error
+	GetFriendListByEmailFunc       func(ctx context.Context, email string) ([]string, error)
+	GetCommonListFunc              func(ctx context.Context, req *friend.CommonFriendListReq) ([]string, error)
+	SubscribeFunc                  func(ctx context.Context, req *subscription.SubscribeRequest) error
+	UnsubscribeFunc                func(ctx context.Context, req *subscription.SubscribeRequest) error
+	BlockUpdatesFunc               func(ctx context.Context, req *block.BlockRequest) error
+	UnblockUpdatesFunc             func(ctx context.Context, req *block.BlockRequest) error
+	GetUpdatableEmailAddressesFunc func(ctx context.Context, req *subscription.RecipientRequest) ([]string, error)
+	PostUpdateFunc                 func(ctx context.Context, req *subscription.RecipientRequest) ([]string, error)
+	GetFriendListsByEmailsFunc     func(ctx context.Context, emails []string) (map[string][]string, error)
+	GetSubscribersByEmailsFunc     func(ctx context.Context, emails []string) (map[string][]string, error)
+	GetBlockedByEmailsFunc         func(ctx context.Context, emails []string) (map[string][]string, error)
+}
+
+// CreateFriend calls the custom CreateFriendFunc.
+func (m *MockRelationshipController) CreateFriend(ctx context.Context, req *friend.CreateFriend) error {
+	return m.CreateFriendFunc(ctx, req)
+}
+
+// Unfriend calls the custom UnfriendFunc.
+func (m *MockRelationshipController) Unfriend(ctx context.Context, req *friend.CreateFriend) error {
+	return m.UnfriendFunc(ctx, req)
+}
+
+// GetFriendListByEmail calls the custom GetFriendListByEmailFunc.
+func (m *MockRelationshipController) GetFriendListByEmail(ctx context.Context, email string) ([]string, error) {
+	return m.GetFriendListByEmailFunc(ctx, email)
+}
+
+// GetCommonList calls the custom GetCommonListFunc.
+func (m *MockRelationshipController) GetCommonList(ctx context.Context, req *friend.CommonFriendListReq) ([]string, error) {
+	return m.GetCommonListFunc(ctx, req)
+}
+
+// Subscribe calls the custom SubscribeFunc.
+func (m *MockRelationshipController) Subscribe(ctx context.Context, req *subscription.SubscribeRequest) error {
+	return m.SubscribeFunc(ctx, req)
+}
+
+// Unsubscribe calls the custom UnsubscribeFunc.
+func (m *MockRelationshipController) Unsubscribe(ctx context.Context, req *subscription.SubscribeRequest) error {
+	return m.UnsubscribeFunc(ctx, req)
+}
+
+// BlockUpdates calls the custom BlockUpdatesFunc.
+func (m *MockRelationshipController) BlockUpdates(ctx context.Context, req *block.BlockRequest) error {
+	return m.BlockUpdatesFunc(ctx, req)
+}
+
+// UnblockUpdates calls the custom UnblockUpdatesFunc.
+func (m *MockRelationshipController) UnblockUpdates(ctx context.Context, req *block.BlockRequest) error {
+	return m.UnblockUpdatesFunc(ctx, req)
+}
+
+// GetUpdatableEmailAddresses calls the custom GetUpdatableEmailAddressesFunc.
+func (m *MockRelationshipController) GetUpdatableEmailAddresses(ctx context.Context, req *subscription.RecipientRequest) ([]string, error) {
+	return m.GetUpdatableEmailAddressesFunc(ctx, req)
+}
+
+// PostUpdate calls the custom PostUpdateFunc.
+func (m *MockRelationshipController) PostUpdate(ctx context.Context, req *subscription.RecipientRequest) ([]string, error) {
+	return m.PostUpdateFunc(ctx, req)
+}
+
+// GetFriendListsByEmails calls the custom GetFriendListsByEmailsFunc.
+func (m *MockRelationshipController) GetFriendListsByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	return m.GetFriendListsByEmailsFunc(ctx, emails)
+}
+
+// GetSubscribersByEmails calls the custom GetSubscribersByEmailsFunc.
+func (m *MockRelationshipController) GetSubscribersByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	return m.GetSubscribersByEmailsFunc(ctx, emails)
+}
+
+// GetBlockedByEmails calls the custom GetBlockedByEmailsFunc.
+func (m *MockRelationshipController) GetBlockedByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	return m.GetBlockedByEmailsFunc(ctx, emails)
+}
+
+// MockUserController is a mock implementation of the user controller for testing purposes.
+type MockUserController struct {
+	CreateUserFunc     func(ctx context.Context, req *user.CreateUser) error
+	GetUserByEmailFunc func(ctx context.Context, email string) (*user.User, error)
+}
+
+// CreateUser calls the custom CreateUserFunc.
+func (m *MockUserController) CreateUser(ctx context.Context, req *user.CreateUser) error {
+	return m.CreateUserFunc(ctx, req)
+}
+
+// GetUserByEmail calls the custom GetUserByEmailFunc.
+func (m *MockUserController) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	return m.GetUserByEmailFunc(ctx, email)
+}
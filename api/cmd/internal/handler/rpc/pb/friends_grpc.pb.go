@@ -0,0 +1,645 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: friends/v1/friends.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	UserService_CreateUser_FullMethodName = "/friends.v1.UserService/CreateUser"
+	UserService_GetUser_FullMethodName    = "/friends.v1.UserService/GetUser"
+)
+
+// UserServiceClient is the client API for UserService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UserService manages the users relationships are formed between.
+type UserServiceClient interface {
+	// CreateUser registers a user. Fails with ALREADY_EXISTS when the email is taken.
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error)
+	// GetUser looks a user up by email. Fails with NOT_FOUND when there is none.
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
+}
+
+type userServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserServiceClient(cc grpc.ClientConnInterface) UserServiceClient {
+	return &userServiceClient{cc}
+}
+
+func (c *userServiceClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateUserResponse)
+	err := c.cc.Invoke(ctx, UserService_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserResponse)
+	err := c.cc.Invoke(ctx, UserService_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserServiceServer is the server API for UserService service.
+// All implementations must embed UnimplementedUserServiceServer
+// for forward compatibility.
+//
+// UserService manages the users relationships are formed between.
+type UserServiceServer interface {
+	// CreateUser registers a user. Fails with ALREADY_EXISTS when the email is taken.
+	CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error)
+	// GetUser looks a user up by email. Fails with NOT_FOUND when there is none.
+	GetUser(context.Context, *GetUserRequest) (*GetUserResponse, error)
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+// UnimplementedUserServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserServiceServer struct{}
+
+func (UnimplementedUserServiceServer) CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedUserServiceServer) GetUser(context.Context, *GetUserRequest) (*GetUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
+func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserServiceServer will
+// result in compilation errors.
+type UnsafeUserServiceServer interface {
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+func RegisterUserServiceServer(s grpc.ServiceRegistrar, srv UserServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUserServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserService_ServiceDesc, srv)
+}
+
+func _UserService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "friends.v1.UserService",
+	HandlerType: (*UserServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateUser",
+			Handler:    _UserService_CreateUser_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _UserService_GetUser_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "friends/v1/friends.proto",
+}
+
+const (
+	RelationshipService_CreateFriend_FullMethodName      = "/friends.v1.RelationshipService/CreateFriend"
+	RelationshipService_RemoveFriend_FullMethodName      = "/friends.v1.RelationshipService/RemoveFriend"
+	RelationshipService_ListFriends_FullMethodName       = "/friends.v1.RelationshipService/ListFriends"
+	RelationshipService_ListCommonFriends_FullMethodName = "/friends.v1.RelationshipService/ListCommonFriends"
+	RelationshipService_Subscribe_FullMethodName         = "/friends.v1.RelationshipService/Subscribe"
+	RelationshipService_Unsubscribe_FullMethodName       = "/friends.v1.RelationshipService/Unsubscribe"
+	RelationshipService_BlockUpdates_FullMethodName      = "/friends.v1.RelationshipService/BlockUpdates"
+	RelationshipService_UnblockUpdates_FullMethodName    = "/friends.v1.RelationshipService/UnblockUpdates"
+	RelationshipService_ListRecipients_FullMethodName    = "/friends.v1.RelationshipService/ListRecipients"
+	RelationshipService_PostUpdate_FullMethodName        = "/friends.v1.RelationshipService/PostUpdate"
+)
+
+// RelationshipServiceClient is the client API for RelationshipService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RelationshipService manages friendships, subscriptions and blocks between users.
+// Requests are validated with the same rules as the REST API; invalid fields are
+// reported as INVALID_ARGUMENT with a google.rpc.BadRequest detail.
+type RelationshipServiceClient interface {
+	// CreateFriend makes two users friends. Fails with ALREADY_EXISTS when they already are
+	// and FAILED_PRECONDITION when either blocks the other.
+	CreateFriend(ctx context.Context, in *CreateFriendRequest, opts ...grpc.CallOption) (*CreateFriendResponse, error)
+	// RemoveFriend ends the friendship of two users. Fails with NOT_FOUND when they are not friends.
+	RemoveFriend(ctx context.Context, in *RemoveFriendRequest, opts ...grpc.CallOption) (*RemoveFriendResponse, error)
+	// ListFriends returns the friends of a user.
+	ListFriends(ctx context.Context, in *ListFriendsRequest, opts ...grpc.CallOption) (*ListFriendsResponse, error)
+	// ListCommonFriends returns the friends two users have in common.
+	ListCommonFriends(ctx context.Context, in *ListCommonFriendsRequest, opts ...grpc.CallOption) (*ListCommonFriendsResponse, error)
+	// Subscribe subscribes the requestor to updates from the target.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (*SubscribeResponse, error)
+	// Unsubscribe removes the requestor's subscription. Fails with NOT_FOUND when there is none.
+	Unsubscribe(ctx context.Context, in *UnsubscribeRequest, opts ...grpc.CallOption) (*UnsubscribeResponse, error)
+	// BlockUpdates blocks updates from the target to the requestor.
+	BlockUpdates(ctx context.Context, in *BlockUpdatesRequest, opts ...grpc.CallOption) (*BlockUpdatesResponse, error)
+	// UnblockUpdates removes the requestor's block. Fails with NOT_FOUND when there is none.
+	UnblockUpdates(ctx context.Context, in *UnblockUpdatesRequest, opts ...grpc.CallOption) (*UnblockUpdatesResponse, error)
+	// ListRecipients returns every email address that receives an update from the sender,
+	// including the addresses mentioned in its text. It records nothing.
+	ListRecipients(ctx context.Context, in *ListRecipientsRequest, opts ...grpc.CallOption) (*ListRecipientsResponse, error)
+	// PostUpdate posts an update from the sender and returns the email addresses that receive it,
+	// like ListRecipients. The update is recorded as an update.posted event.
+	PostUpdate(ctx context.Context, in *PostUpdateRequest, opts ...grpc.CallOption) (*PostUpdateResponse, error)
+}
+
+type relationshipServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRelationshipServiceClient(cc grpc.ClientConnInterface) RelationshipServiceClient {
+	return &relationshipServiceClient{cc}
+}
+
+func (c *relationshipServiceClient) CreateFriend(ctx context.Context, in *CreateFriendRequest, opts ...grpc.CallOption) (*CreateFriendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateFriendResponse)
+	err := c.cc.Invoke(ctx, RelationshipService_CreateFriend_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relationshipServiceClient) RemoveFriend(ctx context.Context, in *RemoveFriendRequest, opts ...grpc.CallOption) (*RemoveFriendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveFriendResponse)
+	err := c.cc.Invoke(ctx, RelationshipService_RemoveFriend_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relationshipServiceClient) ListFriends(ctx context.Context, in *ListFriendsRequest, opts ...grpc.CallOption) (*ListFriendsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFriendsResponse)
+	err := c.cc.Invoke(ctx, RelationshipService_ListFriends_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relationshipServiceClient) ListCommonFriends(ctx context.Context, in *ListCommonFriendsRequest, opts ...grpc.CallOption) (*ListCommonFriendsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCommonFriendsResponse)
+	err := c.cc.Invoke(ctx, RelationshipService_ListCommonFriends_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relationshipServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (*SubscribeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubscribeResponse)
+	err := c.cc.Invoke(ctx, RelationshipService_Subscribe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relationshipServiceClient) Unsubscribe(ctx context.Context, in *UnsubscribeRequest, opts ...grpc.CallOption) (*UnsubscribeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnsubscribeResponse)
+	err := c.cc.Invoke(ctx, RelationshipService_Unsubscribe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relationshipServiceClient) BlockUpdates(ctx context.Context, in *BlockUpdatesRequest, opts ...grpc.CallOption) (*BlockUpdatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BlockUpdatesResponse)
+	err := c.cc.Invoke(ctx, RelationshipService_BlockUpdates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relationshipServiceClient) UnblockUpdates(ctx context.Context, in *UnblockUpdatesRequest, opts ...grpc.CallOption) (*UnblockUpdatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnblockUpdatesResponse)
+	err := c.cc.Invoke(ctx, RelationshipService_UnblockUpdates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relationshipServiceClient) ListRecipients(ctx context.Context, in *ListRecipientsRequest, opts ...grpc.CallOption) (*ListRecipientsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRecipientsResponse)
+	err := c.cc.Invoke(ctx, RelationshipService_ListRecipients_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relationshipServiceClient) PostUpdate(ctx context.Context, in *PostUpdateRequest, opts ...grpc.CallOption) (*PostUpdateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PostUpdateResponse)
+	err := c.cc.Invoke(ctx, RelationshipService_PostUpdate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RelationshipServiceServer is the server API for RelationshipService service.
+// All implementations must embed UnimplementedRelationshipServiceServer
+// for forward compatibility.
+//
+// RelationshipService manages friendships, subscriptions and blocks between users.
+// Requests are validated with the same rules as the REST API; invalid fields are
+// reported as INVALID_ARGUMENT with a google.rpc.BadRequest detail.
+type RelationshipServiceServer interface {
+	// CreateFriend makes two users friends. Fails with ALREADY_EXISTS when they already are
+	// and FAILED_PRECONDITION when either blocks the other.
+	CreateFriend(context.Context, *CreateFriendRequest) (*CreateFriendResponse, error)
+	// RemoveFriend ends the friendship of two users. Fails with NOT_FOUND when they are not friends.
+	RemoveFriend(context.Context, *RemoveFriendRequest) (*RemoveFriendResponse, error)
+	// ListFriends returns the friends of a user.
+	ListFriends(context.Context, *ListFriendsRequest) (*ListFriendsResponse, error)
+	// ListCommonFriends returns the friends two users have in common.
+	ListCommonFriends(context.Context, *ListCommonFriendsRequest) (*ListCommonFriendsResponse, error)
+	// Subscribe subscribes the requestor to updates from the target.
+	Subscribe(context.Context, *SubscribeRequest) (*SubscribeResponse, error)
+	// Unsubscribe removes the requestor's subscription. Fails with NOT_FOUND when there is none.
+	Unsubscribe(context.Context, *UnsubscribeRequest) (*UnsubscribeResponse, error)
+	// BlockUpdates blocks updates from the target to the requestor.
+	BlockUpdates(context.Context, *BlockUpdatesRequest) (*BlockUpdatesResponse, error)
+	// UnblockUpdates removes the requestor's block. Fails with NOT_FOUND when there is none.
+	UnblockUpdates(context.Context, *UnblockUpdatesRequest) (*UnblockUpdatesResponse, error)
+	// ListRecipients returns every email address that receives an update from the sender,
+	// including the addresses mentioned in its text. It records nothing.
+	ListRecipients(context.Context, *ListRecipientsRequest) (*ListRecipientsResponse, error)
+	// PostUpdate posts an update from the sender and returns the email addresses that receive it,
+	// like ListRecipients. The update is recorded as an update.posted event.
+	PostUpdate(context.Context, *PostUpdateRequest) (*PostUpdateResponse, error)
+	mustEmbedUnimplementedRelationshipServiceServer()
+}
+
+// UnimplementedRelationshipServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRelationshipServiceServer struct{}
+
+func (UnimplementedRelationshipServiceServer) CreateFriend(context.Context, *CreateFriendRequest) (*CreateFriendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFriend not implemented")
+}
+func (UnimplementedRelationshipServiceServer) RemoveFriend(context.Context, *RemoveFriendRequest) (*RemoveFriendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveFriend not implemented")
+}
+func (UnimplementedRelationshipServiceServer) ListFriends(context.Context, *ListFriendsRequest) (*ListFriendsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFriends not implemented")
+}
+func (UnimplementedRelationshipServiceServer) ListCommonFriends(context.Context, *ListCommonFriendsRequest) (*ListCommonFriendsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCommonFriends not implemented")
+}
+func (UnimplementedRelationshipServiceServer) Subscribe(context.Context, *SubscribeRequest) (*SubscribeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedRelationshipServiceServer) Unsubscribe(context.Context, *UnsubscribeRequest) (*UnsubscribeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unsubscribe not implemented")
+}
+func (UnimplementedRelationshipServiceServer) BlockUpdates(context.Context, *BlockUpdatesRequest) (*BlockUpdatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockUpdates not implemented")
+}
+func (UnimplementedRelationshipServiceServer) UnblockUpdates(context.Context, *UnblockUpdatesRequest) (*UnblockUpdatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnblockUpdates not implemented")
+}
+func (UnimplementedRelationshipServiceServer) ListRecipients(context.Context, *ListRecipientsRequest) (*ListRecipientsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecipients not implemented")
+}
+func (UnimplementedRelationshipServiceServer) PostUpdate(context.Context, *PostUpdateRequest) (*PostUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PostUpdate not implemented")
+}
+func (UnimplementedRelationshipServiceServer) mustEmbedUnimplementedRelationshipServiceServer() {}
+func (UnimplementedRelationshipServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeRelationshipServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RelationshipServiceServer will
+// result in compilation errors.
+type UnsafeRelationshipServiceServer interface {
+	mustEmbedUnimplementedRelationshipServiceServer()
+}
+
+func RegisterRelationshipServiceServer(s grpc.ServiceRegistrar, srv RelationshipServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRelationshipServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RelationshipService_ServiceDesc, srv)
+}
+
+func _RelationshipService_CreateFriend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFriendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelationshipServiceServer).CreateFriend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RelationshipService_CreateFriend_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelationshipServiceServer).CreateFriend(ctx, req.(*CreateFriendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelationshipService_RemoveFriend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveFriendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelationshipServiceServer).RemoveFriend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RelationshipService_RemoveFriend_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelationshipServiceServer).RemoveFriend(ctx, req.(*RemoveFriendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelationshipService_ListFriends_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFriendsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelationshipServiceServer).ListFriends(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RelationshipService_ListFriends_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelationshipServiceServer).ListFriends(ctx, req.(*ListFriendsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelationshipService_ListCommonFriends_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCommonFriendsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelationshipServiceServer).ListCommonFriends(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RelationshipService_ListCommonFriends_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelationshipServiceServer).ListCommonFriends(ctx, req.(*ListCommonFriendsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelationshipService_Subscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubscribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelationshipServiceServer).Subscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RelationshipService_Subscribe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelationshipServiceServer).Subscribe(ctx, req.(*SubscribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelationshipService_Unsubscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnsubscribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelationshipServiceServer).Unsubscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RelationshipService_Unsubscribe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelationshipServiceServer).Unsubscribe(ctx, req.(*UnsubscribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelationshipService_BlockUpdates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockUpdatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelationshipServiceServer).BlockUpdates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RelationshipService_BlockUpdates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelationshipServiceServer).BlockUpdates(ctx, req.(*BlockUpdatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelationshipService_UnblockUpdates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnblockUpdatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelationshipServiceServer).UnblockUpdates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RelationshipService_UnblockUpdates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelationshipServiceServer).UnblockUpdates(ctx, req.(*UnblockUpdatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelationshipService_ListRecipients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRecipientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelationshipServiceServer).ListRecipients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RelationshipService_ListRecipients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelationshipServiceServer).ListRecipients(ctx, req.(*ListRecipientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelationshipService_PostUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PostUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelationshipServiceServer).PostUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RelationshipService_PostUpdate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelationshipServiceServer).PostUpdate(ctx, req.(*PostUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RelationshipService_ServiceDesc is the grpc.ServiceDesc for RelationshipService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RelationshipService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "friends.v1.RelationshipService",
+	HandlerType: (*RelationshipServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateFriend",
+			Handler:    _RelationshipService_CreateFriend_Handler,
+		},
+		{
+			MethodName: "RemoveFriend",
+			Handler:    _RelationshipService_RemoveFriend_Handler,
+		},
+		{
+			MethodName: "ListFriends",
+			Handler:    _RelationshipService_ListFriends_Handler,
+		},
+		{
+			MethodName: "ListCommonFriends",
+			Handler:    _RelationshipService_ListCommonFriends_Handler,
+		},
+		{
+			MethodName: "Subscribe",
+			Handler:    _RelationshipService_Subscribe_Handler,
+		},
+		{
+			MethodName: "Unsubscribe",
+			Handler:    _RelationshipService_Unsubscribe_Handler,
+		},
+		{
+			MethodName: "BlockUpdates",
+			Handler:    _RelationshipService_BlockUpdates_Handler,
+		},
+		{
+			MethodName: "UnblockUpdates",
+			Handler:    _RelationshipService_UnblockUpdates_Handler,
+		},
+		{
+			MethodName: "ListRecipients",
+			Handler:    _RelationshipService_ListRecipients_Handler,
+		},
+		{
+			MethodName: "PostUpdate",
+			Handler:    _RelationshipService_PostUpdate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "friends/v1/friends.proto",
+}
@@ -0,0 +1,147 @@
+package rpc
+
+import (
+	"context"
+
+	relationshipCtrl "github.com/koeylp/friends-management/cmd/internal/controller/relationship"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rpc/pb"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/block"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/subscription"
+)
+
+// RelationshipServer serves the RelationshipService over gRPC. Requests are converted to the
+// DTOs the REST handlers use and validated with the same rules before reaching the controller.
+type RelationshipServer struct {
+	pb.UnimplementedRelationshipServiceServer
+	relationshipCtrl relationshipCtrl.RelationshipController
+}
+
+// NewRelationshipServer initializes a new RelationshipServer with the provided controller.
+func NewRelationshipServer(relationshipCtrl relationshipCtrl.RelationshipController) *RelationshipServer {
+	return &RelationshipServer{relationshipCtrl: relationshipCtrl}
+}
+
+// CreateFriend makes two users friends.
+func (s *RelationshipServer) CreateFriend(ctx context.Context, req *pb.CreateFriendRequest) (*pb.CreateFriendResponse, error) {
+	createFriendReq := &friend.CreateFriend{Friends: req.GetFriends()}
+	if err := friend.ValidateCreateFriendRequest(createFriendReq); err != nil {
+		return nil, err
+	}
+	if err := s.relationshipCtrl.CreateFriend(ctx, createFriendReq); err != nil {
+		return nil, err
+	}
+	return &pb.CreateFriendResponse{}, nil
+}
+
+// RemoveFriend ends the friendship of two users.
+func (s *RelationshipServer) RemoveFriend(ctx context.Context, req *pb.RemoveFriendRequest) (*pb.RemoveFriendResponse, error) {
+	friendReq := &friend.CreateFriend{Friends: req.GetFriends()}
+	if err := friend.ValidateCreateFriendRequest(friendReq); err != nil {
+		return nil, err
+	}
+	if err := s.relationshipCtrl.Unfriend(ctx, friendReq); err != nil {
+		return nil, err
+	}
+	return &pb.RemoveFriendResponse{}, nil
+}
+
+// ListFriends returns the friends of a user.
+func (s *RelationshipServer) ListFriends(ctx context.Context, req *pb.ListFriendsRequest) (*pb.ListFriendsResponse, error) {
+	emailReq := &friend.EmailRequest{Email: req.GetEmail()}
+	if err := friend.ValidateEmailRequest(emailReq); err != nil {
+		return nil, err
+	}
+	friends, err := s.relationshipCtrl.GetFriendListByEmail(ctx, emailReq.Email)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ListFriendsResponse{Friends: friends}, nil
+}
+
+// ListCommonFriends returns the friends two users have in common.
+func (s *RelationshipServer) ListCommonFriends(ctx context.Context, req *pb.ListCommonFriendsRequest) (*pb.ListCommonFriendsResponse, error) {
+	commonFriendsReq := &friend.CommonFriendListReq{Friends: req.GetFriends()}
+	if err := friend.ValidateCommonFriendListRequest(commonFriendsReq); err != nil {
+		return nil, err
+	}
+	commonList, err := s.relationshipCtrl.GetCommonList(ctx, commonFriendsReq)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ListCommonFriendsResponse{Friends: commonList}, nil
+}
+
+// Subscribe subscribes the requestor to updates from the target.
+func (s *RelationshipServer) Subscribe(ctx context.Context, req *pb.SubscribeRequest) (*pb.SubscribeResponse, error) {
+	subscribeReq := &subscription.SubscribeRequest{Requestor: req.GetRequestor(), Target: req.GetTarget()}
+	if err := subscription.ValidateSubscribeRequest(subscribeReq); err != nil {
+		return nil, err
+	}
+	if err := s.relationshipCtrl.Subscribe(ctx, subscribeReq); err != nil {
+		return nil, err
+	}
+	return &pb.SubscribeResponse{}, nil
+}
+
+// Unsubscribe removes the requestor's subscription to the target.
+func (s *RelationshipServer) Unsubscribe(ctx context.Context, req *pb.UnsubscribeRequest) (*pb.UnsubscribeResponse, error) {
+	subscribeReq := &subscription.SubscribeRequest{Requestor: req.GetRequestor(), Target: req.GetTarget()}
+	if err := subscription.ValidateSubscribeRequest(subscribeReq); err != nil {
+		return nil, err
+	}
+	if err := s.relationshipCtrl.Unsubscribe(ctx, subscribeReq); err != nil {
+		return nil, err
+	}
+	return &pb.UnsubscribeResponse{}, nil
+}
+
+// BlockUpdates blocks updates from the target to the requestor.
+func (s *RelationshipServer) BlockUpdates(ctx context.Context, req *pb.BlockUpdatesRequest) (*pb.BlockUpdatesResponse, error) {
+	blockReq := &block.BlockRequest{Requestor: req.GetRequestor(), Target: req.GetTarget()}
+	if err := block.ValidateBlockRequest(blockReq); err != nil {
+		return nil, err
+	}
+	if err := s.relationshipCtrl.BlockUpdates(ctx, blockReq); err != nil {
+		return nil, err
+	}
+	return &pb.BlockUpdatesResponse{}, nil
+}
+
+// UnblockUpdates removes the requestor's block on the target.
+func (s *RelationshipServer) UnblockUpdates(ctx context.Context, req *pb.UnblockUpdatesRequest) (*pb.UnblockUpdatesResponse, error) {
+	blockReq := &block.BlockRequest{Requestor: req.GetRequestor(), Target: req.GetTarget()}
+	if err := block.ValidateBlockRequest(blockReq); err != nil {
+		return nil, err
+	}
+	if err := s.relationshipCtrl.UnblockUpdates(ctx, blockReq); err != nil {
+		return nil, err
+	}
+	return &pb.UnblockUpdatesResponse{}, nil
+}
+
+// ListRecipients returns every email address that receives an update from the sender. It records nothing.
+func (s *RelationshipServer) ListRecipients(ctx context.Context, req *pb.ListRecipientsRequest) (*pb.ListRecipientsResponse, error) {
+	recipientsReq := &subscription.RecipientRequest{Sender: req.GetSender(), Text: req.GetText()}
+	if err := subscription.ValidateRecipientRequest(recipientsReq); err != nil {
+		return nil, err
+	}
+	recipients, err := s.relationshipCtrl.GetUpdatableEmailAddresses(ctx, recipientsReq)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ListRecipientsResponse{Recipients: recipients}, nil
+}
+
+// PostUpdate posts an update from the sender and returns every email address that receives it.
+func (s *RelationshipServer) PostUpdate(ctx context.Context, req *pb.PostUpdateRequest) (*pb.PostUpdateResponse, error) {
+	updateReq := &subscription.RecipientRequest{Sender: req.GetSender(), Text: req.GetText()}
+	if err := subscription.ValidateRecipientRequest(updateReq); err != nil {
+		return nil, err
+	}
+	recipients, err := s.relationshipCtrl.PostUpdate(ctx, updateReq)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PostUpdateResponse{Recipients: recipients}, nil
+}
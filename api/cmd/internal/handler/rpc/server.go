@@ -0,0 +1,20 @@
+package rpc
+
+import (
+	"log/slog"
+
+	"github.com/koeylp/friends-management/cmd/internal/handler/rpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer builds the gRPC server exposing the user and relationship services.
+// Server reflection is enabled so tools such as grpcurl can discover the services.
+// Every call is logged through logger with its request ID.
+func NewServer(userServer *UserServer, relationshipServer *RelationshipServer, logger *slog.Logger) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(loggingInterceptor(logger), errorInterceptor))
+	pb.RegisterUserServiceServer(server, userServer)
+	pb.RegisterRelationshipServiceServer(server, relationshipServer)
+	reflection.Register(server)
+	return server
+}
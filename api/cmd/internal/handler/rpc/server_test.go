@@ -0,0 +1,238 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/handler/rpc/pb"
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/block"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/friend"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/relationship/subscription"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// setupClients serves the mock controllers over an in-memory connection and returns clients for both services.
+func setupClients(t *testing.T, relationshipCtrl *MockRelationshipController, userCtrl *MockUserController) (pb.RelationshipServiceClient, pb.UserServiceClient) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(NewUserServer(userCtrl), NewRelationshipServer(relationshipCtrl), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewRelationshipServiceClient(conn), pb.NewUserServiceClient(conn)
+}
+
+// Test that a valid request reaches the controller as the REST DTO.
+func TestCreateFriend(t *testing.T) {
+	var received *friend.CreateFriend
+	relationships, _ := setupClients(t, &MockRelationshipController{
+		CreateFriendFunc: func(ctx context.Context, req *friend.CreateFriend) error {
+			received = req
+			return nil
+		},
+	}, &MockUserController{})
+
+	_, err := relationships.CreateFriend(context.Background(), &pb.CreateFriendRequest{Friends: []string{"andy@example.com", "john@example.com"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"andy@example.com", "john@example.com"}, received.Friends)
+}
+
+// Test that RemoveFriend reaches the controller's Unfriend.
+func TestRemoveFriend(t *testing.T) {
+	var received *friend.CreateFriend
+	relationships, _ := setupClients(t, &MockRelationshipController{
+		UnfriendFunc: func(ctx context.Context, req *friend.CreateFriend) error {
+			received = req
+			return nil
+		},
+	}, &MockUserController{})
+
+	_, err := relationships.RemoveFriend(context.Background(), &pb.RemoveFriendRequest{Friends: []string{"andy@example.com", "john@example.com"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"andy@example.com", "john@example.com"}, received.Friends)
+}
+
+// Test that invalid fields are reported as INVALID_ARGUMENT with a BadRequest detail.
+func TestCreateFriend_InvalidArgument(t *testing.T) {
+	relationships, _ := setupClients(t, &MockRelationshipController{}, &MockUserController{})
+
+	_, err := relationships.CreateFriend(context.Background(), &pb.CreateFriendRequest{Friends: []string{"andy@example.com"}})
+
+	s := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, s.Code())
+	assert.Equal(t, "friends must contain exactly 2 items", s.Message())
+	require.Len(t, s.Details(), 1)
+	badRequest, ok := s.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	assert.Equal(t, "friends", badRequest.FieldViolations[0].Field)
+}
+
+// Test that controller errors are mapped to status codes for every relationship RPC.
+func TestRelationshipService_StatusCodes(t *testing.T) {
+	relationships, _ := setupClients(t, &MockRelationshipController{
+		GetFriendListByEmailFunc: func(ctx context.Context, email string) ([]string, error) {
+			return nil, domain.Errorf(domain.ErrUserNotFound, "user not found with email %s", email)
+		},
+		SubscribeFunc: func(ctx context.Context, req *subscription.SubscribeRequest) error {
+			return domain.Errorf(domain.ErrAlreadySubscribed, "subscription already exists")
+		},
+		UnsubscribeFunc: func(ctx context.Context, req *subscription.SubscribeRequest) error {
+			return domain.Errorf(domain.ErrNotSubscribed, "subscription not found")
+		},
+		BlockUpdatesFunc: func(ctx context.Context, req *block.BlockRequest) error {
+			return errors.New("database error")
+		},
+		CreateFriendFunc: func(ctx context.Context, req *friend.CreateFriend) error {
+			return domain.Errorf(domain.ErrBlocked, "blocking updates exists")
+		},
+		UnfriendFunc: func(ctx context.Context, req *friend.CreateFriend) error {
+			return domain.Errorf(domain.ErrNotFriends, "%s and %s are not friends", req.Friends[0], req.Friends[1])
+		},
+	}, &MockUserController{})
+	ctx := context.Background()
+
+	_, err := relationships.ListFriends(ctx, &pb.ListFriendsRequest{Email: "andy@example.com"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "user not found with email andy@example.com", status.Convert(err).Message())
+
+	_, err = relationships.Subscribe(ctx, &pb.SubscribeRequest{Requestor: "andy@example.com", Target: "john@example.com"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = relationships.Unsubscribe(ctx, &pb.UnsubscribeRequest{Requestor: "andy@example.com", Target: "john@example.com"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = relationships.BlockUpdates(ctx, &pb.BlockUpdatesRequest{Requestor: "andy@example.com", Target: "john@example.com"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "internal error", status.Convert(err).Message())
+
+	_, err = relationships.CreateFriend(ctx, &pb.CreateFriendRequest{Friends: []string{"andy@example.com", "john@example.com"}})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = relationships.UnblockUpdates(ctx, &pb.UnblockUpdatesRequest{Requestor: "andy@example.com", Target: "andy@example.com"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = relationships.RemoveFriend(ctx, &pb.RemoveFriendRequest{Friends: []string{"andy@example.com", "john@example.com"}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "andy@example.com and john@example.com are not friends", status.Convert(err).Message())
+
+	_, err = relationships.RemoveFriend(ctx, &pb.RemoveFriendRequest{Friends: []string{"andy@example.com"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// Test the list RPCs return what the controller found, and that listing recipients posts nothing.
+func TestRelationshipService_Lists(t *testing.T) {
+	relationships, _ := setupClients(t, &MockRelationshipController{
+		PostUpdateFunc: func(ctx context.Context, req *subscription.RecipientRequest) ([]string, error) {
+			t.Error("ListRecipients must not post the update")
+			return nil, nil
+		},
+		GetCommonListFunc: func(ctx context.Context, req *friend.CommonFriendListReq) ([]string, error) {
+			return []string{"common@example.com"}, nil
+		},
+		GetUpdatableEmailAddressesFunc: func(ctx context.Context, req *subscription.RecipientRequest) ([]string, error) {
+			return []string{"lisa@example.com", "kate@example.com"}, nil
+		},
+	}, &MockUserController{})
+	ctx := context.Background()
+
+	common, err := relationships.ListCommonFriends(ctx, &pb.ListCommonFriendsRequest{Friends: []string{"andy@example.com", "john@example.com"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"common@example.com"}, common.GetFriends())
+
+	recipients, err := relationships.ListRecipients(ctx, &pb.ListRecipientsRequest{Sender: "john@example.com", Text: "Hello kate@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lisa@example.com", "kate@example.com"}, recipients.GetRecipients())
+}
+
+// Test that PostUpdate posts the update through the controller and returns its recipients.
+func TestRelationshipService_PostUpdate(t *testing.T) {
+	var posted *subscription.RecipientRequest
+	relationships, _ := setupClients(t, &MockRelationshipController{
+		PostUpdateFunc: func(ctx context.Context, req *subscription.RecipientRequest) ([]string, error) {
+			posted = req
+			return []string{"lisa@example.com"}, nil
+		},
+	}, &MockUserController{})
+	ctx := context.Background()
+
+	resp, err := relationships.PostUpdate(ctx, &pb.PostUpdateRequest{Sender: "john@example.com", Text: "Hello"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lisa@example.com"}, resp.GetRecipients())
+	assert.Equal(t, &subscription.RecipientRequest{Sender: "john@example.com", Text: "Hello"}, posted)
+
+	_, err = relationships.PostUpdate(ctx, &pb.PostUpdateRequest{Sender: "john"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// Test creating and looking up users.
+func TestUserService(t *testing.T) {
+	createdAt := time.Date(2024, 10, 24, 17, 0, 0, 0, time.UTC)
+	_, users := setupClients(t, &MockRelationshipController{}, &MockUserController{
+		CreateUserFunc: func(ctx context.Context, req *user.CreateUser) error {
+			return domain.Errorf(domain.ErrUserExists, "user already exists with email %s", req.Email)
+		},
+		GetUserByEmailFunc: func(ctx context.Context, email string) (*user.User, error) {
+			return &user.User{ID: "1", Email: email, CreatedAt: createdAt, UpdatedAt: createdAt}, nil
+		},
+	})
+	ctx := context.Background()
+
+	_, err := users.CreateUser(ctx, &pb.CreateUserRequest{Email: "andy@example.com"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	resp, err := users.GetUser(ctx, &pb.GetUserRequest{Email: "andy@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "1", resp.GetUser().GetId())
+	assert.Equal(t, "andy@example.com", resp.GetUser().GetEmail())
+	assert.True(t, createdAt.Equal(resp.GetUser().GetCreatedAt().AsTime()))
+}
+
+// Test that the caller's request ID reaches the controller and is returned, and that one is generated otherwise.
+func TestRequestID(t *testing.T) {
+	var received string
+	_, users := setupClients(t, &MockRelationshipController{}, &MockUserController{
+		GetUserByEmailFunc: func(ctx context.Context, email string) (*user.User, error) {
+			received = logging.RequestID(ctx)
+			return &user.User{ID: "1", Email: email}, nil
+		},
+	})
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), RequestIDMetadataKey, "req-42")
+	_, err := users.GetUser(ctx, &pb.GetUserRequest{Email: "andy@example.com"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, "req-42", received)
+	assert.Equal(t, []string{"req-42"}, header.Get(RequestIDMetadataKey))
+
+	_, err = users.GetUser(context.Background(), &pb.GetUserRequest{Email: "andy@example.com"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.NotEmpty(t, received)
+	assert.NotEqual(t, "req-42", received)
+	assert.Equal(t, []string{received}, header.Get(RequestIDMetadataKey))
+}
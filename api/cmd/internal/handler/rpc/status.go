@@ -0,0 +1,93 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	validation "github.com/koeylp/friends-management/cmd/internal/pkg/validation_util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorInterceptor lets the servers return domain and validation errors as they come from the
+// controllers and converts them to gRPC statuses in one place, as HandleError does for REST.
+// Internal errors are logged with their detail through the call's logger.
+func errorInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		s := toStatus(err)
+		if s.Code() == codes.Internal {
+			logging.FromContext(ctx).ErrorContext(ctx, "call failed", "method", info.FullMethod, "error", err)
+		}
+		return nil, s.Err()
+	}
+	return resp, nil
+}
+
+// internalMessage is the message of every INTERNAL status.
+const internalMessage = "internal error"
+
+// toStatus translates an error to a gRPC status:
+// - validation errors as INVALID_ARGUMENT with a BadRequest detail listing every invalid field.
+// - ErrInvalidArgument as INVALID_ARGUMENT.
+// - ErrUserNotFound, ErrWebhookNotFound, ErrNotFriends, ErrNotSubscribed and ErrNotBlocked as NOT_FOUND.
+// - ErrUserExists, ErrAlreadyFriends, ErrAlreadySubscribed and ErrAlreadyBlocked as ALREADY_EXISTS.
+// - ErrBlocked as FAILED_PRECONDITION.
+// - context cancellation and deadlines as CANCELED and DEADLINE_EXCEEDED.
+// - any other error as INTERNAL.
+// The status message is the error message, except for INTERNAL, whose message is a fixed
+// "internal error" so storage and driver details do not reach the client.
+func toStatus(err error) *status.Status {
+	if s, ok := status.FromError(err); ok {
+		return s
+	}
+	if fieldErrs := validation.FieldErrors(err); len(fieldErrs) > 0 {
+		return invalidArgument(fieldErrs)
+	}
+
+	message := err.Error()
+	switch {
+	case errors.Is(err, domain.ErrInvalidArgument):
+		return status.New(codes.InvalidArgument, message)
+	case errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrWebhookNotFound),
+		errors.Is(err, domain.ErrNotFriends),
+		errors.Is(err, domain.ErrNotSubscribed),
+		errors.Is(err, domain.ErrNotBlocked):
+		return status.New(codes.NotFound, message)
+	case errors.Is(err, domain.ErrUserExists),
+		errors.Is(err, domain.ErrAlreadyFriends),
+		errors.Is(err, domain.ErrAlreadySubscribed),
+		errors.Is(err, domain.ErrAlreadyBlocked):
+		return status.New(codes.AlreadyExists, message)
+	case errors.Is(err, domain.ErrBlocked):
+		return status.New(codes.FailedPrecondition, message)
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, message)
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, message)
+	default:
+		return status.New(codes.Internal, internalMessage)
+	}
+}
+
+// invalidArgument reports the fields that failed validation.
+func invalidArgument(fieldErrs []validation.FieldError) *status.Status {
+	messages := make([]string, len(fieldErrs))
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(fieldErrs))
+	for i, fieldErr := range fieldErrs {
+		messages[i] = fieldErr.Message
+		violations[i] = &errdetails.BadRequest_FieldViolation{Field: fieldErr.Field, Description: fieldErr.Message}
+	}
+
+	s := status.New(codes.InvalidArgument, strings.Join(messages, "; "))
+	if detailed, err := s.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		return detailed
+	}
+	return s
+}
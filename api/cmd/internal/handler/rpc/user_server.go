@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"context"
+
+	userCtrl "github.com/koeylp/friends-management/cmd/internal/controller/user"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rpc/pb"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// UserServer serves the UserService over gRPC.
+type UserServer struct {
+	pb.UnimplementedUserServiceServer
+	userController userCtrl.UserController
+}
+
+// NewUserServer initializes a new UserServer with the provided UserController.
+func NewUserServer(userController userCtrl.UserController) *UserServer {
+	return &UserServer{userController: userController}
+}
+
+// CreateUser registers a user.
+func (s *UserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	createUserReq := &user.CreateUser{Email: req.GetEmail()}
+	if err := user.ValidateCreateUserRequest(createUserReq); err != nil {
+		return nil, err
+	}
+	if err := s.userController.CreateUser(ctx, createUserReq); err != nil {
+		return nil, err
+	}
+	return &pb.CreateUserResponse{}, nil
+}
+
+// GetUser looks a user up by email. An email that is not a valid address is simply not found.
+func (s *UserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	found, err := s.userController.GetUserByEmail(ctx, req.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetUserResponse{User: &pb.User{
+		Id:        found.ID,
+		Email:     found.Email,
+		CreatedAt: timestamppb.New(found.CreatedAt),
+		UpdatedAt: timestamppb.New(found.UpdatedAt),
+	}}, nil
+}
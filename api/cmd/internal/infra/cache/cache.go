@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+)
+
+// Cache stores values by key until they expire, are deleted or are evicted to make room.
+// Implementations must be safe for concurrent use. The in-memory LRU is only safe for a
+// single API instance with no other writers: other replicas and friendsctl change the
+// database without invalidating it, so it serves their changes only after its entries
+// expire. Instances sharing a cache, such as Redis, implement it over their client:
+// Get as GET, Set as SET with PX when ttl is positive, Delete as DEL.
+type Cache interface {
+	// Get returns the value stored under key, and whether there is one.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl, or until it is evicted when ttl is not positive.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the values stored under keys, if any.
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// New creates the cache named by the configuration, or returns nil when caching is disabled.
+func New(cfg *config.CacheConfig) (Cache, error) {
+	switch cfg.Backend {
+	case "", "none":
+		return nil, nil
+	case "memory":
+		return NewLRU(cfg.Size), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q: use memory or none", cfg.Backend)
+	}
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, c Cache, key string) (string, bool) {
+	t.Helper()
+	value, ok, err := c.Get(context.Background(), key)
+	require.NoError(t, err)
+	return string(value), ok
+}
+
+// Test that the least recently used entry is evicted when the LRU is full.
+func TestLRU_Evict(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2)
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, c.Set(ctx, "b", []byte("2"), 0))
+	get(t, c, "a")
+	require.NoError(t, c.Set(ctx, "c", []byte("3"), 0))
+
+	_, ok := get(t, c, "b")
+	assert.False(t, ok)
+	value, ok := get(t, c, "a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", value)
+	assert.Equal(t, 2, c.Len())
+}
+
+// Test that entries expire after their TTL, and that those without one do not.
+func TestLRU_Expire(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewLRU(10)
+	c.now = func() time.Time { return now }
+	require.NoError(t, c.Set(ctx, "short", []byte("1"), time.Second))
+	require.NoError(t, c.Set(ctx, "forever", []byte("2"), 0))
+
+	now = now.Add(time.Second)
+	_, ok := get(t, c, "short")
+	assert.False(t, ok)
+	_, ok = get(t, c, "forever")
+	assert.True(t, ok)
+	assert.Equal(t, 1, c.Len())
+}
+
+// Test that setting a key replaces its value and TTL.
+func TestLRU_Replace(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewLRU(10)
+	c.now = func() time.Time { return now }
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Second))
+	require.NoError(t, c.Set(ctx, "a", []byte("2"), 0))
+
+	now = now.Add(time.Hour)
+	value, ok := get(t, c, "a")
+	assert.True(t, ok)
+	assert.Equal(t, "2", value)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestLRU_Delete(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10)
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, c.Set(ctx, "b", []byte("2"), 0))
+
+	require.NoError(t, c.Delete(ctx, "a", "missing"))
+	_, ok := get(t, c, "a")
+	assert.False(t, ok)
+	_, ok = get(t, c, "b")
+	assert.True(t, ok)
+}
+
+func TestNew(t *testing.T) {
+	c, err := New(&config.CacheConfig{Backend: "none"})
+	require.NoError(t, err)
+	assert.Nil(t, c)
+
+	c, err = New(&config.CacheConfig{Backend: "memory", Size: 10})
+	require.NoError(t, err)
+	assert.IsType(t, &LRU{}, c)
+
+	_, err = New(&config.CacheConfig{Backend: "redis"})
+	assert.ErrorContains(t, err, "unknown cache backend")
+}
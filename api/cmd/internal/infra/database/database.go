@@ -0,0 +1,23 @@
+// Package database holds what the stores have in common, whichever backend serves them.
+package database
+
+import "context"
+
+// primaryReadsKey is the context key marking reads that must see the primary's latest writes.
+type primaryReadsKey struct{}
+
+// WithPrimaryReads returns a copy of ctx whose reads go to the primary, so they see every
+// write made before them. Operations that read in order to write use it, since a replica
+// may not have caught up with, say, a user created a moment ago.
+func WithPrimaryReads(ctx context.Context) context.Context {
+	if PrimaryReads(ctx) {
+		return ctx
+	}
+	return context.WithValue(ctx, primaryReadsKey{}, true)
+}
+
+// PrimaryReads reports whether the reads of ctx must go to the primary.
+func PrimaryReads(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryReadsKey{}).(bool)
+	return primary
+}
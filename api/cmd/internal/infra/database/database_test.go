@@ -0,0 +1,18 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that a context asks for primary reads only once marked, and is not wrapped twice.
+func TestWithPrimaryReads(t *testing.T) {
+	ctx := context.Background()
+	primaryCtx := WithPrimaryReads(ctx)
+
+	assert.False(t, PrimaryReads(ctx))
+	assert.True(t, PrimaryReads(primaryCtx))
+	assert.Equal(t, primaryCtx, WithPrimaryReads(primaryCtx))
+}
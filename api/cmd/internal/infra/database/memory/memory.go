@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store is an in-memory database, holding the same tables as the PostgreSQL schema in
+// cmd/data/migrations. The in-memory repositories share one, so that relationships refer to
+// stored users and relationship changes are recorded in the outbox as they are in PostgreSQL.
+// Its data lives as long as the process.
+type Store struct {
+	mu     sync.RWMutex
+	tables Tables
+}
+
+// NewStore creates an empty Store.
+func NewStore() *Store {
+	return &Store{}
+}
+
+// View calls fn with the tables for reading. Rows must not be changed or kept after fn returns.
+func (s *Store) View(ctx context.Context, fn func(*Tables) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(&s.tables)
+}
+
+// Update calls fn with the tables for changing them, so that it runs as a transaction.
+// Changes are not rolled back, so fn makes every check that may fail before changing anything.
+func (s *Store) Update(ctx context.Context, fn func(*Tables) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(&s.tables)
+}
+
+// Tables holds the rows of a Store, each table in insertion order.
+type Tables struct {
+	Users         []*User
+	Relationships []*Relationship
+	Events        []*Event
+	Subscriptions []*Subscription
+	Deliveries    []*Delivery
+
+	lastEventID int64
+}
+
+// User is a row of the users table.
+type User struct {
+	ID        string
+	Email     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Relationship is a row of the relationships table.
+type Relationship struct {
+	ID               string
+	RequestorID      string
+	TargetID         string
+	RelationshipType string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// Event is a row of the relationship_events table, the outbox.
+type Event struct {
+	ID             int64
+	EventType      string
+	Payload        []byte
+	Attempts       int
+	LastError      string
+	NextAttemptAt  time.Time
+	PublishedAt    time.Time // zero until the event is published
+	PublishedSinks []string
+	CreatedAt      time.Time
+}
+
+// Subscription is a row of the webhook_subscriptions table.
+type Subscription struct {
+	ID        string
+	URL       string
+	Secret    string
+	Events    []string
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Delivery is a row of the webhook_deliveries table, holding its rows of the
+// webhook_delivery_attempts table in the order they were recorded.
+type Delivery struct {
+	ID             string
+	SubscriptionID string
+	EventID        int64
+	Status         string
+	Attempts       int
+	LastStatusCode *int
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	History        []*Attempt
+}
+
+// Attempt is a row of the webhook_delivery_attempts table.
+type Attempt struct {
+	Attempt     int
+	StatusCode  *int
+	Error       string
+	DurationMs  int64
+	AttemptedAt time.Time
+}
+
+// UserByID returns the user with id, or nil when there is none.
+func (t *Tables) UserByID(id string) *User {
+	for _, user := range t.Users {
+		if user.ID == id {
+			return user
+		}
+	}
+	return nil
+}
+
+// UserByEmail returns the user with email, or nil when there is none.
+func (t *Tables) UserByEmail(email string) *User {
+	for _, user := range t.Users {
+		if user.Email == email {
+			return user
+		}
+	}
+	return nil
+}
+
+// EventByID returns the event with id, or nil when there is none.
+func (t *Tables) EventByID(id int64) *Event {
+	for _, event := range t.Events {
+		if event.ID == id {
+			return event
+		}
+	}
+	return nil
+}
+
+// SubscriptionByID returns the subscription with id, or nil when there is none.
+func (t *Tables) SubscriptionByID(id string) *Subscription {
+	for _, subscription := range t.Subscriptions {
+		if subscription.ID == id {
+			return subscription
+		}
+	}
+	return nil
+}
+
+// DeliveryByID returns the delivery with id, or nil when there is none.
+func (t *Tables) DeliveryByID(id string) *Delivery {
+	for _, delivery := range t.Deliveries {
+		if delivery.ID == id {
+			return delivery
+		}
+	}
+	return nil
+}
+
+// AppendEvent records an event in the outbox, due at once, and returns it. Event IDs
+// increase monotonically, as they do in PostgreSQL.
+func (t *Tables) AppendEvent(eventType string, payload []byte, at time.Time) *Event {
+	t.lastEventID++
+	event := &Event{ID: t.lastEventID, EventType: eventType, Payload: payload, NextAttemptAt: at, CreatedAt: at}
+	t.Events = append(t.Events, event)
+	return event
+}
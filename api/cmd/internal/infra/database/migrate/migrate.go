@@ -0,0 +1,220 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+const createMigrationsTable = `
+    CREATE TABLE IF NOT EXISTS schema_migrations (
+        version VARCHAR(255) PRIMARY KEY,
+        applied_at TIMESTAMP NOT NULL
+    )`
+
+// Migration is one schema change, with the script applying it and the one reverting it.
+type Migration struct {
+	Version string
+	Up      string
+	Down    string
+}
+
+// Status reports whether a migration has been applied, and when.
+type Status struct {
+	Version   string     `json:"version"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+// Migrator applies migrations to a database, recording each applied version in the
+// schema_migrations table so it is only ever applied once.
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+}
+
+// NewMigrator loads the migrations at the root of fsys. Each version needs both a
+// <version>.up.sql and a <version>.down.sql script; versions are applied in name order.
+func NewMigrator(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// load reads the migration scripts in fsys, sorted by version.
+func load(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[string]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		version, up := strings.CutSuffix(name, ".up.sql")
+		if !up {
+			var down bool
+			if version, down = strings.CutSuffix(name, ".down.sql"); !down {
+				continue
+			}
+		}
+
+		script, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version}
+			byVersion[version] = migration
+		}
+		if up {
+			migration.Up = string(script)
+		} else {
+			migration.Down = string(script)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %s needs both an up and a down script", migration.Version)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies every pending migration in order and returns the versions it applied.
+// Each migration runs in its own transaction, so a failure leaves the earlier ones applied.
+func (m *Migrator) Up(ctx context.Context) ([]string, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.run(ctx, migration.Version, migration.Up,
+			`INSERT INTO schema_migrations (version, applied_at) VALUES ($1, $2)`, migration.Version, time.Now())
+		if err != nil {
+			return versions, err
+		}
+		versions = append(versions, migration.Version)
+	}
+	return versions, nil
+}
+
+// Down reverts the given number of most recently applied migrations and returns the versions it reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]string, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for i := len(m.migrations) - 1; i >= 0 && len(versions) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err := m.run(ctx, migration.Version, migration.Down,
+			`DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+		if err != nil {
+			return versions, err
+		}
+		versions = append(versions, migration.Version)
+	}
+	return versions, nil
+}
+
+// Status reports every known migration in order, with when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = &Status{Version: migration.Version}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Pending returns the versions of the migrations that have not been applied, in order.
+// Unlike the other methods it only reads, so it fails when no migration was ever applied.
+func (m *Migrator) Pending(ctx context.Context) ([]string, error) {
+	applied, err := m.query(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			versions = append(versions, migration.Version)
+		}
+	}
+	return versions, nil
+}
+
+// applied returns when each applied version was applied, creating the schema_migrations table if needed.
+func (m *Migrator) applied(ctx context.Context) (map[string]time.Time, error) {
+	if _, err := m.db.ExecContext(ctx, createMigrationsTable); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return m.query(ctx)
+}
+
+// query reads when each applied version was applied from the schema_migrations table.
+func (m *Migrator) query(ctx context.Context) (map[string]time.Time, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]time.Time)
+	for rows.Next() {
+		var version string
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan migration: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return applied, nil
+}
+
+// run executes a migration script and the statement recording it in one transaction.
+func (m *Migrator) run(ctx context.Context, version, script, record string, args ...interface{}) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("migration %s failed: %w", version, err)
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", version, err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,208 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/koeylp/friends-management/cmd/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMigrations = fstest.MapFS{
+	"000001_users.up.sql":      {Data: []byte("CREATE TABLE users (id UUID)")},
+	"000001_users.down.sql":    {Data: []byte("DROP TABLE users")},
+	"000002_events.up.sql":     {Data: []byte("CREATE TABLE events (id BIGSERIAL)")},
+	"000002_events.down.sql":   {Data: []byte("DROP TABLE events")},
+	"README.md":                {Data: []byte("not a migration")},
+	"000003_webhooks.up.sql":   {Data: []byte("CREATE TABLE webhooks (id UUID)")},
+	"000003_webhooks.down.sql": {Data: []byte("DROP TABLE webhooks")},
+}
+
+// expectApplied expects the schema_migrations table to be read, returning the given versions as applied.
+func expectApplied(mock sqlmock.Sqlmock, appliedAt time.Time, versions ...string) {
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range versions {
+		rows.AddRow(version, appliedAt)
+	}
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).WillReturnRows(rows)
+}
+
+// TestEmbeddedMigrations tests that every embedded migration has both scripts.
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := fs.Sub(data.Migrations, "migrations")
+	require.NoError(t, err)
+
+	loaded, err := load(migrations)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+	assert.Equal(t, "000001_db_init", loaded[0].Version)
+}
+
+// TestEmbeddedMigrations_Idempotent tests that every embedded up script only creates what does not
+// exist yet, so a database created before migrations were recorded is adopted by Up.
+func TestEmbeddedMigrations_Idempotent(t *testing.T) {
+	migrations, err := fs.Sub(data.Migrations, "migrations")
+	require.NoError(t, err)
+	loaded, err := load(migrations)
+	require.NoError(t, err)
+
+	create := regexp.MustCompile(`(?i)CREATE\s+(?:TABLE|INDEX)\s+(IF\s+NOT\s+EXISTS\s+)?(\w+)`)
+	for _, migration := range loaded {
+		for _, match := range create.FindAllStringSubmatch(migration.Up, -1) {
+			assert.NotEmpty(t, match[1], "migration %s creates %s without IF NOT EXISTS", migration.Version, match[2])
+		}
+	}
+}
+
+// TestLoad_MissingDownScript tests that a migration that cannot be reverted is rejected.
+func TestLoad_MissingDownScript(t *testing.T) {
+	_, err := load(fstest.MapFS{"000001_users.up.sql": {Data: []byte("CREATE TABLE users (id UUID)")}})
+
+	assert.EqualError(t, err, "migration 000001_users needs both an up and a down script")
+}
+
+// TestUp tests that only pending migrations are applied, in order, each with its version recorded.
+func TestUp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, testMigrations)
+	require.NoError(t, err)
+
+	expectApplied(mock, time.Now(), "000001_users")
+	for _, migration := range []struct{ version, script string }{
+		{"000002_events", "CREATE TABLE events"},
+		{"000003_webhooks", "CREATE TABLE webhooks"},
+	} {
+		mock.ExpectBegin()
+		mock.ExpectExec(migration.script).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO schema_migrations`).
+			WithArgs(migration.version, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+	}
+
+	versions, err := migrator.Up(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000002_events", "000003_webhooks"}, versions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUp_Failure tests that a failing migration is rolled back and stops the ones after it.
+func TestUp_Failure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, testMigrations)
+	require.NoError(t, err)
+
+	expectApplied(mock, time.Now(), "000001_users")
+	mock.ExpectBegin()
+	mock.ExpectExec(`CREATE TABLE events`).WillReturnError(errors.New(`relation "events" already exists`))
+	mock.ExpectRollback()
+
+	versions, err := migrator.Up(context.Background())
+
+	assert.EqualError(t, err, `migration 000002_events failed: relation "events" already exists`)
+	assert.Empty(t, versions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDown tests that the most recently applied migrations are reverted first.
+func TestDown(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, testMigrations)
+	require.NoError(t, err)
+
+	expectApplied(mock, time.Now(), "000001_users", "000002_events")
+	mock.ExpectBegin()
+	mock.ExpectExec(`DROP TABLE events`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM schema_migrations`).
+		WithArgs("000002_events").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	versions, err := migrator.Down(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000002_events"}, versions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestStatus tests that every known migration is reported, with when it was applied.
+func TestStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, testMigrations)
+	require.NoError(t, err)
+
+	appliedAt := time.Date(2024, 10, 28, 2, 12, 0, 0, time.UTC)
+	expectApplied(mock, appliedAt, "000001_users")
+
+	statuses, err := migrator.Status(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []*Status{
+		{Version: "000001_users", AppliedAt: &appliedAt},
+		{Version: "000002_events"},
+		{Version: "000003_webhooks"},
+	}, statuses)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPending tests that the versions not yet applied are reported without creating schema_migrations.
+func TestPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, testMigrations)
+	require.NoError(t, err)
+
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow("000001_users", time.Now()))
+
+	versions, err := migrator.Pending(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000002_events", "000003_webhooks"}, versions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSeed tests that every seed script runs in one transaction.
+func TestSeed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO users`).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO relationships`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	names, err := Seed(context.Background(), db, fstest.MapFS{
+		"1_users.sql":         {Data: []byte("INSERT INTO users VALUES (1), (2)")},
+		"2_relationships.sql": {Data: []byte("INSERT INTO relationships VALUES (1)")},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1_users.sql", "2_relationships.sql"}, names)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
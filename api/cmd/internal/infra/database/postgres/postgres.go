@@ -3,36 +3,63 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"log"
-	"os"
+	"fmt"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/koeylp/friends-management/cmd/internal/infra/config"
-	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"github.com/koeylp/friends-management/cmd/internal/infra/tracing"
+	"go.uber.org/fx"
 )
 
-var DB *sql.DB
-
-func InitDB() (*sql.DB, error) {
-	boil.DebugMode = true
-
-	boil.DebugWriter = os.Stdout
-	dbConfig := config.GetDBConfig()
-	connStr := dbConfig.GetConnectionString()
+// Open opens the database described by cfg with its pool limits and waits for it to answer,
+// pinging up to DB_CONNECT_ATTEMPTS times with a doubling backoff, since it may still be
+// starting. Statements are logged at debug level through logging.QueryTracer and recorded
+// as spans through tracing.QueryTracer. The caller closes the database.
+func Open(ctx context.Context, cfg *config.DBConfig) (*sql.DB, error) {
+	return open(ctx, cfg.GetConnectionString(), cfg)
+}
 
-	db, err := sql.Open("pgx", connStr)
+// open opens the database at dsn with the pool limits and connect attempts of cfg.
+func open(ctx context.Context, dsn string, cfg *config.DBConfig) (*sql.DB, error) {
+	connConfig, err := pgx.ParseConfig(dsn)
 	if err != nil {
-		log.Fatalf("Failed to connect to the database: %v", err)
 		return nil, err
 	}
-	DB = db
-	boil.SetDB(DB)
+	connConfig.Tracer = multitracer.New(logging.QueryTracer{}, tracing.QueryTracer{})
 
-	return DB, nil
+	db := stdlib.OpenDB(*connConfig)
+	configurePool(db, cfg)
+
+	connect := RetryPolicy{Attempts: cfg.ConnectAttempts, Backoff: cfg.ConnectBackoff}
+	if err := connect.Do(ctx, func() error { return db.PingContext(ctx) }); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("database is unreachable after %d attempt(s): %w", max(cfg.ConnectAttempts, 1), err)
+	}
+	return db, nil
 }
 
-func CloseDB(ctx context.Context) {
-	if err := DB.Close(); err != nil {
-		log.Fatalf("Failed to close the database: %v", err)
+// NewDB opens the database for the lifetime of the application and closes it on stop,
+// once the servers and background workers using it have stopped.
+func NewDB(lc fx.Lifecycle, cfg *config.DBConfig) (*sql.DB, error) {
+	db, err := Open(context.Background(), cfg)
+	if err != nil {
+		return nil, err
 	}
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return db.Close()
+		},
+	})
+	return db, nil
+}
+
+// configurePool applies the configured connection pool limits to db.
+func configurePool(db *sql.DB, cfg *config.DBConfig) {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 }
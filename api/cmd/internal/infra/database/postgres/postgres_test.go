@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that opening an unreachable database fails once the connect attempts run out.
+func TestOpen_Unreachable(t *testing.T) {
+	cfg := &config.DBConfig{
+		User: "admin", Password: "secret", Host: "127.0.0.1", Port: "1", DBName: "friends_db", SSLMode: "disable",
+		ConnectAttempts: 2, ConnectBackoff: time.Millisecond,
+	}
+
+	db, err := Open(context.Background(), cfg)
+
+	assert.Nil(t, db)
+	assert.ErrorContains(t, err, "database is unreachable after 2 attempt(s)")
+}
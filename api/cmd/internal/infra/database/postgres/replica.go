@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database"
+	"go.uber.org/fx"
+)
+
+// ReadRouter sends the reads that tolerate replication lag to a read replica and
+// everything else to the primary.
+type ReadRouter struct {
+	primary *sql.DB
+	replica *sql.DB
+}
+
+// NewReadRouter creates a ReadRouter over primary and replica; without a replica every
+// read goes to the primary.
+func NewReadRouter(primary, replica *sql.DB) *ReadRouter {
+	return &ReadRouter{primary: primary, replica: replica}
+}
+
+// OpenReadRouter creates the ReadRouter of the application. When DB_REPLICA_DSN is set the
+// replica is opened like the primary, with the same pool limits, and closed on stop.
+func OpenReadRouter(lc fx.Lifecycle, primary *sql.DB, cfg *config.DBConfig) (*ReadRouter, error) {
+	if cfg.ReplicaDSN == "" {
+		return NewReadRouter(primary, nil), nil
+	}
+	replica, err := open(context.Background(), cfg.ReplicaDSN, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("read replica: %w", err)
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return replica.Close()
+		},
+	})
+	return NewReadRouter(primary, replica), nil
+}
+
+// Primary returns the primary, which every write goes to.
+func (r *ReadRouter) Primary() *sql.DB {
+	return r.primary
+}
+
+// Reader returns the database a read made with ctx goes to: the replica, unless there is
+// none or ctx was made with database.WithPrimaryReads.
+func (r *ReadRouter) Reader(ctx context.Context) *sql.DB {
+	if r.replica == nil || database.PrimaryReads(ctx) {
+		return r.primary
+	}
+	return r.replica
+}
@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/database"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that reads go to the replica unless there is none or the context asks for the primary.
+func TestReadRouter_Reader(t *testing.T) {
+	primary, replica := &sql.DB{}, &sql.DB{}
+	ctx := context.Background()
+	primaryCtx := database.WithPrimaryReads(ctx)
+
+	router := NewReadRouter(primary, replica)
+	assert.Same(t, primary, router.Primary())
+	assert.Same(t, replica, router.Reader(ctx))
+	assert.Same(t, primary, router.Reader(primaryCtx))
+
+	router = NewReadRouter(primary, nil)
+	assert.Same(t, primary, router.Reader(ctx))
+}
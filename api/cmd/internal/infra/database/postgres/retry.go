@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+)
+
+// transientCodes are the PostgreSQL error codes of failures that may not happen again:
+// concurrent transactions getting in each other's way, or the server going away.
+var transientCodes = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+}
+
+// IsTransient reports whether err is a failure that retrying the same statement may not hit,
+// such as a serialization failure or a connection reset. Domain errors and timeouts are not.
+func IsTransient(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Class 08 is connection exceptions.
+		return transientCodes[pgErr.Code] || strings.HasPrefix(pgErr.Code, "08")
+	}
+	return pgconn.SafeToRetry(err) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// RetryPolicy bounds how an idempotent operation is retried.
+type RetryPolicy struct {
+	// Attempts is the most times the operation runs; below 1 it runs once.
+	Attempts int
+	// Backoff is the wait after the first failed attempt, doubled after each.
+	Backoff time.Duration
+	// Retryable tells which errors are retried; nil retries every error.
+	Retryable func(error) bool
+}
+
+// Do runs fn until it succeeds, fails with an error that is not retryable, or the attempts
+// run out, and returns its last error. It stops waiting when ctx is done.
+func (p RetryPolicy) Do(ctx context.Context, fn func() error) error {
+	backoff := p.Backoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.Attempts || (p.Retryable != nil && !p.Retryable(err)) {
+			return err
+		}
+
+		logging.FromContext(ctx).WarnContext(ctx, "retrying database operation",
+			"attempt", attempt, "backoff", backoff, "error", err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// NewReadRetryPolicy returns the policy idempotent repository reads are retried with:
+// only on transient errors, as configured by DB_READ_ATTEMPTS and DB_READ_BACKOFF.
+func NewReadRetryPolicy(cfg *config.DBConfig) RetryPolicy {
+	return RetryPolicy{Attempts: cfg.ReadAttempts, Backoff: cfg.ReadBackoff, Retryable: IsTransient}
+}
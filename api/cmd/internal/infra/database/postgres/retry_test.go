@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that only failures that may not happen again are transient.
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{"Serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"Deadlock", fmt.Errorf("query failed: %w", &pgconn.PgError{Code: "40P01"}), true},
+		{"Connection failure", &pgconn.PgError{Code: "08006"}, true},
+		{"Server shutting down", &pgconn.PgError{Code: "57P01"}, true},
+		{"Bad connection", driver.ErrBadConn, true},
+		{"Connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"Unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"No rows", sql.ErrNoRows, false},
+		{"Domain error", domain.Errorf(domain.ErrUserNotFound, "user not found"), false},
+		{"Deadline", context.DeadlineExceeded, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.transient, IsTransient(tt.err))
+		})
+	}
+}
+
+// Test that retryable failures are retried until the attempts run out, and others are not.
+func TestRetryPolicy_Do(t *testing.T) {
+	policy := RetryPolicy{Attempts: 3, Backoff: time.Millisecond, Retryable: IsTransient}
+
+	calls := 0
+	err := policy.Do(context.Background(), func() error {
+		calls++
+		if calls < 2 {
+			return driver.ErrBadConn
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	calls = 0
+	err = policy.Do(context.Background(), func() error {
+		calls++
+		return driver.ErrBadConn
+	})
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = policy.Do(context.Background(), func() error {
+		calls++
+		return sql.ErrNoRows
+	})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Equal(t, 1, calls)
+}
+
+// Test that a policy without Retryable retries every error, and that waiting stops with the context.
+func TestRetryPolicy_Do_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := RetryPolicy{Attempts: 5, Backoff: time.Hour}
+
+	calls := 0
+	err := policy.Do(ctx, func() error {
+		calls++
+		cancel()
+		return errors.New("connection refused")
+	})
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, 1, calls)
+}
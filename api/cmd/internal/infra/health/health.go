@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/data"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/migrate"
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"go.uber.org/fx"
+)
+
+// Check results reported by Checker.
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// checkTimeout bounds each readiness check, so a probe gets an answer while the database hangs.
+const checkTimeout = 2 * time.Second
+
+// Report is the outcome of a readiness check: the overall status and the result of each check.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Ready reports whether every check passed.
+func (r *Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+// Checker tells whether the application can serve requests: it is serving, the database
+// answers and its schema is at the version the application was built with.
+type Checker struct {
+	db       *sql.DB
+	migrator *migrate.Migrator
+	serving  atomic.Bool
+}
+
+// NewChecker creates a Checker for db, expecting every embedded migration to be applied.
+// It does not report ready until Register's start hook has run.
+func NewChecker(db *sql.DB) (*Checker, error) {
+	migrations, err := fs.Sub(data.Migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	migrator, err := migrate.NewMigrator(db, migrations)
+	if err != nil {
+		return nil, err
+	}
+	return &Checker{db: db, migrator: migrator}, nil
+}
+
+// NewServingChecker creates a Checker for an application without a database, such as one
+// keeping its data in memory, which is ready as long as it is serving.
+func NewServingChecker() *Checker {
+	return &Checker{}
+}
+
+// Register marks the application as serving once it has started, and as no longer serving
+// as soon as it starts stopping. It must be invoked after the servers are registered, so that
+// readiness flips before they stop accepting requests; they then stop after cfg.ShutdownDelay,
+// giving load balancers time to notice.
+func (c *Checker) Register(lc fx.Lifecycle, cfg *config.ServerConfig) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			c.serving.Store(true)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			c.serving.Store(false)
+			select {
+			case <-time.After(cfg.ShutdownDelay):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+}
+
+// Ready runs the readiness checks. Failures are logged with their cause, while the report
+// only names them, since it is served to anyone who asks.
+func (c *Checker) Ready(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	report := &Report{Status: StatusOK, Checks: make(map[string]string)}
+	fail := func(check, result string) {
+		report.Status = StatusUnavailable
+		report.Checks[check] = result
+	}
+
+	report.Checks["serving"] = StatusOK
+	if !c.serving.Load() {
+		fail("serving", "not serving")
+	}
+
+	if c.db == nil {
+		return report
+	}
+
+	report.Checks["database"] = StatusOK
+	if err := c.db.PingContext(ctx); err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "database is unreachable", "error", err)
+		fail("database", "unreachable")
+	}
+
+	report.Checks["migrations"] = StatusOK
+	pending, err := c.migrator.Pending(ctx)
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "failed to check migrations", "error", err)
+		fail("migrations", "unknown")
+	} else if len(pending) > 0 {
+		fail("migrations", fmt.Sprintf("pending: %s", strings.Join(pending, ", ")))
+	}
+	return report
+}
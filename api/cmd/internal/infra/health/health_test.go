@@ -0,0 +1,107 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/koeylp/friends-management/cmd/data"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+)
+
+// newTestChecker creates a serving Checker on a mock database that answers pings.
+func newTestChecker(t *testing.T) (*Checker, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	checker, err := NewChecker(db)
+	require.NoError(t, err)
+	checker.serving.Store(true)
+	return checker, mock
+}
+
+// expectApplied expects the applied migrations to be read, returning every embedded one but the last skipped.
+func expectApplied(t *testing.T, mock sqlmock.Sqlmock, skipped int) []string {
+	scripts, err := fs.Glob(data.Migrations, "migrations/*.up.sql")
+	require.NoError(t, err)
+
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	var versions []string
+	for _, script := range scripts {
+		versions = append(versions, strings.TrimSuffix(strings.TrimPrefix(script, "migrations/"), ".up.sql"))
+	}
+	for _, version := range versions[:len(versions)-skipped] {
+		rows.AddRow(version, time.Now())
+	}
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).WillReturnRows(rows)
+	return versions[len(versions)-skipped:]
+}
+
+// Test that the application is ready when it serves, the database answers and every migration is applied.
+func TestReady(t *testing.T) {
+	checker, mock := newTestChecker(t)
+	mock.ExpectPing()
+	expectApplied(t, mock, 0)
+
+	report := checker.Ready(context.Background())
+
+	assert.True(t, report.Ready())
+	assert.Equal(t, &Report{Status: StatusOK, Checks: map[string]string{"serving": "ok", "database": "ok", "migrations": "ok"}}, report)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// Test that each failed check makes the application unavailable and is named in the report.
+func TestReady_Failures(t *testing.T) {
+	checker, mock := newTestChecker(t)
+	mock.ExpectPing()
+	pending := expectApplied(t, mock, 1)
+
+	report := checker.Ready(context.Background())
+
+	assert.False(t, report.Ready())
+	assert.Equal(t, "pending: "+pending[0], report.Checks["migrations"])
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).WillReturnError(errors.New("connection refused"))
+	checker.serving.Store(false)
+
+	report = checker.Ready(context.Background())
+
+	assert.Equal(t, &Report{
+		Status: StatusUnavailable,
+		Checks: map[string]string{"serving": "not serving", "database": "unreachable", "migrations": "unknown"},
+	}, report)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// Test that an application without a database is ready as long as it serves.
+func TestReady_WithoutDatabase(t *testing.T) {
+	checker := NewServingChecker()
+	assert.False(t, checker.Ready(context.Background()).Ready())
+
+	checker.serving.Store(true)
+
+	assert.Equal(t, &Report{Status: StatusOK, Checks: map[string]string{"serving": "ok"}}, checker.Ready(context.Background()))
+}
+
+// Test that the application only reports serving between its start and stop.
+func TestRegister(t *testing.T) {
+	checker, _ := newTestChecker(t)
+	checker.serving.Store(false)
+	lc := fxtest.NewLifecycle(t)
+	checker.Register(lc, &config.ServerConfig{})
+
+	assert.False(t, checker.serving.Load())
+	lc.RequireStart()
+	assert.True(t, checker.serving.Load())
+	lc.RequireStop()
+	assert.False(t, checker.serving.Load())
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that the logger honours the configured format and level.
+func TestNew(t *testing.T) {
+	var out bytes.Buffer
+	logger := New(&config.LogConfig{Level: slog.LevelWarn, Format: "text"}, &out)
+	logger.Info("skipped")
+	logger.Warn("kept", "key", "value")
+	assert.Equal(t, 1, strings.Count(out.String(), "\n"))
+	assert.Contains(t, out.String(), "level=WARN msg=kept key=value")
+
+	out.Reset()
+	logger = New(&config.LogConfig{Level: slog.LevelInfo, Format: "json"}, &out)
+	logger.Info("kept")
+	assert.Contains(t, out.String(), `"msg":"kept"`)
+}
+
+// Test that the request ID is carried by the context and added to the context's logger.
+func TestWithRequestID(t *testing.T) {
+	var out bytes.Buffer
+	ctx := NewContext(context.Background(), New(&config.LogConfig{Format: "text"}, &out))
+
+	assert.Equal(t, "", RequestID(ctx))
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+
+	ctx = WithRequestID(ctx, "req-42")
+	FromContext(ctx).InfoContext(ctx, "handled")
+	assert.Equal(t, "req-42", RequestID(ctx))
+	assert.Contains(t, out.String(), "msg=handled request_id=req-42")
+}
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, ValidRequestID("req-42"))
+	assert.True(t, ValidRequestID(NewRequestID()))
+	assert.False(t, ValidRequestID(""))
+	assert.False(t, ValidRequestID("has spaces"))
+	assert.False(t, ValidRequestID("line\nbreak"))
+	assert.False(t, ValidRequestID(strings.Repeat("a", 129)))
+}
+
+// Test that statements are logged with the request ID at debug level only.
+func TestQueryTracer(t *testing.T) {
+	var out bytes.Buffer
+	tracer := QueryTracer{}
+	query := pgx.TraceQueryStartData{SQL: "SELECT * FROM users WHERE email = $1", Args: []any{"andy@example.com"}}
+
+	ctx := WithRequestID(NewContext(context.Background(), New(&config.LogConfig{Level: slog.LevelDebug, Format: "text"}, &out)), "req-42")
+	tracer.TraceQueryEnd(tracer.TraceQueryStart(ctx, nil, query), nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+	tracer.TraceQueryEnd(tracer.TraceQueryStart(ctx, nil, query), nil, pgx.TraceQueryEndData{Err: errors.New("connection reset")})
+	logs := out.String()
+	assert.Contains(t, logs, `level=DEBUG msg="sql query" request_id=req-42 sql="SELECT * FROM users WHERE email = $1" args=[andy@example.com]`)
+	assert.Contains(t, logs, "rows=1")
+	assert.Contains(t, logs, `error="connection reset"`)
+
+	out.Reset()
+	ctx = NewContext(context.Background(), New(&config.LogConfig{Level: slog.LevelInfo, Format: "text"}, &out))
+	tracer.TraceQueryEnd(tracer.TraceQueryStart(ctx, nil, query), nil, pgx.TraceQueryEndData{})
+	assert.Empty(t, out.String())
+}
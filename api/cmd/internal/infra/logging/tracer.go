@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// QueryTracer logs every SQL statement at debug level through the logger carried by the
+// query's context, so statements made for a request are logged with its request ID.
+type QueryTracer struct{}
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	sql   string
+	args  []any
+	start time.Time
+}
+
+// TraceQueryStart records the statement for TraceQueryEnd, unless debug logging is disabled.
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !FromContext(ctx).Enabled(ctx, slog.LevelDebug) {
+		return ctx
+	}
+	return context.WithValue(ctx, queryStartKey{}, &queryStart{sql: data.SQL, args: data.Args, start: time.Now()})
+}
+
+// TraceQueryEnd logs the statement with its arguments, duration and outcome.
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	query, ok := ctx.Value(queryStartKey{}).(*queryStart)
+	if !ok {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("sql", query.sql),
+		slog.Any("args", query.args),
+		slog.Duration("duration", time.Since(query.start)),
+	}
+	if data.Err != nil {
+		attrs = append(attrs, slog.String("error", data.Err.Error()))
+	} else {
+		attrs = append(attrs, slog.Int64("rows", data.CommandTag.RowsAffected()))
+	}
+	FromContext(ctx).LogAttrs(ctx, slog.LevelDebug, "sql query", attrs...)
+}
@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the name of every application metric.
+const namespace = "friends"
+
+// Metrics holds the application's Prometheus collectors in a registry of its own,
+// so every instance, including the ones built in tests, starts from zero.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requestDuration *prometheus.HistogramVec
+	queryDuration   *prometheus.HistogramVec
+	queryErrors     *prometheus.CounterVec
+	cacheLookups    *prometheus.CounterVec
+
+	friendshipsCreated prometheus.Counter
+	blocksCreated      prometheus.Counter
+	updatesFannedOut   prometheus.Counter
+}
+
+// New creates the application metrics, along with the Go runtime and process metrics.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests by method, route pattern and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_query_duration_seconds",
+			Help:      "Duration of repository calls by repository and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"repository", "method"}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "repository_query_errors_total",
+			Help:      "Repository calls that failed, by repository and method.",
+		}, []string{"repository", "method"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Cached repository reads by method and result, hit or miss.",
+		}, []string{"method", "result"}),
+		friendshipsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "friendships_created_total",
+			Help:      "Friendships created.",
+		}),
+		blocksCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "blocks_created_total",
+			Help:      "Blocks on updates created.",
+		}),
+		updatesFannedOut: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "updates_fanned_out_total",
+			Help:      "Recipients updates were fanned out to.",
+		}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requestDuration,
+		m.queryDuration,
+		m.queryErrors,
+		m.cacheLookups,
+		m.friendshipsCreated,
+		m.blocksCreated,
+		m.updatesFannedOut,
+	)
+	return m
+}
+
+// RegisterDB exposes the connection pool statistics of db as go_sql_* gauges.
+func (m *Metrics) RegisterDB(db *sql.DB) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, "friends"))
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveRequest records a served HTTP request. route is the matched route pattern,
+// so requests for different users are counted together.
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	m.requestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// CacheLookup counts a read of method looked up in the cache, by whether it was found.
+func (m *Metrics) CacheLookup(method string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.WithLabelValues(method, result).Inc()
+}
+
+// FriendshipCreated counts a new friendship.
+func (m *Metrics) FriendshipCreated() {
+	m.friendshipsCreated.Inc()
+}
+
+// BlockCreated counts a new block on updates.
+func (m *Metrics) BlockCreated() {
+	m.blocksCreated.Inc()
+}
+
+// UpdateFannedOut counts the recipients of a posted update.
+func (m *Metrics) UpdateFannedOut(recipients int) {
+	m.updatesFannedOut.Add(float64(recipients))
+}
+
+// Repository returns an observer for the calls of the named repository.
+func (m *Metrics) Repository(name string) *RepositoryObserver {
+	return &RepositoryObserver{metrics: m, repository: name}
+}
+
+// RepositoryObserver records the duration and failures of the calls of one repository.
+type RepositoryObserver struct {
+	metrics    *Metrics
+	repository string
+}
+
+// Observe records a call to method that started at started and returned *err. It is meant
+// to be deferred with a named error result:
+//
+//	defer r.observer.Observe("GetUserByEmail", time.Now(), &err)
+//
+// Domain errors, such as a user that does not exist, are answers rather than failures
+// and are not counted as errors.
+func (o *RepositoryObserver) Observe(method string, started time.Time, err *error) {
+	o.metrics.queryDuration.WithLabelValues(o.repository, method).Observe(time.Since(started).Seconds())
+
+	var domainErr *domain.Error
+	if *err != nil && !errors.As(*err, &domainErr) {
+		o.metrics.queryErrors.WithLabelValues(o.repository, method).Inc()
+	}
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that repository calls are timed and only failures other than domain errors are counted as errors.
+func TestRepositoryObserver(t *testing.T) {
+	m := New()
+	observer := m.Repository("user")
+
+	call := func(err error) {
+		defer observer.Observe("GetUserByEmail", time.Now(), &err)
+	}
+	call(nil)
+	call(domain.Errorf(domain.ErrUserNotFound, "user not found"))
+	call(sql.ErrConnDone)
+
+	assert.Equal(t, 1, testutil.CollectAndCount(m.queryDuration))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.queryErrors.WithLabelValues("user", "GetUserByEmail")))
+}
+
+func TestBusinessCounters(t *testing.T) {
+	m := New()
+
+	m.FriendshipCreated()
+	m.BlockCreated()
+	m.BlockCreated()
+	m.UpdateFannedOut(3)
+	m.UpdateFannedOut(0)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.friendshipsCreated))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.blocksCreated))
+	assert.Equal(t, 3.0, testutil.ToFloat64(m.updatesFannedOut))
+}
+
+func TestCacheLookup(t *testing.T) {
+	m := New()
+
+	m.CacheLookup("GetFriends", false)
+	m.CacheLookup("GetFriends", true)
+	m.CacheLookup("GetFriends", true)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.cacheLookups.WithLabelValues("GetFriends", "miss")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.cacheLookups.WithLabelValues("GetFriends", "hit")))
+}
+
+// Test that the handler exposes the application, pool and runtime metrics.
+func TestHandler(t *testing.T) {
+	m := New()
+	require.NoError(t, m.RegisterDB(&sql.DB{}))
+	m.ObserveRequest(http.MethodPost, "/api/v1/friends/", http.StatusOK, 20*time.Millisecond)
+	m.FriendshipCreated()
+
+	rr := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	body := rr.Body.String()
+	assert.Contains(t, body, `friends_http_request_duration_seconds_count{method="POST",route="/api/v1/friends/",status="200"} 1`)
+	assert.Contains(t, body, "friends_friendships_created_total 1")
+	assert.Contains(t, body, `go_sql_open_connections{db_name="friends"} 0`)
+	assert.Contains(t, body, "go_goroutines")
+}
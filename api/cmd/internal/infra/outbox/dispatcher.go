@@ -0,0 +1,168 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+	outboxRepo "github.com/koeylp/friends-management/cmd/internal/repository/outbox"
+	"go.uber.org/fx"
+)
+
+const (
+	// claimLease is how long a claimed event stays hidden from other dispatchers.
+	claimLease = time.Minute
+
+	baseBackoff = time.Second
+	maxBackoff  = 5 * time.Minute
+)
+
+// Sink is a destination relationship events are published to. Sinks are told apart by name.
+// Publish must be safe to call more than once for the same event.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, e *event.Event) error
+}
+
+// Dispatcher drains the relationship event outbox and publishes each event to every sink.
+// An event is marked published only after all sinks accept it; otherwise it is retried with
+// exponential backoff. The sinks that accepted it are recorded with the failure and skipped
+// by the retry, so a failing sink does not make the others see the event again. A sink may
+// still see an event twice when its acceptance could not be recorded.
+type Dispatcher struct {
+	repo   outboxRepo.OutboxRepository
+	sinks  []Sink
+	config *config.OutboxConfig
+	logger *slog.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewDispatcher creates a new Dispatcher publishing to the provided sinks.
+func NewDispatcher(repo outboxRepo.OutboxRepository, sinks []Sink, config *config.OutboxConfig, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{repo: repo, sinks: sinks, config: config, logger: logger}
+}
+
+// DispatchOnce claims one batch of due events and publishes them.
+// It returns the number of events that were published to every sink.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	events, err := d.repo.ClaimPending(ctx, d.config.BatchSize, d.config.MaxAttempts, claimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, e := range events {
+		if publishedSinks, err := d.publish(ctx, e); err != nil {
+			retryAt := time.Now().Add(Backoff(e.Attempts))
+			d.logger.ErrorContext(ctx, "outbox event publish failed",
+				"event_id", e.ID, "event_type", e.Type, "attempt", e.Attempts, "retry_at", retryAt, "error", err)
+			if err := d.repo.MarkFailed(ctx, e.ID, err, retryAt, publishedSinks); err != nil {
+				return published, err
+			}
+			continue
+		}
+		if err := d.repo.MarkPublished(ctx, e.ID); err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+// publish hands the event to every sink that has not accepted it yet and joins their failures.
+// It returns the names of the sinks that have accepted the event, on this or earlier attempts.
+func (d *Dispatcher) publish(ctx context.Context, e *event.Event) ([]string, error) {
+	publishedSinks := slices.Clone(e.PublishedSinks)
+	var errs []error
+	for _, sink := range d.sinks {
+		if slices.Contains(e.PublishedSinks, sink.Name()) {
+			continue
+		}
+		if err := sink.Publish(ctx, e); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+			continue
+		}
+		publishedSinks = append(publishedSinks, sink.Name())
+	}
+	return publishedSinks, errors.Join(errs...)
+}
+
+// Run dispatches events every poll interval until ctx is cancelled.
+// A full batch is followed immediately by another one so a backlog drains without waiting.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := d.DispatchOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			d.logger.ErrorContext(ctx, "outbox dispatch failed", "error", err)
+		}
+		if err == nil && n == d.config.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Start runs the dispatcher in the background. Its queries are logged through the dispatcher's logger.
+func (d *Dispatcher) Start() {
+	ctx, cancel := context.WithCancel(logging.NewContext(context.Background(), d.logger))
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	go func() {
+		defer close(d.done)
+		d.Run(ctx)
+	}()
+}
+
+// Stop cancels the background dispatcher and waits for the current batch to finish.
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	if d.cancel == nil {
+		return nil
+	}
+	d.cancel()
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Backoff returns the delay before retrying an event that has been attempted the given number of times.
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := float64(baseBackoff) * math.Pow(2, float64(attempts-1))
+	if delay > float64(maxBackoff) {
+		return maxBackoff
+	}
+	return time.Duration(delay)
+}
+
+// RegisterDispatcher ties the dispatcher to the application lifecycle.
+func RegisterDispatcher(lc fx.Lifecycle, d *Dispatcher) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			d.Start()
+			return nil
+		},
+		OnStop: d.Stop,
+	})
+}
@@ -0,0 +1,168 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = &config.OutboxConfig{PollInterval: 10 * time.Millisecond, BatchSize: 10, MaxAttempts: 5}
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func newTestEvent(id int64, attempts int) *event.Event {
+	return &event.Event{
+		ID:       id,
+		Type:     event.FriendCreated,
+		Data:     json.RawMessage(`{"relationship_id":"r1","requestor":"a@example.com","target":"b@example.com"}`),
+		Attempts: attempts,
+	}
+}
+
+// Tests that delivered events are published to every sink and marked published.
+func TestDispatchOnce_Success(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockOutboxRepository)
+	first, second := NewMemorySink("first"), NewMemorySink("second")
+	d := NewDispatcher(repo, []Sink{first, second}, testConfig, testLogger)
+
+	events := []*event.Event{newTestEvent(1, 1), newTestEvent(2, 1)}
+	repo.On("ClaimPending", ctx, 10, 5, claimLease).Return(events, nil)
+	repo.On("MarkPublished", ctx, int64(1)).Return(nil)
+	repo.On("MarkPublished", ctx, int64(2)).Return(nil)
+
+	n, err := d.DispatchOnce(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, events, first.Events())
+	assert.Equal(t, events, second.Events())
+	repo.AssertExpectations(t)
+}
+
+// Tests that a failing sink schedules a retry with backoff instead of marking the event published,
+// recording the sinks that accepted the event.
+func TestDispatchOnce_SinkFailure(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockOutboxRepository)
+	healthy, broken := NewMemorySink("healthy"), NewMemorySink("broken")
+	broken.Err = errors.New("connection refused")
+	d := NewDispatcher(repo, []Sink{healthy, broken}, testConfig, testLogger)
+
+	repo.On("ClaimPending", ctx, 10, 5, claimLease).Return([]*event.Event{newTestEvent(1, 3)}, nil)
+	repo.On("MarkFailed", ctx, int64(1), mock.MatchedBy(func(err error) bool {
+		return err.Error() == "broken: connection refused"
+	}), mock.MatchedBy(func(retryAt time.Time) bool {
+		delay := time.Until(retryAt)
+		return delay > 3*time.Second && delay <= 4*time.Second
+	}), []string{"healthy"}).Return(nil)
+
+	n, err := d.DispatchOnce(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Len(t, healthy.Events(), 1)
+	repo.AssertNotCalled(t, "MarkPublished", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
+
+// Tests that a retry only publishes to the sinks that did not accept the event before,
+// so a sink that fails once does not make the others see the event twice.
+func TestDispatchOnce_RetrySkipsPublishedSinks(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockOutboxRepository)
+	healthy, flaky := NewMemorySink("healthy"), NewMemorySink("flaky")
+	flaky.Err = errors.New("connection refused")
+	d := NewDispatcher(repo, []Sink{healthy, flaky}, testConfig, testLogger)
+
+	repo.On("ClaimPending", ctx, 10, 5, claimLease).Return([]*event.Event{newTestEvent(1, 1)}, nil).Once()
+	repo.On("MarkFailed", ctx, int64(1), mock.Anything, mock.Anything, []string{"healthy"}).Return(nil).Once()
+
+	n, err := d.DispatchOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	flaky.Err = nil
+	retried := newTestEvent(1, 2)
+	retried.PublishedSinks = []string{"healthy"}
+	repo.On("ClaimPending", ctx, 10, 5, claimLease).Return([]*event.Event{retried}, nil).Once()
+	repo.On("MarkPublished", ctx, int64(1)).Return(nil).Once()
+
+	n, err = d.DispatchOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Len(t, healthy.Events(), 1)
+	assert.Len(t, flaky.Events(), 1)
+	repo.AssertExpectations(t)
+}
+
+// Tests that the dispatcher keeps running until stopped and drains events on the way.
+func TestDispatcher_StartStop(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	sink := NewMemorySink("memory")
+	d := NewDispatcher(repo, []Sink{sink}, testConfig, testLogger)
+
+	repo.On("ClaimPending", mock.Anything, 10, 5, claimLease).Return([]*event.Event{newTestEvent(1, 1)}, nil).Once()
+	repo.On("ClaimPending", mock.Anything, 10, 5, claimLease).Return([]*event.Event{}, nil)
+	repo.On("MarkPublished", mock.Anything, int64(1)).Return(nil)
+
+	d.Start()
+	assert.Eventually(t, func() bool { return len(sink.Events()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.NoError(t, d.Stop(context.Background()))
+}
+
+// Tests the exponential backoff schedule and its cap.
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, Backoff(0))
+	assert.Equal(t, time.Second, Backoff(1))
+	assert.Equal(t, 4*time.Second, Backoff(3))
+	assert.Equal(t, maxBackoff, Backoff(20))
+}
+
+// Tests that the webhook sink posts the event and treats non-2xx responses as failures.
+func TestWebhookSink(t *testing.T) {
+	var received event.Event
+	var eventType string
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		eventType = r.Header.Get("X-Event-Type")
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, nil)
+
+	require.NoError(t, sink.Publish(context.Background(), newTestEvent(42, 1)))
+	assert.Equal(t, int64(42), received.ID)
+	assert.Equal(t, event.FriendCreated, eventType)
+
+	status = http.StatusInternalServerError
+	assert.EqualError(t, sink.Publish(context.Background(), newTestEvent(43, 1)), "unexpected status 500")
+}
+
+// Tests that only known sinks can be configured.
+func TestNewConfiguredSinks(t *testing.T) {
+	sinks, err := NewConfiguredSinks(&config.OutboxConfig{Sinks: []string{"stdout", "webhook"}, WebhookURL: "http://localhost:9000"})
+	require.NoError(t, err)
+	assert.Len(t, sinks, 2)
+
+	_, err = NewConfiguredSinks(&config.OutboxConfig{Sinks: []string{"webhook"}})
+	assert.Error(t, err)
+
+	_, err = NewConfiguredSinks(&config.OutboxConfig{Sinks: []string{"kafka"}})
+	assert.EqualError(t, err, `unknown outbox sink "kafka"`)
+}
@@ -0,0 +1,38 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOutboxRepository is a mock implementation of an outbox repository for testing purposes.
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+// Append mocks recording a standalone event.
+func (m *MockOutboxRepository) Append(ctx context.Context, eventType string, data interface{}) error {
+	args := m.Called(ctx, eventType, data)
+	return args.Error(0)
+}
+
+// ClaimPending mocks leasing a batch of due events.
+func (m *MockOutboxRepository) ClaimPending(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]*event.Event, error) {
+	args := m.Called(ctx, limit, maxAttempts, lease)
+	return args.Get(0).([]*event.Event), args.Error(1)
+}
+
+// MarkPublished mocks stamping an event as published.
+func (m *MockOutboxRepository) MarkPublished(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MarkFailed mocks recording a failed delivery attempt.
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, id int64, cause error, retryAt time.Time, publishedSinks []string) error {
+	args := m.Called(ctx, id, cause, retryAt, publishedSinks)
+	return args.Error(0)
+}
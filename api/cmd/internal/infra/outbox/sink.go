@@ -0,0 +1,132 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+)
+
+// NewConfiguredSinks builds the sinks named in the outbox configuration.
+func NewConfiguredSinks(config *config.OutboxConfig) ([]Sink, error) {
+	sinks := make([]Sink, 0, len(config.Sinks))
+	for _, name := range config.Sinks {
+		switch name {
+		case "stdout":
+			sinks = append(sinks, NewWriterSink(os.Stdout))
+		case "webhook":
+			if config.WebhookURL == "" {
+				return nil, fmt.Errorf("outbox webhook sink requires OUTBOX_WEBHOOK_URL")
+			}
+			sinks = append(sinks, NewWebhookSink(config.WebhookURL, nil))
+		default:
+			return nil, fmt.Errorf("unknown outbox sink %q", name)
+		}
+	}
+	return sinks, nil
+}
+
+// WebhookSink POSTs every event as JSON to a fixed URL.
+// Any non-2xx response is treated as a failed delivery.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink creates a WebhookSink. A nil client uses one with a 10 second timeout.
+func NewWebhookSink(url string, client *http.Client) *WebhookSink {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &WebhookSink{url: url, client: client}
+}
+
+func (s *WebhookSink) Name() string { return "webhook" }
+
+func (s *WebhookSink) Publish(ctx context.Context, e *event.Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Id", strconv.FormatInt(e.ID, 10))
+	req.Header.Set("X-Event-Type", e.Type)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// WriterSink writes every event as a line of JSON, typically to stdout.
+type WriterSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewWriterSink creates a WriterSink writing to w.
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{enc: json.NewEncoder(w)}
+}
+
+func (s *WriterSink) Name() string { return "stdout" }
+
+func (s *WriterSink) Publish(ctx context.Context, e *event.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(e)
+}
+
+// MemorySink keeps published events in memory, for tests.
+type MemorySink struct {
+	name   string
+	mu     sync.Mutex
+	events []*event.Event
+	// Err, when set, is returned from Publish instead of recording the event.
+	Err error
+}
+
+// NewMemorySink creates an empty MemorySink with the given name.
+func NewMemorySink(name string) *MemorySink {
+	return &MemorySink{name: name}
+}
+
+func (s *MemorySink) Name() string { return s.name }
+
+func (s *MemorySink) Publish(ctx context.Context, e *event.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Err != nil {
+		return s.Err
+	}
+	s.events = append(s.events, e)
+	return nil
+}
+
+// Events returns a copy of the events published so far.
+func (s *MemorySink) Events() []*event.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*event.Event(nil), s.events...)
+}
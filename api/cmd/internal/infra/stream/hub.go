@@ -0,0 +1,168 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+)
+
+// Hub fans relationship events out to the clients connected to this process.
+// It is registered as an outbox sink, so it sees every event exactly in the order the
+// dispatcher publishes them, and keeps the most recent ones so reconnecting clients can
+// resume from the last event id they received.
+type Hub struct {
+	config *config.StreamConfig
+
+	mu      sync.Mutex
+	clients map[string]map[*Client]struct{}
+	recent  []*event.Event
+	seen    map[int64]struct{}
+}
+
+// Client is one connected stream for a single user.
+// Events is closed when the client is unsubscribed or falls too far behind.
+type Client struct {
+	Email  string
+	Events chan *event.Event
+}
+
+// NewHub creates an empty Hub.
+func NewHub(config *config.StreamConfig) *Hub {
+	return &Hub{
+		config:  config,
+		clients: make(map[string]map[*Client]struct{}),
+		seen:    make(map[int64]struct{}),
+	}
+}
+
+func (h *Hub) Name() string { return "stream" }
+
+// Publish records the event for replay and delivers it to every connected recipient.
+// Events already seen are ignored, since the outbox may publish the same event twice.
+// A client whose buffer is full is disconnected rather than allowed to block the hub;
+// it is expected to reconnect with its last event id.
+func (h *Hub) Publish(ctx context.Context, e *event.Event) error {
+	recipients, err := Recipients(e)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.seen[e.ID]; ok {
+		return nil
+	}
+	h.remember(e)
+
+	for _, email := range recipients {
+		for client := range h.clients[email] {
+			select {
+			case client.Events <- e:
+			default:
+				h.removeLocked(client)
+			}
+		}
+	}
+	return nil
+}
+
+// remember appends the event to the replay buffer, evicting the oldest one when full.
+func (h *Hub) remember(e *event.Event) {
+	h.recent = append(h.recent, e)
+	h.seen[e.ID] = struct{}{}
+	if len(h.recent) > h.config.ReplayBufferSize {
+		delete(h.seen, h.recent[0].ID)
+		h.recent = h.recent[1:]
+	}
+}
+
+// Subscribe connects a client for the given user. Buffered events for the user with an id
+// greater than lastEventID are queued on the client before any new event.
+func (h *Hub) Subscribe(email string, lastEventID int64) *Client {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var replay []*event.Event
+	if lastEventID > 0 {
+		for _, e := range h.recent {
+			if e.ID <= lastEventID {
+				continue
+			}
+			if recipients, err := Recipients(e); err == nil && slices.Contains(recipients, email) {
+				replay = append(replay, e)
+			}
+		}
+	}
+
+	size := h.config.ClientBufferSize
+	if len(replay) > size {
+		size = len(replay)
+	}
+	client := &Client{Email: email, Events: make(chan *event.Event, size)}
+	for _, e := range replay {
+		client.Events <- e
+	}
+
+	if h.clients[email] == nil {
+		h.clients[email] = make(map[*Client]struct{})
+	}
+	h.clients[email][client] = struct{}{}
+	return client
+}
+
+// Unsubscribe disconnects a client. It is safe to call more than once.
+func (h *Hub) Unsubscribe(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(client)
+}
+
+// Close disconnects every client, letting their streams end so the server can shut down.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, clients := range h.clients {
+		for client := range clients {
+			h.removeLocked(client)
+		}
+	}
+}
+
+func (h *Hub) removeLocked(client *Client) {
+	clients, ok := h.clients[client.Email]
+	if !ok {
+		return
+	}
+	if _, ok := clients[client]; !ok {
+		return
+	}
+	delete(clients, client)
+	if len(clients) == 0 {
+		delete(h.clients, client.Email)
+	}
+	close(client.Events)
+}
+
+// Recipients returns the emails of the users an event is delivered to: both sides of a
+// relationship change, and every recipient of a posted update.
+func Recipients(e *event.Event) ([]string, error) {
+	switch e.Type {
+	case event.UpdatePosted:
+		var data event.UpdateData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return nil, err
+		}
+		return data.Recipients, nil
+	default:
+		var data event.RelationshipData
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return nil, err
+		}
+		return []string{data.Requestor, data.Target}, nil
+	}
+}
@@ -0,0 +1,112 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = &config.StreamConfig{ReplayBufferSize: 3, ClientBufferSize: 2}
+
+func friendEvent(id int64, requestor, target string) *event.Event {
+	data, _ := json.Marshal(event.RelationshipData{RelationshipID: "r", Requestor: requestor, Target: target})
+	return &event.Event{ID: id, Type: event.FriendCreated, Data: data}
+}
+
+func updateEvent(id int64, sender string, recipients ...string) *event.Event {
+	data, _ := json.Marshal(event.UpdateData{Sender: sender, Text: "hello", Recipients: recipients})
+	return &event.Event{ID: id, Type: event.UpdatePosted, Data: data}
+}
+
+// Tests that events only reach the clients of the users they concern.
+func TestHub_FanOut(t *testing.T) {
+	ctx := context.Background()
+	hub := NewHub(testConfig)
+	alice := hub.Subscribe("alice@example.com", 0)
+	bob := hub.Subscribe("bob@example.com", 0)
+	carol := hub.Subscribe("carol@example.com", 0)
+
+	require.NoError(t, hub.Publish(ctx, friendEvent(1, "alice@example.com", "bob@example.com")))
+	require.NoError(t, hub.Publish(ctx, updateEvent(2, "alice@example.com", "carol@example.com")))
+
+	assert.Equal(t, int64(1), (<-alice.Events).ID)
+	assert.Equal(t, int64(1), (<-bob.Events).ID)
+	assert.Equal(t, int64(2), (<-carol.Events).ID)
+	assert.Len(t, alice.Events, 0)
+	assert.Len(t, bob.Events, 0)
+}
+
+// Tests that a republished event is not delivered twice.
+func TestHub_Deduplicates(t *testing.T) {
+	ctx := context.Background()
+	hub := NewHub(testConfig)
+	alice := hub.Subscribe("alice@example.com", 0)
+
+	require.NoError(t, hub.Publish(ctx, friendEvent(1, "alice@example.com", "bob@example.com")))
+	require.NoError(t, hub.Publish(ctx, friendEvent(1, "alice@example.com", "bob@example.com")))
+
+	assert.Len(t, alice.Events, 1)
+}
+
+// Tests that a reconnecting client receives the buffered events after its last event id.
+func TestHub_Resume(t *testing.T) {
+	ctx := context.Background()
+	hub := NewHub(testConfig)
+
+	require.NoError(t, hub.Publish(ctx, friendEvent(1, "alice@example.com", "bob@example.com")))
+	require.NoError(t, hub.Publish(ctx, friendEvent(2, "carol@example.com", "bob@example.com")))
+	require.NoError(t, hub.Publish(ctx, updateEvent(3, "bob@example.com", "alice@example.com")))
+
+	alice := hub.Subscribe("alice@example.com", 1)
+	require.Len(t, alice.Events, 1)
+	assert.Equal(t, int64(3), (<-alice.Events).ID)
+
+	// Without a last event id nothing is replayed.
+	fresh := hub.Subscribe("bob@example.com", 0)
+	assert.Len(t, fresh.Events, 0)
+
+	// Event 1 is evicted from the three element replay buffer, event 2 is still there.
+	require.NoError(t, hub.Publish(ctx, friendEvent(4, "dave@example.com", "erin@example.com")))
+	bob := hub.Subscribe("bob@example.com", 1)
+	require.Len(t, bob.Events, 1)
+	assert.Equal(t, int64(2), (<-bob.Events).ID)
+}
+
+// Tests that a client which stops reading is disconnected instead of blocking the hub.
+func TestHub_SlowClientDropped(t *testing.T) {
+	ctx := context.Background()
+	hub := NewHub(testConfig)
+	slow := hub.Subscribe("alice@example.com", 0)
+
+	for id := int64(1); id <= 3; id++ {
+		require.NoError(t, hub.Publish(ctx, friendEvent(id, "alice@example.com", "bob@example.com")))
+	}
+
+	received := 0
+	for range slow.Events {
+		received++
+	}
+	assert.Equal(t, 2, received)
+
+	// Unsubscribing a dropped client is a no-op.
+	hub.Unsubscribe(slow)
+}
+
+// Tests that closing the hub ends every client stream.
+func TestHub_Close(t *testing.T) {
+	hub := NewHub(testConfig)
+	alice := hub.Subscribe("alice@example.com", 0)
+	bob := hub.Subscribe("bob@example.com", 0)
+
+	hub.Close()
+
+	_, open := <-alice.Events
+	assert.False(t, open)
+	_, open = <-bob.Events
+	assert.False(t, open)
+}
@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+)
+
+// ServiceName identifies the application in exported spans, unless OTEL_SERVICE_NAME overrides it.
+const ServiceName = "friends-management"
+
+// instrumentationName is the instrumentation scope of every span the application starts.
+const instrumentationName = "github.com/koeylp/friends-management"
+
+// Propagator reads and writes W3C trace context and baggage headers.
+var Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Tracer returns the tracer application spans are started with. It goes through the global
+// provider, so spans started before Register has run are simply not recorded.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a child span of the trace in ctx. Outside of a trace it starts nothing and returns
+// ctx with a span that is not recorded, so background polling does not start a trace every interval.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// NewExporter creates the span exporter named by the configuration, or returns nil when tracing is disabled.
+func NewExporter(ctx context.Context, cfg *config.TraceConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case "", "none":
+		return nil, nil
+	case "stdout":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "otlp":
+		return otlptracegrpc.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q: use otlp, stdout or none", cfg.Exporter)
+	}
+}
+
+// Register installs the propagator and, when an exporter is configured, a tracer provider
+// batching sampled spans to it, globally for the lifetime of the application. Pending spans
+// are flushed on stop. Without an exporter spans are not recorded, but trace context is still
+// propagated.
+func Register(lc fx.Lifecycle, cfg *config.TraceConfig) error {
+	otel.SetTextMapPropagator(Propagator)
+
+	exporter, err := NewExporter(context.Background(), cfg)
+	if err != nil || exporter == nil {
+		return err
+	}
+	res, err := resource.New(context.Background(),
+		resource.WithAttributes(semconv.ServiceName(ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	lc.Append(fx.Hook{OnStop: provider.Shutdown})
+	return nil
+}
+
+// End ends span after recording *err. It is meant to be deferred with a named error result:
+//
+//	ctx, span := tracing.Start(ctx, "RelationshipRepository.GetFriends")
+//	defer tracing.End(span, &err)
+//
+// Domain errors, such as a user that does not exist, are answers rather than failures and
+// do not mark the span as failed.
+func End(span trace.Span, err *error) {
+	var domainErr *domain.Error
+	if *err != nil && !errors.As(*err, &domainErr) {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newRecorder installs a tracer provider recording every span for the duration of the test.
+func newRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// Test that spans are only started within a trace.
+func TestStart(t *testing.T) {
+	recorder := newRecorder(t)
+
+	ctx, span := Start(context.Background(), "orphan")
+	assert.False(t, span.IsRecording())
+	span.End()
+
+	ctx, root := Tracer().Start(ctx, "root")
+	_, child := Start(ctx, "child")
+	child.End()
+	root.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, root.SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
+
+// Test that spans fail on errors, but not on domain errors.
+func TestEnd(t *testing.T) {
+	recorder := newRecorder(t)
+	ctx, root := Tracer().Start(context.Background(), "root")
+	defer root.End()
+
+	for _, err := range []error{nil, domain.Errorf(domain.ErrUserNotFound, "user not found: andy@example.com"), errors.New("connection refused")} {
+		_, span := Start(ctx, "call")
+		End(span, &err)
+	}
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+	assert.Equal(t, "connection refused", spans[2].Status().Description)
+}
+
+// Test that statements are recorded as spans named after their operation.
+func TestQueryTracer(t *testing.T) {
+	recorder := newRecorder(t)
+	ctx, root := Tracer().Start(context.Background(), "root")
+	defer root.End()
+
+	tracer := QueryTracer{}
+	queryCtx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "select * from users where email = $1"})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{})
+	queryCtx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "  INSERT INTO users (email) VALUES ($1)"})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{Err: errors.New("duplicate key")})
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "SELECT", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "INSERT", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+// Test that only the supported exporters are accepted.
+func TestNewExporter(t *testing.T) {
+	exporter, err := NewExporter(context.Background(), &config.TraceConfig{Exporter: "none"})
+	assert.NoError(t, err)
+	assert.Nil(t, exporter)
+
+	exporter, err = NewExporter(context.Background(), &config.TraceConfig{Exporter: "stdout"})
+	assert.NoError(t, err)
+	assert.NotNil(t, exporter)
+
+	_, err = NewExporter(context.Background(), &config.TraceConfig{Exporter: "jaeger"})
+	assert.ErrorContains(t, err, `unknown trace exporter "jaeger"`)
+}
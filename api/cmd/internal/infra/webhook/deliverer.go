@@ -0,0 +1,179 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"github.com/koeylp/friends-management/cmd/internal/infra/outbox"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+	webhookRepo "github.com/koeylp/friends-management/cmd/internal/repository/webhook"
+	"go.uber.org/fx"
+)
+
+// claimLease is how long a claimed delivery stays hidden from other deliverers.
+const claimLease = time.Minute
+
+// Deliverer sends queued webhook deliveries, signing each request with the subscription secret.
+// Failed deliveries are retried with exponential backoff until the configured number of attempts,
+// after which they are marked failed. Every attempt is recorded in the delivery history.
+type Deliverer struct {
+	repo   webhookRepo.WebhookRepository
+	client *http.Client
+	config *config.WebhookConfig
+	logger *slog.Logger
+	now    func() time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewDeliverer creates a new Deliverer.
+func NewDeliverer(repo webhookRepo.WebhookRepository, config *config.WebhookConfig, logger *slog.Logger) *Deliverer {
+	return &Deliverer{
+		repo:   repo,
+		client: &http.Client{Timeout: config.RequestTimeout},
+		config: config,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// DeliverOnce claims one batch of due deliveries and sends them.
+// It returns the number of deliveries that succeeded.
+func (d *Deliverer) DeliverOnce(ctx context.Context) (int, error) {
+	deliveries, err := d.repo.ClaimDueDeliveries(ctx, d.config.BatchSize, claimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	succeeded := 0
+	for _, delivery := range deliveries {
+		attempt := d.send(ctx, delivery)
+
+		status := webhook.StatusSucceeded
+		nextAttemptAt := attempt.AttemptedAt
+		if attempt.Error != "" {
+			status = webhook.StatusPending
+			nextAttemptAt = attempt.AttemptedAt.Add(outbox.Backoff(delivery.Attempts))
+			if delivery.Attempts >= d.config.MaxAttempts {
+				status = webhook.StatusFailed
+			}
+			d.logger.ErrorContext(ctx, "webhook delivery failed",
+				"delivery_id", delivery.ID, "attempt", delivery.Attempts, "status", status, "error", attempt.Error)
+		}
+
+		if err := d.repo.RecordAttempt(ctx, delivery.ID, attempt, status, nextAttemptAt); err != nil {
+			return succeeded, err
+		}
+		if status == webhook.StatusSucceeded {
+			succeeded++
+		}
+	}
+	return succeeded, nil
+}
+
+// send makes a single signed request for the delivery and describes its outcome.
+func (d *Deliverer) send(ctx context.Context, delivery *webhook.PendingDelivery) *webhook.DeliveryAttempt {
+	started := d.now()
+	attempt := &webhook.DeliveryAttempt{Attempt: delivery.Attempts, AttemptedAt: started}
+
+	err := func() error {
+		body, err := json.Marshal(delivery.Event)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		timestamp := started.Unix()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, body))
+		req.Header.Set(EventIDHeader, strconv.FormatInt(delivery.Event.ID, 10))
+		req.Header.Set(EventTypeHeader, delivery.Event.Type)
+
+		res, err := d.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		_, _ = io.Copy(io.Discard, res.Body)
+
+		attempt.StatusCode = &res.StatusCode
+		if res.StatusCode < 200 || res.StatusCode >= 300 {
+			return fmt.Errorf("unexpected status %d", res.StatusCode)
+		}
+		return nil
+	}()
+
+	attempt.DurationMs = time.Since(started).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	return attempt
+}
+
+// Run delivers webhooks every poll interval until ctx is cancelled.
+func (d *Deliverer) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DeliverOnce(ctx); err != nil && ctx.Err() == nil {
+			d.logger.ErrorContext(ctx, "webhook delivery run failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Start runs the deliverer in the background. Its queries are logged through the deliverer's logger.
+func (d *Deliverer) Start() {
+	ctx, cancel := context.WithCancel(logging.NewContext(context.Background(), d.logger))
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	go func() {
+		defer close(d.done)
+		d.Run(ctx)
+	}()
+}
+
+// Stop cancels the background deliverer and waits for the current batch to finish.
+func (d *Deliverer) Stop(ctx context.Context) error {
+	if d.cancel == nil {
+		return nil
+	}
+	d.cancel()
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RegisterDeliverer ties the deliverer to the application lifecycle.
+func RegisterDeliverer(lc fx.Lifecycle, d *Deliverer) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			d.Start()
+			return nil
+		},
+		OnStop: d.Stop,
+	})
+}
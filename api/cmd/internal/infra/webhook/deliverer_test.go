@@ -0,0 +1,166 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = &config.WebhookConfig{PollInterval: 10 * time.Millisecond, BatchSize: 10, MaxAttempts: 3, RequestTimeout: time.Second}
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// receivedRequest is what the local receiver saw for one delivery.
+type receivedRequest struct {
+	event     event.Event
+	eventType string
+	verified  bool
+}
+
+// newReceiver starts a local webhook receiver that verifies signatures with secret and answers with status.
+func newReceiver(t *testing.T, secret string, status *int) (*httptest.Server, *[]receivedRequest) {
+	var received []receivedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+
+		var req receivedRequest
+		_ = json.Unmarshal(body, &req.event)
+		req.eventType = r.Header.Get(EventTypeHeader)
+		req.verified = Verify(secret, timestamp, body, r.Header.Get(SignatureHeader))
+		received = append(received, req)
+
+		w.WriteHeader(*status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func newPendingDelivery(url string, attempts int) *webhook.PendingDelivery {
+	return &webhook.PendingDelivery{
+		ID:       "del-1",
+		Attempts: attempts,
+		URL:      url,
+		Secret:   "s3cr3t",
+		Event: &event.Event{
+			ID:   9,
+			Type: event.BlockCreated,
+			Data: json.RawMessage(`{"relationship_id":"r1","requestor":"a@example.com","target":"b@example.com"}`),
+		},
+	}
+}
+
+// Tests that a delivery is signed, accepted by the receiver and recorded as succeeded.
+func TestDeliverOnce_Success(t *testing.T) {
+	ctx := context.Background()
+	status := http.StatusNoContent
+	server, received := newReceiver(t, "s3cr3t", &status)
+
+	repo := new(MockWebhookRepository)
+	d := NewDeliverer(repo, testConfig, testLogger)
+
+	repo.On("ClaimDueDeliveries", ctx, 10, claimLease).Return([]*webhook.PendingDelivery{newPendingDelivery(server.URL, 1)}, nil)
+	repo.On("RecordAttempt", ctx, "del-1", mock.MatchedBy(func(a *webhook.DeliveryAttempt) bool {
+		return a.Attempt == 1 && a.Error == "" && *a.StatusCode == http.StatusNoContent
+	}), webhook.StatusSucceeded, mock.Anything).Return(nil)
+
+	n, err := d.DeliverOnce(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, *received, 1)
+	assert.True(t, (*received)[0].verified)
+	assert.Equal(t, event.BlockCreated, (*received)[0].eventType)
+	assert.Equal(t, int64(9), (*received)[0].event.ID)
+	repo.AssertExpectations(t)
+}
+
+// Tests that a rejected delivery is rescheduled with exponential backoff.
+func TestDeliverOnce_RetryWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	status := http.StatusServiceUnavailable
+	server, received := newReceiver(t, "s3cr3t", &status)
+
+	repo := new(MockWebhookRepository)
+	d := NewDeliverer(repo, testConfig, testLogger)
+	now := time.Date(2024, 10, 28, 2, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	repo.On("ClaimDueDeliveries", ctx, 10, claimLease).Return([]*webhook.PendingDelivery{newPendingDelivery(server.URL, 2)}, nil)
+	repo.On("RecordAttempt", ctx, "del-1", mock.MatchedBy(func(a *webhook.DeliveryAttempt) bool {
+		return a.Attempt == 2 && a.Error == "unexpected status 503" && *a.StatusCode == http.StatusServiceUnavailable
+	}), webhook.StatusPending, now.Add(2*time.Second)).Return(nil)
+
+	n, err := d.DeliverOnce(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Len(t, *received, 1)
+	repo.AssertExpectations(t)
+}
+
+// Tests that a delivery is marked failed once it runs out of attempts.
+func TestDeliverOnce_Exhausted(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockWebhookRepository)
+	d := NewDeliverer(repo, testConfig, testLogger)
+
+	// Nothing listens on this address, so the request itself fails.
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	repo.On("ClaimDueDeliveries", ctx, 10, claimLease).Return([]*webhook.PendingDelivery{newPendingDelivery(url, 3)}, nil)
+	repo.On("RecordAttempt", ctx, "del-1", mock.MatchedBy(func(a *webhook.DeliveryAttempt) bool {
+		return a.StatusCode == nil && a.Error != ""
+	}), webhook.StatusFailed, mock.Anything).Return(nil)
+
+	_, err := d.DeliverOnce(ctx)
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+// Tests that the outbox sink only queues deliveries for subscriptions matching the event.
+func TestSink_Publish(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockWebhookRepository)
+	sink := NewSink(repo)
+
+	repo.On("ListSubscriptions", ctx).Return([]*webhook.Subscription{
+		{ID: "all", Events: []string{webhook.AllEvents}},
+		{ID: "friends", Events: []string{event.FriendCreated}},
+		{ID: "blocks", Events: []string{event.BlockCreated, event.UpdatePosted}},
+	}, nil)
+	repo.On("EnqueueDeliveries", ctx, int64(9), []string{"all", "blocks"}).Return(nil)
+
+	err := sink.Publish(ctx, &event.Event{ID: 9, Type: event.BlockCreated})
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+// Tests that signatures only verify for the same secret, timestamp and body.
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	signature := Sign("s3cr3t", 1730080800, body)
+
+	assert.True(t, Verify("s3cr3t", 1730080800, body, signature))
+	assert.False(t, Verify("other", 1730080800, body, signature))
+	assert.False(t, Verify("s3cr3t", 1730080801, body, signature))
+	assert.False(t, Verify("s3cr3t", 1730080800, []byte(`{"id":2}`), signature))
+	assert.False(t, Verify("s3cr3t", 1730080800, body, signature[len("sha256="):]))
+}
@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+	webhookRepo "github.com/koeylp/friends-management/cmd/internal/repository/webhook"
+)
+
+// Sink is the outbox sink that turns each relationship event into one queued delivery
+// per matching webhook subscription. The Deliverer sends them independently, so a slow
+// or failing endpoint never holds back the outbox.
+type Sink struct {
+	repo webhookRepo.WebhookRepository
+}
+
+// NewSink creates a new webhook Sink.
+func NewSink(repo webhookRepo.WebhookRepository) *Sink {
+	return &Sink{repo: repo}
+}
+
+func (s *Sink) Name() string { return "webhooks" }
+
+func (s *Sink) Publish(ctx context.Context, e *event.Event) error {
+	subscriptions, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	var matched []string
+	for _, subscription := range subscriptions {
+		if subscription.Matches(e.Type) {
+			matched = append(matched, subscription.ID)
+		}
+	}
+	return s.repo.EnqueueDeliveries(ctx, e.ID, matched)
+}
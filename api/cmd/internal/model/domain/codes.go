@@ -0,0 +1,26 @@
+package domain
+
+// Error codes are the stable, machine-readable identifiers clients should branch on
+// instead of matching the human-readable detail. The REST problem responses and the
+// GraphQL error extensions both report them.
+const (
+	CodeBadRequest         = "BAD_REQUEST"
+	CodeInvalidPayload     = "INVALID_PAYLOAD"
+	CodeValidationFailed   = "VALIDATION_FAILED"
+	CodeNotFound           = "NOT_FOUND"
+	CodeUserNotFound       = "USER_NOT_FOUND"
+	CodeWebhookNotFound    = "WEBHOOK_NOT_FOUND"
+	CodeNotFriends         = "FRIENDSHIP_NOT_FOUND"
+	CodeNotSubscribed      = "SUBSCRIPTION_NOT_FOUND"
+	CodeNotBlocked         = "BLOCK_NOT_FOUND"
+	CodeForbidden          = "FORBIDDEN"
+	CodeBlocked            = "BLOCKED"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeConflict           = "CONFLICT"
+	CodeUserExists         = "USER_EXISTS"
+	CodeFriendshipExists   = "FRIENDSHIP_EXISTS"
+	CodeSubscriptionExists = "SUBSCRIPTION_EXISTS"
+	CodeBlockExists        = "BLOCK_EXISTS"
+	CodePayloadTooLarge    = "PAYLOAD_TOO_LARGE"
+	CodeInternal           = "INTERNAL_ERROR"
+)
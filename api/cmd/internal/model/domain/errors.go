@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors describing why a business operation failed.
+// Controllers and repositories return them, usually wrapped with Errorf to add detail,
+// and each frontend decides how to present them: the REST handlers translate them to
+// problem responses, other frontends can match them with errors.Is.
+var (
+	ErrInvalidArgument   = errors.New("invalid argument")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrUserExists        = errors.New("user already exists")
+	ErrAlreadyFriends    = errors.New("friendship already exists")
+	ErrAlreadySubscribed = errors.New("subscription already exists")
+	ErrAlreadyBlocked    = errors.New("blocking updates already exists")
+	ErrBlocked           = errors.New("blocking updates exists")
+	ErrNotFriends        = errors.New("friendship not found")
+	ErrNotSubscribed     = errors.New("subscription not found")
+	ErrNotBlocked        = errors.New("blocking updates not found")
+	ErrWebhookNotFound   = errors.New("webhook subscription not found")
+)
+
+// Error is a domain error with a message describing the specific failure.
+// It matches its kind with errors.Is.
+type Error struct {
+	Kind    error
+	Message string
+}
+
+// Errorf returns an Error of the given kind with a formatted message.
+func Errorf(kind error, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
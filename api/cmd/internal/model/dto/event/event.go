@@ -0,0 +1,43 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	FriendCreated       = "friend.created"
+	FriendDeleted       = "friend.deleted"
+	SubscriptionCreated = "subscription.created"
+	SubscriptionDeleted = "subscription.deleted"
+	BlockCreated        = "block.created"
+	BlockDeleted        = "block.deleted"
+	UpdatePosted        = "update.posted"
+)
+
+// Event is a relationship change recorded in the outbox and published to sinks.
+// IDs increase monotonically, so consumers can use them to resume a stream.
+type Event struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+	Attempts  int             `json:"-"`
+	// PublishedSinks names the sinks that accepted the event on earlier attempts.
+	PublishedSinks []string `json:"-"`
+}
+
+// RelationshipData is the payload of the friend, subscription and block events.
+// For the deleted events, RelationshipID is the id of the removed relationship.
+type RelationshipData struct {
+	RelationshipID string `json:"relationship_id"`
+	Requestor      string `json:"requestor"`
+	Target         string `json:"target"`
+}
+
+// UpdateData is the payload of the update.posted event.
+type UpdateData struct {
+	Sender     string   `json:"sender"`
+	Text       string   `json:"text"`
+	Recipients []string `json:"recipients"`
+}
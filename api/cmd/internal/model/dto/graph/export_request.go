@@ -0,0 +1,20 @@
+package graph
+
+const (
+	FormatNDJSON  = "ndjson"
+	FormatCSV     = "csv"
+	FormatGraphML = "graphml"
+	FormatDOT     = "dot"
+
+	DefaultDepth = 1
+)
+
+type ExportRequest struct {
+	Format string `json:"format" validate:"required,oneof=ndjson csv graphml dot"`
+	Email  string `json:"email" validate:"omitempty,email"`
+	Depth  int    `json:"depth" validate:"omitempty,min=1,max=5"`
+}
+
+func ValidateExportRequest(req *ExportRequest) error {
+	return validate.Struct(req)
+}
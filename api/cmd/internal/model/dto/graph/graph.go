@@ -0,0 +1,26 @@
+package graph
+
+import "time"
+
+// Node is a user vertex in the exported social graph.
+type Node struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Edge is a directed relationship between two users in the exported social graph.
+type Edge struct {
+	ID               string    `json:"id"`
+	Source           string    `json:"source"`
+	Target           string    `json:"target"`
+	RelationshipType string    `json:"relationship_type"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Filter restricts an export to the ego network of a single user.
+// A nil Filter exports the whole graph.
+type Filter struct {
+	UserID string
+	Depth  int
+}
@@ -0,0 +1,8 @@
+package graph
+
+// QueryRequest is a GraphQL query as posted over HTTP.
+type QueryRequest struct {
+	Query         string                 `json:"query" validate:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
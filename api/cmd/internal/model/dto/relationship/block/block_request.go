@@ -2,7 +2,7 @@ package block
 
 type BlockRequest struct {
 	Requestor string `json:"requestor" validate:"required,email"`
-	Target    string `json:"target" validate:"required,email"`
+	Target    string `json:"target" validate:"required,email,nefield=Requestor"`
 }
 
 func ValidateBlockRequest(req *BlockRequest) error {
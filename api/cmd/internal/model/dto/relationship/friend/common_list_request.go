@@ -1,7 +1,7 @@
 package friend
 
 type CommonFriendListReq struct {
-	Friends []string `json:"friends" validate:"required,dive,email"`
+	Friends []string `json:"friends" validate:"required,len=2,unique,dive,email"`
 }
 
 func ValidateCommonFriendListRequest(req *CommonFriendListReq) error {
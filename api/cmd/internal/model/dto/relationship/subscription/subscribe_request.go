@@ -2,7 +2,7 @@ package subscription
 
 type SubscribeRequest struct {
 	Requestor string `json:"requestor" validate:"required,email"`
-	Target    string `json:"target" validate:"required,email"`
+	Target    string `json:"target" validate:"required,email,nefield=Requestor"`
 }
 
 func ValidateSubscribeRequest(req *SubscribeRequest) error {
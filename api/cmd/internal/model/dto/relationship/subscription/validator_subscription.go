@@ -1,5 +1,5 @@
 package subscription
 
-import "github.com/go-playground/validator"
+import utils "github.com/koeylp/friends-management/cmd/internal/pkg/validation_util"
 
-var validate = validator.New()
+var validate = utils.Validator()
@@ -0,0 +1,10 @@
+package webhook
+
+type CreateSubscriptionRequest struct {
+	URL    string   `json:"url" validate:"required,url"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=* friend.created friend.deleted subscription.created subscription.deleted block.created block.deleted update.posted"`
+}
+
+func ValidateCreateSubscriptionRequest(req *CreateSubscriptionRequest) error {
+	return validate.Struct(req)
+}
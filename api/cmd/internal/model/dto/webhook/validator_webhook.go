@@ -0,0 +1,5 @@
+package webhook
+
+import utils "github.com/koeylp/friends-management/cmd/internal/pkg/validation_util"
+
+var validate = utils.Validator()
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"slices"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+)
+
+const (
+	// AllEvents subscribes to every event type.
+	AllEvents = "*"
+
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Subscription is a registered webhook endpoint and the event types it receives.
+// The secret is only returned when the subscription is created.
+type Subscription struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Matches reports whether the subscription receives events of the given type.
+func (s *Subscription) Matches(eventType string) bool {
+	return slices.Contains(s.Events, AllEvents) || slices.Contains(s.Events, eventType)
+}
+
+// Delivery is the delivery of one event to one subscription, with its attempt history.
+type Delivery struct {
+	ID             string             `json:"id"`
+	SubscriptionID string             `json:"subscription_id"`
+	EventID        int64              `json:"event_id"`
+	EventType      string             `json:"event_type"`
+	Status         string             `json:"status"`
+	Attempts       int                `json:"attempts"`
+	LastStatusCode *int               `json:"last_status_code,omitempty"`
+	NextAttemptAt  time.Time          `json:"next_attempt_at"`
+	CreatedAt      time.Time          `json:"created_at"`
+	History        []*DeliveryAttempt `json:"history"`
+}
+
+// DeliveryAttempt records the outcome of one HTTP request made for a delivery.
+type DeliveryAttempt struct {
+	Attempt     int       `json:"attempt"`
+	StatusCode  *int      `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMs  int64     `json:"duration_ms"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
+
+// PendingDelivery is a claimed delivery together with everything needed to send it.
+type PendingDelivery struct {
+	ID       string
+	Attempts int
+	URL      string
+	Secret   string
+	Event    *event.Event
+}
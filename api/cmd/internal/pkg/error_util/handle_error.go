@@ -2,29 +2,122 @@ package utils
 
 import (
 	"errors"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	responses "github.com/koeylp/friends-management/cmd/internal/handler/rest/response"
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	validation "github.com/koeylp/friends-management/cmd/internal/pkg/validation_util"
 )
 
 // HandleError is a utility function that processes errors and sends the appropriate HTTP response.
-// It checks the type of error and responds accordingly:
-// - If the error is of type NotFoundError, it sends a 404 Not Found response.
-// - If the error is of type BadRequestError, it sends a 400 Bad Request response.
-// - For all other errors, it sends a 500 Internal Server Error response.
+// Domain errors are translated to problems by fromDomain, and validation errors to a
+// 400 Bad Request listing every invalid field.
+// Every typed error from the response package is sent as a problem with its own status and code:
+// - NotFoundError as 404 Not Found.
+// - BadRequestError as 400 Bad Request.
+// - ForbiddenError as 403 Forbidden.
+// - UnauthorizedError as 401 Unauthorized.
+// - ConflictError as 409 Conflict.
+// - PayloadTooLargeError as 413 Payload Too Large.
+// - InternalServerError and any other error as 500 Internal Server Error.
+// The request path is reported as the problem instance. Server errors are logged at error level
+// and client errors at debug level, through the request's logger.
 //
 // Parameters:
 // - w: http.ResponseWriter used to write the HTTP response.
+// - r: the request that failed.
 // - err: the error that needs to be handled.
-func HandleError(w http.ResponseWriter, err error) {
+func HandleError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := toProblem(err)
+	problem.Instance = r.URL.Path
+
+	level := slog.LevelDebug
+	if problem.Status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	ctx := r.Context()
+	logging.FromContext(ctx).Log(ctx, level, "request failed",
+		slog.Int("status", problem.Status), slog.String("code", problem.Code), slog.String("error", err.Error()))
+
+	problem.Send(w)
+}
+
+// toProblem finds the validation, domain or typed error in err's chain, falling back to an internal server error.
+func toProblem(err error) *responses.ErrorResponse {
+	if fieldErrs := validation.FieldErrors(err); len(fieldErrs) > 0 {
+		messages := make([]string, len(fieldErrs))
+		for i, fieldErr := range fieldErrs {
+			messages[i] = fieldErr.Message
+		}
+		return responses.NewValidationError(strings.Join(messages, "; "), fieldErrs...).ErrorResponse
+	}
+	if problem := fromDomain(err); problem != nil {
+		return problem
+	}
+
 	var notFoundErr *responses.NotFoundError
 	var badRequestErr *responses.BadRequestError
+	var forbiddenErr *responses.ForbiddenError
+	var unauthorizedErr *responses.UnauthorizedError
+	var conflictErr *responses.ConflictError
+	var tooLargeErr *responses.PayloadTooLargeError
+	var internalErr *responses.InternalServerError
 	switch {
 	case errors.As(err, &notFoundErr):
-		notFoundErr.Send(w)
+		return notFoundErr.ErrorResponse
 	case errors.As(err, &badRequestErr):
-		badRequestErr.Send(w)
+		return badRequestErr.ErrorResponse
+	case errors.As(err, &forbiddenErr):
+		return forbiddenErr.ErrorResponse
+	case errors.As(err, &unauthorizedErr):
+		return unauthorizedErr.ErrorResponse
+	case errors.As(err, &conflictErr):
+		return conflictErr.ErrorResponse
+	case errors.As(err, &tooLargeErr):
+		return tooLargeErr.ErrorResponse
+	case errors.As(err, &internalErr):
+		return internalErr.ErrorResponse
+	default:
+		return responses.NewInternalServerError(err.Error()).ErrorResponse
+	}
+}
+
+// fromDomain translates a domain error to a problem, or returns nil if err is not one.
+// The problem detail is the domain error message.
+func fromDomain(err error) *responses.ErrorResponse {
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) {
+		return nil
+	}
+	message := domainErr.Error()
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidArgument):
+		return responses.NewBadRequestError(message).ErrorResponse
+	case errors.Is(err, domain.ErrUserNotFound):
+		return responses.NewNotFoundError(message).WithCode(domain.CodeUserNotFound).ErrorResponse
+	case errors.Is(err, domain.ErrWebhookNotFound):
+		return responses.NewNotFoundError(message).WithCode(domain.CodeWebhookNotFound).ErrorResponse
+	case errors.Is(err, domain.ErrNotFriends):
+		return responses.NewNotFoundError(message).WithCode(domain.CodeNotFriends).ErrorResponse
+	case errors.Is(err, domain.ErrNotSubscribed):
+		return responses.NewNotFoundError(message).WithCode(domain.CodeNotSubscribed).ErrorResponse
+	case errors.Is(err, domain.ErrNotBlocked):
+		return responses.NewNotFoundError(message).WithCode(domain.CodeNotBlocked).ErrorResponse
+	case errors.Is(err, domain.ErrBlocked):
+		return responses.NewForbiddenError(message).WithCode(domain.CodeBlocked).ErrorResponse
+	case errors.Is(err, domain.ErrUserExists):
+		return responses.NewConflictError(message).WithCode(domain.CodeUserExists).ErrorResponse
+	case errors.Is(err, domain.ErrAlreadyFriends):
+		return responses.NewConflictError(message).WithCode(domain.CodeFriendshipExists).ErrorResponse
+	case errors.Is(err, domain.ErrAlreadySubscribed):
+		return responses.NewConflictError(message).WithCode(domain.CodeSubscriptionExists).ErrorResponse
+	case errors.Is(err, domain.ErrAlreadyBlocked):
+		return responses.NewConflictError(message).WithCode(domain.CodeBlockExists).ErrorResponse
 	default:
-		responses.NewInternalServerError(err.Error()).Send(w)
+		return nil
 	}
 }
@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+)
+
+// GraphEncoder writes a social graph to an output stream one element at a time.
+// Begin must be called before any node or edge, all nodes are written before edges,
+// and End flushes whatever the format still buffers.
+type GraphEncoder interface {
+	Begin() error
+	WriteNode(node *graph.Node) error
+	WriteEdge(edge *graph.Edge) error
+	End() error
+}
+
+// ContentTypes maps every supported export format to the media type it is served with.
+var ContentTypes = map[string]string{
+	graph.FormatNDJSON:  "application/x-ndjson",
+	graph.FormatCSV:     "text/csv",
+	graph.FormatGraphML: "application/graphml+xml",
+	graph.FormatDOT:     "text/vnd.graphviz",
+}
+
+// NewGraphEncoder returns the encoder for the given format writing to w.
+func NewGraphEncoder(format string, w io.Writer) (GraphEncoder, error) {
+	switch format {
+	case graph.FormatNDJSON:
+		return &ndjsonEncoder{enc: json.NewEncoder(w)}, nil
+	case graph.FormatCSV:
+		return &csvEncoder{w: csv.NewWriter(w)}, nil
+	case graph.FormatGraphML:
+		return &graphMLEncoder{w: bufio.NewWriter(w)}, nil
+	case graph.FormatDOT:
+		return &dotEncoder{w: bufio.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// ndjsonEncoder writes one JSON object per line, tagged with its element kind.
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+type ndjsonNode struct {
+	Kind string `json:"kind"`
+	*graph.Node
+}
+
+type ndjsonEdge struct {
+	Kind string `json:"kind"`
+	*graph.Edge
+}
+
+func (e *ndjsonEncoder) Begin() error { return nil }
+
+func (e *ndjsonEncoder) WriteNode(node *graph.Node) error {
+	return e.enc.Encode(ndjsonNode{Kind: "node", Node: node})
+}
+
+func (e *ndjsonEncoder) WriteEdge(edge *graph.Edge) error {
+	return e.enc.Encode(ndjsonEdge{Kind: "edge", Edge: edge})
+}
+
+func (e *ndjsonEncoder) End() error { return nil }
+
+// csvEncoder writes nodes and edges into a single table distinguished by the kind column.
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvEncoder) Begin() error {
+	return e.w.Write([]string{"kind", "id", "email", "source", "target", "relationship_type", "created_at"})
+}
+
+func (e *csvEncoder) WriteNode(node *graph.Node) error {
+	return e.w.Write([]string{"node", node.ID, node.Email, "", "", "", node.CreatedAt.Format(time.RFC3339)})
+}
+
+func (e *csvEncoder) WriteEdge(edge *graph.Edge) error {
+	return e.w.Write([]string{"edge", edge.ID, "", edge.Source, edge.Target, edge.RelationshipType, edge.CreatedAt.Format(time.RFC3339)})
+}
+
+func (e *csvEncoder) End() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// graphMLEncoder writes a directed GraphML document keyed by user email.
+type graphMLEncoder struct {
+	w *bufio.Writer
+}
+
+const graphMLHeader = xml.Header + `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="created_at" for="all" attr.name="created_at" attr.type="string"/>
+  <key id="relationship_type" for="edge" attr.name="relationship_type" attr.type="string"/>
+  <graph id="friends" edgedefault="directed">
+`
+
+func (e *graphMLEncoder) Begin() error {
+	_, err := e.w.WriteString(graphMLHeader)
+	return err
+}
+
+func (e *graphMLEncoder) WriteNode(node *graph.Node) error {
+	_, err := fmt.Fprintf(e.w, "    <node id=\"%s\"><data key=\"created_at\">%s</data></node>\n",
+		escapeXML(node.Email), node.CreatedAt.Format(time.RFC3339))
+	return err
+}
+
+func (e *graphMLEncoder) WriteEdge(edge *graph.Edge) error {
+	_, err := fmt.Fprintf(e.w, "    <edge id=\"%s\" source=\"%s\" target=\"%s\"><data key=\"relationship_type\">%s</data><data key=\"created_at\">%s</data></edge>\n",
+		escapeXML(edge.ID), escapeXML(edge.Source), escapeXML(edge.Target), escapeXML(edge.RelationshipType), edge.CreatedAt.Format(time.RFC3339))
+	return err
+}
+
+func (e *graphMLEncoder) End() error {
+	if _, err := e.w.WriteString("  </graph>\n</graphml>\n"); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+// dotEncoder writes a Graphviz digraph with the relationship type as the edge label.
+type dotEncoder struct {
+	w *bufio.Writer
+}
+
+func (e *dotEncoder) Begin() error {
+	_, err := e.w.WriteString("digraph friends {\n")
+	return err
+}
+
+func (e *dotEncoder) WriteNode(node *graph.Node) error {
+	_, err := fmt.Fprintf(e.w, "  %s;\n", strconv.Quote(node.Email))
+	return err
+}
+
+func (e *dotEncoder) WriteEdge(edge *graph.Edge) error {
+	_, err := fmt.Fprintf(e.w, "  %s -> %s [label=%s];\n",
+		strconv.Quote(edge.Source), strconv.Quote(edge.Target), strconv.Quote(edge.RelationshipType))
+	return err
+}
+
+func (e *dotEncoder) End() error {
+	if _, err := e.w.WriteString("}\n"); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+// escapeXML escapes s for use inside an XML attribute or text node.
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
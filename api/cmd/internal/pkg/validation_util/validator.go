@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator"
+)
+
+// FieldError describes one validation rule a request field failed.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// englishMessages are the message templates for the rules used by the DTOs.
+// {0} is the field name and {1} the rule parameter. Rules whose meaning depends on the field
+// kind have one template per kind, keyed "<rule>-items", "<rule>-string" and "<rule>-number".
+var englishMessages = map[string]string{
+	"required":     "{0} is a required field",
+	"email":        "{0} must be a valid email address",
+	"url":          "{0} must be a valid URL",
+	"oneof":        "{0} must be one of [{1}]",
+	"unique":       "{0} must contain unique values",
+	"nefield":      "{0} cannot be equal to {1}",
+	"len-items":    "{0} must contain exactly {1} items",
+	"len-string":   "{0} must be exactly {1} characters long",
+	"len-number":   "{0} must be equal to {1}",
+	"min-items":    "{0} must contain at least {1} items",
+	"min-string":   "{0} must be at least {1} characters long",
+	"min-number":   "{0} must be {1} or greater",
+	"max-items":    "{0} must contain at most {1} items",
+	"max-string":   "{0} must be at most {1} characters long",
+	"max-number":   "{0} must be {1} or less",
+	"unknown-rule": "{0} failed on the '{1}' rule",
+}
+
+var (
+	english    = en.New()
+	translator = ut.New(english, english)
+	validate   = validator.New()
+)
+
+func init() {
+	validate.RegisterTagNameFunc(jsonFieldName)
+
+	trans, _ := translator.GetTranslator(english.Locale())
+	for key, text := range englishMessages {
+		if err := trans.Add(key, text, false); err != nil {
+			panic(fmt.Sprintf("failed to register validation message %q: %v", key, err))
+		}
+	}
+}
+
+// Validator returns the validator shared by every DTO package.
+// Fields are reported by their JSON names.
+func Validator() *validator.Validate {
+	return validate
+}
+
+// jsonFieldName returns the JSON name of a struct field, or its Go name when it has none.
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// FieldErrors converts the validation errors in err into field errors with English messages.
+// It returns nil if err is not a validation error.
+func FieldErrors(err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	trans, _ := translator.GetTranslator(english.Locale())
+	fieldErrs := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fieldErrs = append(fieldErrs, FieldError{
+			Field:   fieldPath(fe.Namespace()),
+			Rule:    fe.Tag(),
+			Message: translate(trans, fe),
+		})
+	}
+	return fieldErrs
+}
+
+// translate renders the message for a failed rule, falling back to a generic one for rules without a template.
+func translate(trans ut.Translator, fe validator.FieldError) string {
+	param := strings.ReplaceAll(fe.Param(), " ", ", ")
+	if fe.Tag() == "nefield" {
+		// The compared field is named by its Go name; the DTOs give it the same JSON name in lower case.
+		param = strings.ToLower(param)
+	}
+	for _, key := range []string{fe.Tag(), fe.Tag() + "-" + kindSuffix(fe.Kind())} {
+		if message, err := trans.T(key, fe.Field(), param); err == nil {
+			return message
+		}
+	}
+	message, _ := trans.T("unknown-rule", fe.Field(), fe.Tag())
+	return message
+}
+
+// kindSuffix groups field kinds the way the length rules treat them.
+func kindSuffix(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	case reflect.String:
+		return "string"
+	default:
+		return "number"
+	}
+}
+
+// fieldPath strips the struct name from a namespace such as "CreateFriend.friends[0]".
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+)
+
+// GraphRepository defines the interface for streaming the social graph out of the database.
+type GraphRepository interface {
+	StreamNodes(ctx context.Context, filter *graph.Filter, fn func(*graph.Node) error) error
+	StreamEdges(ctx context.Context, filter *graph.Filter, fn func(*graph.Edge) error) error
+}
+
+// graphRepositoryImpl is the implementation of the GraphRepository interface.
+type graphRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewGraphRepository creates a new instance of GraphRepository.
+func NewGraphRepository(db *sql.DB) GraphRepository {
+	return &graphRepositoryImpl{db: db}
+}
+
+// egoNetworkCTE walks relationships in both directions from the user $1 up to $2 hops. The anchor
+// is cast to uuid, the type of the relationship columns the recursive part yields and compares.
+const egoNetworkCTE = `
+    WITH RECURSIVE ego(id, depth) AS (
+        SELECT $1::uuid, 0
+        UNION
+        SELECT CASE
+            WHEN r.requestor_id = e.id THEN r.target_id
+            ELSE r.requestor_id
+        END, e.depth + 1
+        FROM ego e
+        JOIN relationships r
+            ON r.requestor_id = e.id OR r.target_id = e.id
+        WHERE e.depth < $2
+    )`
+
+// StreamNodes calls fn for every user in the graph, or in the ego network described by filter.
+// Rows are read one at a time so the full users table is never held in memory.
+func (repo *graphRepositoryImpl) StreamNodes(ctx context.Context, filter *graph.Filter, fn func(*graph.Node) error) error {
+	query := `SELECT u.id, u.email, u.created_at FROM users u ORDER BY u.email`
+	var args []interface{}
+	if filter != nil {
+		query = egoNetworkCTE + `
+    SELECT u.id, u.email, u.created_at
+    FROM users u
+    WHERE u.id IN (SELECT id FROM ego)
+    ORDER BY u.email`
+		args = []interface{}{filter.UserID, filter.Depth}
+	}
+
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query nodes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var node graph.Node
+		if err := rows.Scan(&node.ID, &node.Email, &node.CreatedAt); err != nil {
+			return fmt.Errorf("failed to scan node: %w", err)
+		}
+		if err := fn(&node); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row iteration error: %w", err)
+	}
+	return nil
+}
+
+// StreamEdges calls fn for every relationship in the graph, or for every relationship whose
+// endpoints both lie in the ego network described by filter.
+func (repo *graphRepositoryImpl) StreamEdges(ctx context.Context, filter *graph.Filter, fn func(*graph.Edge) error) error {
+	query := `
+    SELECT r.id, ru.email, tu.email, r.relationship_type, r.created_at
+    FROM relationships r
+    JOIN users ru ON ru.id = r.requestor_id
+    JOIN users tu ON tu.id = r.target_id
+    ORDER BY r.created_at, r.id`
+	var args []interface{}
+	if filter != nil {
+		query = egoNetworkCTE + `
+    SELECT r.id, ru.email, tu.email, r.relationship_type, r.created_at
+    FROM relationships r
+    JOIN users ru ON ru.id = r.requestor_id
+    JOIN users tu ON tu.id = r.target_id
+    WHERE r.requestor_id IN (SELECT id FROM ego)
+      AND r.target_id IN (SELECT id FROM ego)
+    ORDER BY r.created_at, r.id`
+		args = []interface{}{filter.UserID, filter.Depth}
+	}
+
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query edges: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var edge graph.Edge
+		if err := rows.Scan(&edge.ID, &edge.Source, &edge.Target, &edge.RelationshipType, &edge.CreatedAt); err != nil {
+			return fmt.Errorf("failed to scan edge: %w", err)
+		}
+		if err := fn(&edge); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row iteration error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStreamNodes tests that every user row is handed to the callback in order.
+func TestStreamNodes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewGraphRepository(db)
+	createdAt := time.Now()
+
+	mock.ExpectQuery(`SELECT u\.id, u\.email, u\.created_at FROM users u ORDER BY u\.email`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "created_at"}).
+			AddRow("1", "alice@example.com", createdAt).
+			AddRow("2", "bob@example.com", createdAt))
+
+	var emails []string
+	err = repo.StreamNodes(context.Background(), nil, func(node *graph.Node) error {
+		emails = append(emails, node.Email)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice@example.com", "bob@example.com"}, emails)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestStreamNodes_EgoNetwork tests that a filter runs the recursive ego network query.
+func TestStreamNodes_EgoNetwork(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewGraphRepository(db)
+
+	mock.ExpectQuery(`WITH RECURSIVE ego\(id, depth\) AS \(\s+SELECT \$1::uuid, 0 .* WHERE u\.id IN \(SELECT id FROM ego\)`).
+		WithArgs("1", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "created_at"}).
+			AddRow("1", "alice@example.com", time.Now()))
+
+	count := 0
+	err = repo.StreamNodes(context.Background(), &graph.Filter{UserID: "1", Depth: 2}, func(node *graph.Node) error {
+		count++
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestStreamEdges tests that relationships are streamed with their endpoint emails.
+func TestStreamEdges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewGraphRepository(db)
+
+	mock.ExpectQuery(`SELECT r\.id, ru\.email, tu\.email, r\.relationship_type, r\.created_at\s+FROM relationships r`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "source", "target", "relationship_type", "created_at"}).
+			AddRow("r1", "alice@example.com", "bob@example.com", "Friend", time.Now()))
+
+	var edges []*graph.Edge
+	err = repo.StreamEdges(context.Background(), nil, func(edge *graph.Edge) error {
+		edges = append(edges, edge)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	require.Len(t, edges, 1)
+	assert.Equal(t, "alice@example.com", edges[0].Source)
+	assert.Equal(t, "bob@example.com", edges[0].Target)
+	assert.Equal(t, "Friend", edges[0].RelationshipType)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestStreamEdges_CallbackError tests that streaming stops at the first callback error.
+func TestStreamEdges_CallbackError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewGraphRepository(db)
+
+	mock.ExpectQuery(`SELECT r\.id`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "source", "target", "relationship_type", "created_at"}).
+			AddRow("r1", "alice@example.com", "bob@example.com", "Friend", time.Now()).
+			AddRow("r2", "bob@example.com", "carol@example.com", "Block", time.Now()))
+
+	calls := 0
+	err = repo.StreamEdges(context.Background(), nil, func(edge *graph.Edge) error {
+		calls++
+		return errors.New("client went away")
+	})
+
+	assert.EqualError(t, err, "client went away")
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,38 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/koeylp/friends-management/cmd/internal/infra/tracing"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+)
+
+// instrumentedGraphRepository records every call to the wrapped repository as a span and in the
+// repository duration and error metrics.
+// A streamed call is timed until the last row has been handed to fn, so a slow reader shows up in its duration.
+type instrumentedGraphRepository struct {
+	next     GraphRepository
+	observer *metrics.RepositoryObserver
+}
+
+// NewInstrumentedGraphRepository wraps repo so each call is a span named "GraphRepository.<method>"
+// and is recorded in m under the "graph" repository.
+func NewInstrumentedGraphRepository(repo GraphRepository, m *metrics.Metrics) GraphRepository {
+	return &instrumentedGraphRepository{next: repo, observer: m.Repository("graph")}
+}
+
+func (r *instrumentedGraphRepository) StreamNodes(ctx context.Context, filter *graph.Filter, fn func(*graph.Node) error) (err error) {
+	ctx, span := tracing.Start(ctx, "GraphRepository.StreamNodes")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("StreamNodes", time.Now(), &err)
+	return r.next.StreamNodes(ctx, filter, fn)
+}
+
+func (r *instrumentedGraphRepository) StreamEdges(ctx context.Context, filter *graph.Filter, fn func(*graph.Edge) error) (err error) {
+	ctx, span := tracing.Start(ctx, "GraphRepository.StreamEdges")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("StreamEdges", time.Now(), &err)
+	return r.next.StreamEdges(ctx, filter, fn)
+}
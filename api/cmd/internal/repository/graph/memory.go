@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/memory"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+)
+
+// memoryGraphRepository implements the GraphRepository interface over an in-memory store.
+type memoryGraphRepository struct {
+	store *memory.Store
+}
+
+// NewMemoryGraphRepository creates a GraphRepository streaming the users and relationships in store.
+func NewMemoryGraphRepository(store *memory.Store) GraphRepository {
+	return &memoryGraphRepository{store: store}
+}
+
+// StreamNodes calls fn for every user in the graph, or in the ego network described by filter,
+// in email order. The nodes are copied out of the store first, so fn does not hold it up.
+func (repo *memoryGraphRepository) StreamNodes(ctx context.Context, filter *graph.Filter, fn func(*graph.Node) error) error {
+	var nodes []*graph.Node
+	err := repo.store.View(ctx, func(tables *memory.Tables) error {
+		ego := egoNetwork(tables, filter)
+		for _, u := range tables.Users {
+			if ego == nil || ego[u.ID] {
+				nodes = append(nodes, &graph.Node{ID: u.ID, Email: u.Email, CreatedAt: u.CreatedAt})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(nodes, func(a, b *graph.Node) int { return cmp.Compare(a.Email, b.Email) })
+	for _, node := range nodes {
+		if err := fn(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StreamEdges calls fn for every relationship in the graph, or for every relationship whose
+// endpoints both lie in the ego network described by filter, in creation order.
+func (repo *memoryGraphRepository) StreamEdges(ctx context.Context, filter *graph.Filter, fn func(*graph.Edge) error) error {
+	var edges []*graph.Edge
+	err := repo.store.View(ctx, func(tables *memory.Tables) error {
+		ego := egoNetwork(tables, filter)
+		for _, r := range tables.Relationships {
+			if ego == nil || (ego[r.RequestorID] && ego[r.TargetID]) {
+				edges = append(edges, &graph.Edge{
+					ID:               r.ID,
+					Source:           tables.UserByID(r.RequestorID).Email,
+					Target:           tables.UserByID(r.TargetID).Email,
+					RelationshipType: r.RelationshipType,
+					CreatedAt:        r.CreatedAt,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slices.SortStableFunc(edges, func(a, b *graph.Edge) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	for _, edge := range edges {
+		if err := fn(edge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// egoNetwork returns the IDs of the users within filter.Depth relationships of any type and
+// direction from filter.UserID, or nil without a filter.
+func egoNetwork(tables *memory.Tables, filter *graph.Filter) map[string]bool {
+	if filter == nil {
+		return nil
+	}
+	ego := map[string]bool{filter.UserID: true}
+	frontier := []string{filter.UserID}
+	for depth := 0; depth < filter.Depth && len(frontier) > 0; depth++ {
+		var next []string
+		for _, id := range frontier {
+			for _, r := range tables.Relationships {
+				other := r.TargetID
+				if r.TargetID == id {
+					other = r.RequestorID
+				} else if r.RequestorID != id {
+					continue
+				}
+				if !ego[other] {
+					ego[other] = true
+					next = append(next, other)
+				}
+			}
+		}
+		frontier = next
+	}
+	return ego
+}
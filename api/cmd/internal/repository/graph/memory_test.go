@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/memory"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMemoryGraph stores a chain of friends carol - alice - bob - dave, with eve apart.
+func newMemoryGraph(t *testing.T) GraphRepository {
+	store := memory.NewStore()
+	now := time.Now()
+	err := store.Update(context.Background(), func(tables *memory.Tables) error {
+		for _, u := range [][2]string{{"1", "alice"}, {"2", "bob"}, {"3", "carol"}, {"4", "dave"}, {"5", "eve"}} {
+			tables.Users = append(tables.Users, &memory.User{ID: u[0], Email: u[1] + "@example.com", CreatedAt: now})
+		}
+		for i, r := range [][2]string{{"2", "4"}, {"1", "2"}, {"3", "1"}} {
+			tables.Relationships = append(tables.Relationships, &memory.Relationship{
+				ID: string(rune('a' + i)), RequestorID: r[0], TargetID: r[1], RelationshipType: "Friend",
+				CreatedAt: now.Add(time.Duration(i) * time.Second),
+			})
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return NewMemoryGraphRepository(store)
+}
+
+func streamEmails(t *testing.T, repo GraphRepository, filter *graph.Filter) []string {
+	var emails []string
+	err := repo.StreamNodes(context.Background(), filter, func(node *graph.Node) error {
+		emails = append(emails, node.Email)
+		return nil
+	})
+	require.NoError(t, err)
+	return emails
+}
+
+// Test that every user is streamed in email order, or those of the ego network in reach.
+func TestMemoryGraphRepository_StreamNodes(t *testing.T) {
+	repo := newMemoryGraph(t)
+
+	assert.Equal(t, []string{"alice@example.com", "bob@example.com", "carol@example.com", "dave@example.com", "eve@example.com"},
+		streamEmails(t, repo, nil))
+	assert.Equal(t, []string{"alice@example.com", "bob@example.com", "carol@example.com"},
+		streamEmails(t, repo, &graph.Filter{UserID: "1", Depth: 1}))
+	assert.Equal(t, []string{"alice@example.com", "bob@example.com", "carol@example.com", "dave@example.com"},
+		streamEmails(t, repo, &graph.Filter{UserID: "3", Depth: 3}))
+	assert.Equal(t, []string{"eve@example.com"}, streamEmails(t, repo, &graph.Filter{UserID: "5", Depth: 5}))
+}
+
+// Test that edges are streamed in creation order, keeping those within the ego network.
+func TestMemoryGraphRepository_StreamEdges(t *testing.T) {
+	repo := newMemoryGraph(t)
+
+	var edges []string
+	err := repo.StreamEdges(context.Background(), nil, func(edge *graph.Edge) error {
+		edges = append(edges, edge.Source+" "+edge.Target)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob@example.com dave@example.com", "alice@example.com bob@example.com", "carol@example.com alice@example.com"}, edges)
+
+	edges = nil
+	err = repo.StreamEdges(context.Background(), &graph.Filter{UserID: "2", Depth: 1}, func(edge *graph.Edge) error {
+		edges = append(edges, edge.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, edges)
+}
+
+// Test that an error from the callback stops the stream and is returned.
+func TestMemoryGraphRepository_CallbackError(t *testing.T) {
+	repo := newMemoryGraph(t)
+
+	count := 0
+	err := repo.StreamNodes(context.Background(), nil, func(node *graph.Node) error {
+		count++
+		return assert.AnError
+	})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, count)
+}
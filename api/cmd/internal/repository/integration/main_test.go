@@ -0,0 +1,108 @@
+//go:build integration
+
+// Package integration runs the PostgreSQL repositories end to end against a real server, so the
+// queries sqlmock only matches as text are planned and executed. It is built with the integration
+// tag:
+//
+//	go test -tags integration ./cmd/internal/repository/integration
+//
+// The server is the one at TEST_DB_DSN when it is set. Otherwise an embedded PostgreSQL is started
+// on port 54329 from the binaries at POSTGRES_BINARIES, e.g. /usr/lib/postgresql/15, or from
+// binaries downloaded once into the embedded-postgres cache when it is not set.
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"testing"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/koeylp/friends-management/cmd/data"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/migrate"
+	_ "github.com/koeylp/friends-management/cmd/internal/infra/database/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+// embeddedPort is the port of the embedded server, away from a PostgreSQL on the default port.
+const embeddedPort = 54329
+
+// db is the migrated database every test runs against.
+var db *sql.DB
+
+func TestMain(m *testing.M) {
+	code, err := run(m)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(code)
+}
+
+// run starts the server when there is none to connect to, applies the migrations and runs the tests.
+func run(m *testing.M) (int, error) {
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		runtime, err := os.MkdirTemp("", "friends-integration-")
+		if err != nil {
+			return 0, err
+		}
+		defer os.RemoveAll(runtime)
+
+		cfg := embeddedpostgres.DefaultConfig().
+			Version(embeddedpostgres.V15).
+			Port(embeddedPort).
+			Database("friends_test").
+			RuntimePath(runtime).
+			Logger(os.Stderr)
+		if binaries := os.Getenv("POSTGRES_BINARIES"); binaries != "" {
+			cfg = cfg.BinariesPath(binaries)
+		}
+		server := embeddedpostgres.NewDatabase(cfg)
+		if err := server.Start(); err != nil {
+			return 0, fmt.Errorf("failed to start embedded postgres: %w", err)
+		}
+		defer server.Stop()
+		dsn = cfg.GetConnectionURL() + "?sslmode=disable"
+	}
+
+	var err error
+	db, err = sql.Open("pgx", dsn)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	migrations, err := fs.Sub(data.Migrations, "migrations")
+	if err != nil {
+		return 0, err
+	}
+	migrator, err := migrate.NewMigrator(db, migrations)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		return 0, fmt.Errorf("failed to migrate: %w", err)
+	}
+
+	return m.Run(), nil
+}
+
+// reset empties every table, so a test starts from an empty database.
+func reset(t *testing.T) {
+	t.Helper()
+	_, err := db.Exec(`TRUNCATE users, relationships, relationship_events, webhook_subscriptions RESTART IDENTITY CASCADE`)
+	require.NoError(t, err)
+}
+
+// seed empties every table and inserts the sample data of cmd/data/seed.
+func seed(t *testing.T) {
+	t.Helper()
+	reset(t)
+	seeds, err := fs.Sub(data.Seeds, "seed")
+	require.NoError(t, err)
+	_, err = migrate.Seed(context.Background(), db, seeds)
+	require.NoError(t, err)
+}
@@ -0,0 +1,45 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+
+	"github.com/koeylp/friends-management/cmd/data"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that a database whose tables exist but whose migrations were never recorded, as one
+// created before the schema was migrated, is adopted by Up without losing its data.
+func TestMigrate_AdoptsExistingSchema(t *testing.T) {
+	seed(t)
+	ctx := context.Background()
+
+	var users int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`).Scan(&users))
+	require.NotZero(t, users)
+
+	_, err := db.ExecContext(ctx, `DROP TABLE schema_migrations`)
+	require.NoError(t, err)
+
+	migrations, err := fs.Sub(data.Migrations, "migrations")
+	require.NoError(t, err)
+	migrator, err := migrate.NewMigrator(db, migrations)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000001_db_init", "000002_relationship_events", "000003_webhooks", "000004_published_sinks"}, applied)
+
+	pending, err := migrator.Pending(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	var adopted int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`).Scan(&adopted))
+	assert.Equal(t, users, adopted)
+}
@@ -0,0 +1,276 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	graphCtrl "github.com/koeylp/friends-management/cmd/internal/controller/graph"
+	"github.com/koeylp/friends-management/cmd/internal/infra/cache"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/postgres"
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/graph"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+	graphRepo "github.com/koeylp/friends-management/cmd/internal/repository/graph"
+	outboxRepo "github.com/koeylp/friends-management/cmd/internal/repository/outbox"
+	relationshipRepo "github.com/koeylp/friends-management/cmd/internal/repository/relationship"
+	"github.com/koeylp/friends-management/cmd/internal/repository/repotest"
+	userRepo "github.com/koeylp/friends-management/cmd/internal/repository/user"
+	webhookRepo "github.com/koeylp/friends-management/cmd/internal/repository/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that the repositories fulfil the contract on their own, and wrapped as the application
+// wires them: routed, retried and, for relationships, cached.
+func TestContract(t *testing.T) {
+	t.Run("Postgres", func(t *testing.T) {
+		repotest.Run(t, func(t *testing.T) *repotest.Repositories {
+			reset(t)
+			return &repotest.Repositories{
+				Users:         userRepo.NewUserRepository(db),
+				Relationships: relationshipRepo.NewRelationshipRepository(db),
+				Outbox:        outboxRepo.NewOutboxRepository(db),
+			}
+		})
+	})
+
+	t.Run("Decorated", func(t *testing.T) {
+		cfg := &config.DBConfig{ReadAttempts: 3, ReadBackoff: time.Millisecond}
+		dbs := postgres.NewReadRouter(db, nil)
+		repotest.Run(t, func(t *testing.T) *repotest.Repositories {
+			reset(t)
+			users := userRepo.NewRetryingUserRepository(userRepo.NewRoutedUserRepository(dbs), cfg)
+			relationships := relationshipRepo.NewRetryingRelationshipRepository(relationshipRepo.NewRoutedRelationshipRepository(dbs), cfg)
+			return &repotest.Repositories{
+				Users: users,
+				Relationships: relationshipRepo.NewCachedRelationshipRepository(
+					relationships, users, cache.NewLRU(100), &config.CacheConfig{TTL: time.Minute}, metrics.New()),
+				Outbox: outboxRepo.NewOutboxRepository(db),
+			}
+		})
+	})
+}
+
+// Test that the seeded users are found by email with the IDs they were seeded with.
+func TestSeed(t *testing.T) {
+	seed(t)
+	ctx := context.Background()
+	repo := userRepo.NewUserRepository(db)
+
+	john, err := repo.GetUserByEmail(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "676330d0-71c8-4f9d-ac58-e83f9808241c", john.ID)
+	assert.Equal(t, time.Date(2024, 10, 28, 2, 12, 36, 121000000, time.UTC), john.CreatedAt.UTC())
+
+	err = repo.CreateUser(ctx, &user.CreateUser{Email: "phoebe@example.com"})
+	assert.ErrorIs(t, err, domain.ErrUserExists)
+
+	var emails []string
+	err = graphRepo.NewGraphRepository(db).StreamNodes(ctx, nil, func(node *graph.Node) error {
+		emails = append(emails, node.Email)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"alex@example.com", "doe@example.com", "joey@example.com", "john@example.com",
+		"monica@example.com", "peter@example.com", "phill@example.com", "phoebe@example.com",
+		"rachel@example.com", "ross@example.com", "shelby@example.com",
+	}, emails)
+}
+
+// createUsers stores a user for each email and returns them in the same order.
+func createUsers(t *testing.T, emails ...string) []*user.User {
+	t.Helper()
+	ctx := context.Background()
+	repo := userRepo.NewUserRepository(db)
+	users := make([]*user.User, len(emails))
+	for i, email := range emails {
+		require.NoError(t, repo.CreateUser(ctx, &user.CreateUser{Email: email}))
+		found, err := repo.GetUserByEmail(ctx, email)
+		require.NoError(t, err)
+		users[i] = found
+	}
+	return users
+}
+
+// Test that appended events are claimed with their data and the sinks that accepted them, and
+// that failed ones are given up after their last attempt.
+func TestOutbox(t *testing.T) {
+	reset(t)
+	ctx := context.Background()
+	repo := outboxRepo.NewOutboxRepository(db)
+
+	data := event.RelationshipData{RelationshipID: uuid.New().String(), Requestor: "andy@example.com", Target: "john@example.com"}
+	require.NoError(t, repo.Append(ctx, event.FriendCreated, data))
+
+	events, err := repo.ClaimPending(ctx, 10, 2, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, event.FriendCreated, events[0].Type)
+	assert.Equal(t, 1, events[0].Attempts)
+	var claimed event.RelationshipData
+	require.NoError(t, json.Unmarshal(events[0].Data, &claimed))
+	assert.Equal(t, data, claimed)
+
+	assert.Empty(t, events[0].PublishedSinks)
+
+	require.NoError(t, repo.MarkFailed(ctx, events[0].ID, assert.AnError, time.Now().Add(-time.Second), []string{"stream"}))
+	events, err = repo.ClaimPending(ctx, 10, 2, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, 2, events[0].Attempts)
+	assert.Equal(t, []string{"stream"}, events[0].PublishedSinks)
+
+	require.NoError(t, repo.MarkFailed(ctx, events[0].ID, assert.AnError, time.Now().Add(-time.Second), []string{"stream"}))
+	events, err = repo.ClaimPending(ctx, 10, 2, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+// streamGraph returns the emails of the nodes and the endpoints of the edges in the graph,
+// or in the ego network described by filter.
+func streamGraph(t *testing.T, filter *graph.Filter) ([]string, []string) {
+	t.Helper()
+	ctx := context.Background()
+	repo := graphRepo.NewGraphRepository(db)
+
+	var nodes, edges []string
+	require.NoError(t, repo.StreamNodes(ctx, filter, func(node *graph.Node) error {
+		nodes = append(nodes, node.Email)
+		return nil
+	}))
+	require.NoError(t, repo.StreamEdges(ctx, filter, func(edge *graph.Edge) error {
+		edges = append(edges, edge.Source+" "+edge.Target)
+		return nil
+	}))
+	return nodes, edges
+}
+
+// Test that the graph is streamed whole, or as the ego network within reach of a user.
+func TestGraph(t *testing.T) {
+	reset(t)
+	ctx := context.Background()
+	users := createUsers(t, "alice@example.com", "bob@example.com", "carol@example.com", "dave@example.com", "eve@example.com")
+	alice, bob, carol, dave, eve := users[0], users[1], users[2], users[3], users[4]
+
+	relationships := relationshipRepo.NewRelationshipRepository(db)
+	require.NoError(t, relationships.CreateFriend(ctx, bob.ID, dave.ID))
+	require.NoError(t, relationships.CreateFriend(ctx, alice.ID, bob.ID))
+	require.NoError(t, relationships.Subscribe(ctx, carol.ID, alice.ID))
+
+	nodes, edges := streamGraph(t, nil)
+	assert.Equal(t, []string{alice.Email, bob.Email, carol.Email, dave.Email, eve.Email}, nodes)
+	assert.Equal(t, []string{bob.Email + " " + dave.Email, alice.Email + " " + bob.Email, carol.Email + " " + alice.Email}, edges)
+
+	nodes, edges = streamGraph(t, &graph.Filter{UserID: alice.ID, Depth: 1})
+	assert.Equal(t, []string{alice.Email, bob.Email, carol.Email}, nodes)
+	assert.Equal(t, []string{alice.Email + " " + bob.Email, carol.Email + " " + alice.Email}, edges)
+
+	nodes, edges = streamGraph(t, &graph.Filter{UserID: carol.ID, Depth: 3})
+	assert.Equal(t, []string{alice.Email, bob.Email, carol.Email, dave.Email}, nodes)
+	assert.Len(t, edges, 3)
+
+	nodes, edges = streamGraph(t, &graph.Filter{UserID: eve.ID, Depth: 5})
+	assert.Equal(t, []string{eve.Email}, nodes)
+	assert.Empty(t, edges)
+}
+
+// Test that an export filtered by email and depth, as friendsctl export -email -depth runs it,
+// walks the ego network on the server.
+func TestGraphExport(t *testing.T) {
+	reset(t)
+	ctx := context.Background()
+	users := createUsers(t, "alice@example.com", "bob@example.com", "carol@example.com")
+	alice, bob := users[0], users[1]
+	require.NoError(t, relationshipRepo.NewRelationshipRepository(db).CreateFriend(ctx, alice.ID, bob.ID))
+
+	ctrl := graphCtrl.NewGraphController(graphRepo.NewGraphRepository(db), userRepo.NewUserRepository(db))
+	var out bytes.Buffer
+	err := ctrl.Export(ctx, &graph.ExportRequest{Format: graph.FormatCSV, Email: alice.Email, Depth: 1}, &out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), alice.Email)
+	assert.Contains(t, out.String(), bob.Email)
+	assert.NotContains(t, out.String(), "carol@example.com")
+}
+
+// Test that subscriptions are served without their secret until they are deactivated, and that
+// deliveries are enqueued once, claimed, recorded and failed with their subscription.
+func TestWebhooks(t *testing.T) {
+	reset(t)
+	ctx := context.Background()
+	repo := webhookRepo.NewWebhookRepository(db)
+
+	outbox := outboxRepo.NewOutboxRepository(db)
+	require.NoError(t, outbox.Append(ctx, event.BlockCreated, event.RelationshipData{Requestor: "a@example.com", Target: "b@example.com"}))
+	events, err := outbox.ClaimPending(ctx, 10, 5, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	eventID := events[0].ID
+
+	subscriptionID := uuid.New().String()
+	require.NoError(t, repo.CreateSubscription(ctx, &webhook.Subscription{
+		ID: subscriptionID, URL: "https://example.com/hook", Events: []string{webhook.AllEvents}, Secret: "s3cr3t", Active: true, CreatedAt: time.Now(),
+	}))
+
+	subscription, err := repo.GetSubscription(ctx, subscriptionID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook", subscription.URL)
+	assert.Equal(t, []string{webhook.AllEvents}, subscription.Events)
+	assert.Empty(t, subscription.Secret)
+	subscriptions, err := repo.ListSubscriptions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+
+	require.NoError(t, repo.EnqueueDeliveries(ctx, eventID, []string{subscriptionID}))
+	require.NoError(t, repo.EnqueueDeliveries(ctx, eventID, []string{subscriptionID}))
+	assert.Error(t, repo.EnqueueDeliveries(ctx, eventID, []string{uuid.New().String()}))
+
+	deliveries, err := repo.ClaimDueDeliveries(ctx, 20, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, "s3cr3t", deliveries[0].Secret)
+	assert.Equal(t, eventID, deliveries[0].Event.ID)
+	assert.Equal(t, event.BlockCreated, deliveries[0].Event.Type)
+
+	claimed, err := repo.ClaimDueDeliveries(ctx, 20, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	statusCode := 503
+	attempt := &webhook.DeliveryAttempt{Attempt: 1, StatusCode: &statusCode, Error: "unexpected status 503", DurationMs: 12, AttemptedAt: time.Now()}
+	require.NoError(t, repo.RecordAttempt(ctx, deliveries[0].ID, attempt, webhook.StatusPending, time.Now().Add(-time.Second)))
+	assert.Error(t, repo.RecordAttempt(ctx, uuid.New().String(), attempt, webhook.StatusPending, time.Now()))
+
+	listed, err := repo.ListDeliveries(ctx, subscriptionID, 50)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, event.BlockCreated, listed[0].EventType)
+	assert.Equal(t, webhook.StatusPending, listed[0].Status)
+	assert.Equal(t, 503, *listed[0].LastStatusCode)
+	require.Len(t, listed[0].History, 1)
+	assert.Equal(t, "unexpected status 503", listed[0].History[0].Error)
+
+	// Deactivating the subscription fails its pending deliveries.
+	require.NoError(t, repo.DeactivateSubscription(ctx, subscriptionID))
+	_, err = repo.GetSubscription(ctx, subscriptionID)
+	assert.ErrorIs(t, err, domain.ErrWebhookNotFound)
+	assert.ErrorIs(t, repo.DeactivateSubscription(ctx, subscriptionID), domain.ErrWebhookNotFound)
+
+	claimed, err = repo.ClaimDueDeliveries(ctx, 20, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+	listed, err = repo.ListDeliveries(ctx, subscriptionID, 50)
+	require.NoError(t, err)
+	assert.Equal(t, webhook.StatusFailed, listed[0].Status)
+}
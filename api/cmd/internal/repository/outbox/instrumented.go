@@ -0,0 +1,51 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/koeylp/friends-management/cmd/internal/infra/tracing"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+)
+
+// instrumentedOutboxRepository records every call to the wrapped repository as a span and in the
+// repository duration and error metrics.
+type instrumentedOutboxRepository struct {
+	next     OutboxRepository
+	observer *metrics.RepositoryObserver
+}
+
+// NewInstrumentedOutboxRepository wraps repo so each call is a span named "OutboxRepository.<method>"
+// and is recorded in m under the "outbox" repository.
+func NewInstrumentedOutboxRepository(repo OutboxRepository, m *metrics.Metrics) OutboxRepository {
+	return &instrumentedOutboxRepository{next: repo, observer: m.Repository("outbox")}
+}
+
+func (r *instrumentedOutboxRepository) Append(ctx context.Context, eventType string, data interface{}) (err error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.Append")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("Append", time.Now(), &err)
+	return r.next.Append(ctx, eventType, data)
+}
+
+func (r *instrumentedOutboxRepository) ClaimPending(ctx context.Context, limit, maxAttempts int, lease time.Duration) (_ []*event.Event, err error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.ClaimPending")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("ClaimPending", time.Now(), &err)
+	return r.next.ClaimPending(ctx, limit, maxAttempts, lease)
+}
+
+func (r *instrumentedOutboxRepository) MarkPublished(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.MarkPublished")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("MarkPublished", time.Now(), &err)
+	return r.next.MarkPublished(ctx, id)
+}
+
+func (r *instrumentedOutboxRepository) MarkFailed(ctx context.Context, id int64, cause error, retryAt time.Time, publishedSinks []string) (err error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.MarkFailed")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("MarkFailed", time.Now(), &err)
+	return r.next.MarkFailed(ctx, id, cause, retryAt, publishedSinks)
+}
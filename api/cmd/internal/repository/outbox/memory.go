@@ -0,0 +1,81 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/memory"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+)
+
+// memoryOutboxRepository implements the OutboxRepository interface over an in-memory store.
+type memoryOutboxRepository struct {
+	store *memory.Store
+}
+
+// NewMemoryOutboxRepository creates an OutboxRepository over the events recorded in store,
+// including those of the relationship changes made by its relationship repository.
+func NewMemoryOutboxRepository(store *memory.Store) OutboxRepository {
+	return &memoryOutboxRepository{store: store}
+}
+
+// Append records an event that is not tied to a relationship mutation, such as a posted update.
+func (repo *memoryOutboxRepository) Append(ctx context.Context, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	return repo.store.Update(ctx, func(tables *memory.Tables) error {
+		tables.AppendEvent(eventType, payload, time.Now())
+		return nil
+	})
+}
+
+// ClaimPending leases up to limit unpublished events that are due and have not exhausted
+// maxAttempts, in the order they were recorded. See the PostgreSQL implementation for the lease.
+func (repo *memoryOutboxRepository) ClaimPending(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]*event.Event, error) {
+	events := make([]*event.Event, 0, limit)
+	err := repo.store.Update(ctx, func(tables *memory.Tables) error {
+		now := time.Now()
+		for _, e := range tables.Events {
+			if len(events) == limit {
+				break
+			}
+			if !e.PublishedAt.IsZero() || e.NextAttemptAt.After(now) || e.Attempts >= maxAttempts {
+				continue
+			}
+			e.Attempts++
+			e.NextAttemptAt = now.Add(lease)
+			events = append(events, &event.Event{ID: e.ID, Type: e.EventType, Data: e.Payload, CreatedAt: e.CreatedAt, Attempts: e.Attempts, PublishedSinks: slices.Clone(e.PublishedSinks)})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim events: %w", err)
+	}
+	return events, nil
+}
+
+// MarkPublished records that an event has been delivered to every sink.
+func (repo *memoryOutboxRepository) MarkPublished(ctx context.Context, id int64) error {
+	return repo.store.Update(ctx, func(tables *memory.Tables) error {
+		if e := tables.EventByID(id); e != nil {
+			e.PublishedAt, e.LastError = time.Now(), ""
+		}
+		return nil
+	})
+}
+
+// MarkFailed records a failed delivery attempt and schedules the next one. publishedSinks
+// names the sinks that have accepted the event so far, which the next attempt skips.
+func (repo *memoryOutboxRepository) MarkFailed(ctx context.Context, id int64, cause error, retryAt time.Time, publishedSinks []string) error {
+	return repo.store.Update(ctx, func(tables *memory.Tables) error {
+		if e := tables.EventByID(id); e != nil {
+			e.LastError, e.NextAttemptAt, e.PublishedSinks = cause.Error(), retryAt, slices.Clone(publishedSinks)
+		}
+		return nil
+	})
+}
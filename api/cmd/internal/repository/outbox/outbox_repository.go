@@ -0,0 +1,120 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+)
+
+// OutboxRepository defines the interface the dispatcher uses to drain the relationship event outbox.
+type OutboxRepository interface {
+	Append(ctx context.Context, eventType string, data interface{}) error
+	ClaimPending(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]*event.Event, error)
+	MarkPublished(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, cause error, retryAt time.Time, publishedSinks []string) error
+}
+
+// outboxRepositoryImpl is the implementation of the OutboxRepository interface.
+type outboxRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository creates a new instance of OutboxRepository.
+func NewOutboxRepository(db *sql.DB) OutboxRepository {
+	return &outboxRepositoryImpl{db: db}
+}
+
+// Append records an event that is not tied to a relationship mutation, such as a posted update.
+func (repo *outboxRepositoryImpl) Append(ctx context.Context, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	now := time.Now()
+	_, err = repo.db.ExecContext(ctx,
+		`INSERT INTO relationship_events (event_type, payload, next_attempt_at, created_at) VALUES ($1, $2, $3, $3)`,
+		eventType, payload, now)
+	if err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// ClaimPending leases up to limit unpublished events that are due and have not exhausted maxAttempts.
+// A claimed event is hidden from other dispatchers for the lease duration; if it is neither marked
+// published nor failed before the lease expires it becomes due again, which gives at-least-once delivery.
+func (repo *outboxRepositoryImpl) ClaimPending(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]*event.Event, error) {
+	query := `
+    UPDATE relationship_events
+    SET attempts = attempts + 1, next_attempt_at = $2
+    WHERE id IN (
+        SELECT id FROM relationship_events
+        WHERE published_at IS NULL AND next_attempt_at <= $1 AND attempts < $3
+        ORDER BY id
+        LIMIT $4
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING id, event_type, payload, created_at, attempts, published_sinks`
+
+	now := time.Now()
+	rows, err := repo.db.QueryContext(ctx, query, now, now.Add(lease), maxAttempts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*event.Event, 0, limit)
+	for rows.Next() {
+		var e event.Event
+		var publishedSinks []byte
+		if err := rows.Scan(&e.ID, &e.Type, &e.Data, &e.CreatedAt, &e.Attempts, &publishedSinks); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		if err := json.Unmarshal(publishedSinks, &e.PublishedSinks); err != nil {
+			return nil, fmt.Errorf("failed to decode published sinks of event %d: %w", e.ID, err)
+		}
+		events = append(events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	// RETURNING does not preserve the subquery order.
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
+}
+
+// MarkPublished records that an event has been delivered to every sink.
+func (repo *outboxRepositoryImpl) MarkPublished(ctx context.Context, id int64) error {
+	_, err := repo.db.ExecContext(ctx,
+		`UPDATE relationship_events SET published_at = $2, last_error = NULL WHERE id = $1`, id, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to mark event %d published: %w", id, err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery attempt and schedules the next one. publishedSinks
+// names the sinks that have accepted the event so far, which the next attempt skips.
+func (repo *outboxRepositoryImpl) MarkFailed(ctx context.Context, id int64, cause error, retryAt time.Time, publishedSinks []string) error {
+	if publishedSinks == nil {
+		publishedSinks = []string{}
+	}
+	sinks, err := json.Marshal(publishedSinks)
+	if err != nil {
+		return fmt.Errorf("failed to encode published sinks of event %d: %w", id, err)
+	}
+	_, err = repo.db.ExecContext(ctx,
+		`UPDATE relationship_events SET last_error = $2, next_attempt_at = $3, published_sinks = $4 WHERE id = $1`,
+		id, cause.Error(), retryAt, sinks)
+	if err != nil {
+		return fmt.Errorf("failed to mark event %d failed: %w", id, err)
+	}
+	return nil
+}
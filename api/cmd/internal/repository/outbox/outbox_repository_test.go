@@ -0,0 +1,95 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAppend tests that a standalone event is inserted with its JSON payload.
+func TestAppend(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOutboxRepository(db)
+
+	mock.ExpectExec(`INSERT INTO relationship_events \(event_type, payload, next_attempt_at, created_at\) VALUES \(\$1, \$2, \$3, \$3\)`).
+		WithArgs("update.posted", []byte(`{"sender":"a@example.com"}`), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.Append(context.Background(), "update.posted", map[string]string{"sender": "a@example.com"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestClaimPending tests that due events are leased and returned in id order.
+func TestClaimPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOutboxRepository(db)
+	createdAt := time.Now()
+
+	mock.ExpectQuery(`UPDATE relationship_events\s+SET attempts = attempts \+ 1.*FOR UPDATE SKIP LOCKED.*RETURNING id, event_type, payload, created_at, attempts, published_sinks`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 5, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_type", "payload", "created_at", "attempts", "published_sinks"}).
+			AddRow(2, "block.created", []byte(`{"requestor":"a@example.com","target":"b@example.com"}`), createdAt, 1, []byte(`[]`)).
+			AddRow(1, "friend.created", []byte(`{"requestor":"a@example.com","target":"c@example.com"}`), createdAt, 3, []byte(`["stream"]`)))
+
+	events, err := repo.ClaimPending(context.Background(), 10, 5, time.Minute)
+
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(1), events[0].ID)
+	assert.Equal(t, "friend.created", events[0].Type)
+	assert.Equal(t, 3, events[0].Attempts)
+	assert.Equal(t, []string{"stream"}, events[0].PublishedSinks)
+	assert.Empty(t, events[1].PublishedSinks)
+	assert.JSONEq(t, `{"requestor":"a@example.com","target":"b@example.com"}`, string(events[1].Data))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestMarkPublished tests that a delivered event is stamped as published.
+func TestMarkPublished(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOutboxRepository(db)
+
+	mock.ExpectExec(`UPDATE relationship_events SET published_at = \$2, last_error = NULL WHERE id = \$1`).
+		WithArgs(int64(7), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.MarkPublished(context.Background(), 7))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestMarkFailed tests that a failed attempt stores the error, the next retry time and the sinks
+// that accepted the event.
+func TestMarkFailed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOutboxRepository(db)
+	retryAt := time.Now().Add(time.Minute)
+
+	mock.ExpectExec(`UPDATE relationship_events SET last_error = \$2, next_attempt_at = \$3, published_sinks = \$4 WHERE id = \$1`).
+		WithArgs(int64(7), "connection refused", retryAt, []byte(`["stream"]`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE relationship_events SET last_error = \$2, next_attempt_at = \$3, published_sinks = \$4 WHERE id = \$1`).
+		WithArgs(int64(8), "connection refused", retryAt, []byte(`[]`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.MarkFailed(context.Background(), 7, errors.New("connection refused"), retryAt, []string{"stream"}))
+	assert.NoError(t, repo.MarkFailed(context.Background(), 8, errors.New("connection refused"), retryAt, nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
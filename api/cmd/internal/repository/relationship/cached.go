@@ -0,0 +1,234 @@
+package relationship
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koeylp/friends-management/cmd/internal/infra/cache"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database"
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	userRepo "github.com/koeylp/friends-management/cmd/internal/repository/user"
+)
+
+// cacheKeyPrefix namespaces the keys of the relationship cache, so it can share a backend.
+const cacheKeyPrefix = "relationship:"
+
+// cachedRelationshipRepository memoizes the friend lists, common friends and update recipients
+// read from the wrapped repository.
+//
+// Each of these reads only depends on the relationships of the users it is about, so entries
+// are invalidated per user: every user has a generation, a random token that is part of the
+// key of each entry about them, and a change to a relationship deletes the generations of
+// both its users. Their entries can then no longer be found and expire in time, including
+// entries written concurrently from reads made before the change. A generation that was
+// evicted or never set is replaced by a new one, so an eviction can not bring stale entries back.
+//
+// Misses are loaded from the primary: a read replica may not have caught up with a change yet,
+// and an entry filled from it would outlive the change's invalidation. Reads whose context asks
+// for the primary bypass the cache, so they see the latest writes.
+type cachedRelationshipRepository struct {
+	next    RelationshipRepository
+	users   userRepo.UserRepository
+	cache   cache.Cache
+	ttl     time.Duration
+	metrics *metrics.Metrics
+}
+
+// NewCachedRelationshipRepository wraps repo so its friend lists, common friends and update
+// recipients are kept in c for the configured TTL. Friend lists are keyed by user, whose ID is
+// looked up in users. Without a cache, repo is returned as is. Hits and misses are counted in m.
+func NewCachedRelationshipRepository(repo RelationshipRepository, users userRepo.UserRepository, c cache.Cache, cfg *config.CacheConfig, m *metrics.Metrics) RelationshipRepository {
+	if c == nil {
+		return repo
+	}
+	return &cachedRelationshipRepository{next: repo, users: users, cache: c, ttl: cfg.TTL, metrics: m}
+}
+
+func (r *cachedRelationshipRepository) CreateFriend(ctx context.Context, requestor_id, target_id string) error {
+	defer r.invalidate(ctx, requestor_id, target_id)
+	return r.next.CreateFriend(ctx, requestor_id, target_id)
+}
+
+func (r *cachedRelationshipRepository) DeleteFriend(ctx context.Context, requestor_id, target_id string) error {
+	defer r.invalidate(ctx, requestor_id, target_id)
+	return r.next.DeleteFriend(ctx, requestor_id, target_id)
+}
+
+func (r *cachedRelationshipRepository) CheckFriendshipExists(ctx context.Context, requestor_id, target_id string) (bool, error) {
+	return r.next.CheckFriendshipExists(ctx, requestor_id, target_id)
+}
+
+// GetFriends serves the friends of the user with email from the cache. Users that can not be
+// looked up are passed through, so the wrapped repository reports the error.
+func (r *cachedRelationshipRepository) GetFriends(ctx context.Context, email string) ([]string, error) {
+	userID, err := r.userID(ctx, email)
+	if err != nil {
+		return r.next.GetFriends(ctx, email)
+	}
+	return r.cached(ctx, "GetFriends", "friends", []string{userID}, func(ctx context.Context) ([]string, error) {
+		return r.next.GetFriends(ctx, email)
+	})
+}
+
+func (r *cachedRelationshipRepository) GetCommonFriends(ctx context.Context, users []*user.User) ([]string, error) {
+	if len(users) != 2 {
+		return r.next.GetCommonFriends(ctx, users)
+	}
+	return r.cached(ctx, "GetCommonFriends", "common", []string{users[0].ID, users[1].ID}, func(ctx context.Context) ([]string, error) {
+		return r.next.GetCommonFriends(ctx, users)
+	})
+}
+
+func (r *cachedRelationshipRepository) GetFriendsByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	return r.next.GetFriendsByEmails(ctx, emails)
+}
+
+func (r *cachedRelationshipRepository) Subscribe(ctx context.Context, requestor_id, target_id string) error {
+	defer r.invalidate(ctx, requestor_id, target_id)
+	return r.next.Subscribe(ctx, requestor_id, target_id)
+}
+
+func (r *cachedRelationshipRepository) Unsubscribe(ctx context.Context, requestor_id, target_id string) error {
+	defer r.invalidate(ctx, requestor_id, target_id)
+	return r.next.Unsubscribe(ctx, requestor_id, target_id)
+}
+
+func (r *cachedRelationshipRepository) CheckSubscriptionExists(ctx context.Context, requestor_id, target_id string) (bool, error) {
+	return r.next.CheckSubscriptionExists(ctx, requestor_id, target_id)
+}
+
+func (r *cachedRelationshipRepository) GetUpdatableEmailAddresses(ctx context.Context, sender_id string) ([]string, error) {
+	return r.cached(ctx, "GetUpdatableEmailAddresses", "recipients", []string{sender_id}, func(ctx context.Context) ([]string, error) {
+		return r.next.GetUpdatableEmailAddresses(ctx, sender_id)
+	})
+}
+
+func (r *cachedRelationshipRepository) GetSubscribersByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	return r.next.GetSubscribersByEmails(ctx, emails)
+}
+
+func (r *cachedRelationshipRepository) BlockUpdates(ctx context.Context, requestor_id, target_id string) error {
+	defer r.invalidate(ctx, requestor_id, target_id)
+	return r.next.BlockUpdates(ctx, requestor_id, target_id)
+}
+
+func (r *cachedRelationshipRepository) UnblockUpdates(ctx context.Context, requestor_id, target_id string) error {
+	defer r.invalidate(ctx, requestor_id, target_id)
+	return r.next.UnblockUpdates(ctx, requestor_id, target_id)
+}
+
+func (r *cachedRelationshipRepository) CheckBlockExists(ctx context.Context, requestor_id, target_id string) (bool, error) {
+	return r.next.CheckBlockExists(ctx, requestor_id, target_id)
+}
+
+func (r *cachedRelationshipRepository) CheckBlocking(ctx context.Context, requestor_id, target_id string) (bool, error) {
+	return r.next.CheckBlocking(ctx, requestor_id, target_id)
+}
+
+func (r *cachedRelationshipRepository) GetBlockedByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	return r.next.GetBlockedByEmails(ctx, emails)
+}
+
+// cached returns the result of method for userIDs from the cache, or loads it from the primary
+// and stores it. The cache is bypassed when ctx asks for primary reads, and while it fails, so
+// reads keep being served from the database.
+func (r *cachedRelationshipRepository) cached(ctx context.Context, method, kind string, userIDs []string, load func(ctx context.Context) ([]string, error)) ([]string, error) {
+	if database.PrimaryReads(ctx) {
+		return load(ctx)
+	}
+	key, err := r.key(ctx, kind, userIDs)
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "relationship cache is unavailable", "error", err)
+		return load(ctx)
+	}
+
+	value, ok, err := r.cache.Get(ctx, key)
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "relationship cache is unavailable", "error", err)
+	}
+	var result []string
+	if ok && json.Unmarshal(value, &result) == nil {
+		r.metrics.CacheLookup(method, true)
+		return result, nil
+	}
+	r.metrics.CacheLookup(method, false)
+
+	result, err = load(database.WithPrimaryReads(ctx))
+	if err != nil {
+		return nil, err
+	}
+	value, err = json.Marshal(result)
+	if err == nil {
+		err = r.cache.Set(ctx, key, value, r.ttl)
+	}
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "failed to cache relationships", "error", err)
+	}
+	return result, nil
+}
+
+// key returns the key of the kind of entry about userIDs, made of the current generation of each.
+func (r *cachedRelationshipRepository) key(ctx context.Context, kind string, userIDs []string) (string, error) {
+	parts := []string{kind}
+	for _, id := range userIDs {
+		generation, err := r.generation(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, id, generation)
+	}
+	return cacheKeyPrefix + strings.Join(parts, ":"), nil
+}
+
+// generation returns the current generation of a user, starting a new one when there is none.
+func (r *cachedRelationshipRepository) generation(ctx context.Context, userID string) (string, error) {
+	key := generationKey(userID)
+	value, ok, err := r.cache.Get(ctx, key)
+	if err != nil || ok {
+		return string(value), err
+	}
+	generation := uuid.NewString()
+	return generation, r.cache.Set(ctx, key, []byte(generation), 0)
+}
+
+// invalidate starts new generations for the users of a changed relationship. It runs whether
+// or not the change succeeded, since a failed commit may still have been applied. A failure
+// leaves their entries in place until they expire, so it is logged as an error.
+func (r *cachedRelationshipRepository) invalidate(ctx context.Context, userIDs ...string) {
+	keys := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		keys[i] = generationKey(id)
+	}
+	if err := r.cache.Delete(ctx, keys...); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "failed to invalidate cached relationships",
+			"users", userIDs, "error", err)
+	}
+}
+
+// userID returns the ID of the user with email. Users keep their email, so the ID is cached
+// for the TTL without being invalidated. Like the entries, it is loaded from the primary.
+func (r *cachedRelationshipRepository) userID(ctx context.Context, email string) (string, error) {
+	key := cacheKeyPrefix + "user:" + email
+	if value, ok, err := r.cache.Get(ctx, key); err == nil && ok {
+		return string(value), nil
+	}
+	found, err := r.users.GetUserByEmail(database.WithPrimaryReads(ctx), email)
+	if err != nil {
+		return "", err
+	}
+	if err := r.cache.Set(ctx, key, []byte(found.ID), r.ttl); err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "failed to cache relationships", "error", err)
+	}
+	return found.ID, nil
+}
+
+// generationKey is the key of a user's generation.
+func generationKey(userID string) string {
+	return cacheKeyPrefix + "generation:" + userID
+}
@@ -0,0 +1,307 @@
+package relationship
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/cache"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database"
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	userRepo "github.com/koeylp/friends-management/cmd/internal/repository/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRelationships serves the reads the cache memoizes from maps the tests change, and
+// counts how often each is made. Mutations change nothing; tests change the maps instead.
+type fakeRelationships struct {
+	RelationshipRepository
+	friends    map[string][]string // by email
+	common     map[string][]string // by the IDs of both users
+	recipients map[string][]string // by ID
+	reads      int
+}
+
+func (f *fakeRelationships) GetFriends(ctx context.Context, email string) ([]string, error) {
+	f.reads++
+	return f.friends[email], nil
+}
+
+func (f *fakeRelationships) GetCommonFriends(ctx context.Context, users []*user.User) ([]string, error) {
+	f.reads++
+	return f.common[users[0].ID+users[1].ID], nil
+}
+
+func (f *fakeRelationships) GetUpdatableEmailAddresses(ctx context.Context, sender_id string) ([]string, error) {
+	f.reads++
+	return f.recipients[sender_id], nil
+}
+
+func (f *fakeRelationships) CreateFriend(ctx context.Context, requestor_id, target_id string) error {
+	return nil
+}
+
+func (f *fakeRelationships) DeleteFriend(ctx context.Context, requestor_id, target_id string) error {
+	return nil
+}
+
+func (f *fakeRelationships) Subscribe(ctx context.Context, requestor_id, target_id string) error {
+	return nil
+}
+
+func (f *fakeRelationships) Unsubscribe(ctx context.Context, requestor_id, target_id string) error {
+	return nil
+}
+
+func (f *fakeRelationships) BlockUpdates(ctx context.Context, requestor_id, target_id string) error {
+	return nil
+}
+
+// UnblockUpdates fails, as a change that may have been applied anyway.
+func (f *fakeRelationships) UnblockUpdates(ctx context.Context, requestor_id, target_id string) error {
+	return errors.New("connection reset")
+}
+
+// laggingRelationships is a routed repository whose replica has not caught up: reads that do
+// not ask for the primary are served what the relationships were before the last change.
+type laggingRelationships struct {
+	*fakeRelationships
+	staleFriends map[string][]string // by email
+	replicaReads int
+}
+
+func (f *laggingRelationships) GetFriends(ctx context.Context, email string) ([]string, error) {
+	if !database.PrimaryReads(ctx) {
+		f.replicaReads++
+		return f.staleFriends[email], nil
+	}
+	return f.fakeRelationships.GetFriends(ctx, email)
+}
+
+// laggingUsers only finds users on the primary, as a replica that has not caught up with their creation.
+type laggingUsers struct {
+	fakeUsers
+}
+
+func (f *laggingUsers) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	if !database.PrimaryReads(ctx) {
+		return nil, errors.New("user not found")
+	}
+	return f.fakeUsers.GetUserByEmail(ctx, email)
+}
+
+// fakeUsers looks users up by email.
+type fakeUsers struct {
+	userRepo.UserRepository
+	ids map[string]string
+}
+
+func (f *fakeUsers) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	id, ok := f.ids[email]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	return &user.User{ID: id, Email: email}, nil
+}
+
+// failingCache is a cache whose backend is down.
+type failingCache struct{}
+
+func (failingCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+
+func (failingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (failingCache) Delete(ctx context.Context, keys ...string) error {
+	return errors.New("connection refused")
+}
+
+var (
+	andy = &user.User{ID: "1", Email: "andy@example.com"}
+	john = &user.User{ID: "2", Email: "john@example.com"}
+	lisa = &user.User{ID: "3", Email: "lisa@example.com"}
+)
+
+func newFakeRelationships() *fakeRelationships {
+	return &fakeRelationships{
+		friends: map[string][]string{
+			andy.Email: {"common@example.com"},
+			john.Email: {"common@example.com"},
+			lisa.Email: {"common@example.com"},
+		},
+		common: map[string][]string{
+			andy.ID + john.ID: {"common@example.com"},
+			andy.ID + lisa.ID: {"common@example.com"},
+		},
+		recipients: map[string][]string{
+			andy.ID: {"common@example.com"},
+			john.ID: {"common@example.com"},
+			lisa.ID: {"common@example.com"},
+		},
+	}
+}
+
+func newCached(next RelationshipRepository, c cache.Cache, m *metrics.Metrics) RelationshipRepository {
+	users := &fakeUsers{ids: map[string]string{andy.Email: andy.ID, john.Email: john.ID, lisa.Email: lisa.ID}}
+	return NewCachedRelationshipRepository(next, users, c, &config.CacheConfig{TTL: time.Minute}, m)
+}
+
+// Test that reads are served from the cache once loaded.
+func TestCachedRelationshipRepository_Hit(t *testing.T) {
+	ctx := context.Background()
+	next := newFakeRelationships()
+	repo := newCached(next, cache.NewLRU(100), metrics.New())
+
+	for range 2 {
+		friends, err := repo.GetFriends(ctx, andy.Email)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"common@example.com"}, friends)
+		common, err := repo.GetCommonFriends(ctx, []*user.User{andy, john})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"common@example.com"}, common)
+		recipients, err := repo.GetUpdatableEmailAddresses(ctx, andy.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"common@example.com"}, recipients)
+	}
+
+	assert.Equal(t, 3, next.reads)
+}
+
+// Test that every mutation invalidates the entries about both of its users, whether or not
+// it succeeds, and leaves the entries about other users cached.
+func TestCachedRelationshipRepository_Invalidate(t *testing.T) {
+	mutations := map[string]func(RelationshipRepository, context.Context, string, string) error{
+		"CreateFriend":   RelationshipRepository.CreateFriend,
+		"DeleteFriend":   RelationshipRepository.DeleteFriend,
+		"Subscribe":      RelationshipRepository.Subscribe,
+		"Unsubscribe":    RelationshipRepository.Unsubscribe,
+		"BlockUpdates":   RelationshipRepository.BlockUpdates,
+		"UnblockUpdates": RelationshipRepository.UnblockUpdates,
+	}
+	for name, mutate := range mutations {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			next := newFakeRelationships()
+			repo := newCached(next, cache.NewLRU(100), metrics.New())
+
+			read := func() map[string][]string {
+				results := make(map[string][]string)
+				for _, u := range []*user.User{andy, john, lisa} {
+					friends, err := repo.GetFriends(ctx, u.Email)
+					require.NoError(t, err)
+					results["friends "+u.Email] = friends
+					recipients, err := repo.GetUpdatableEmailAddresses(ctx, u.ID)
+					require.NoError(t, err)
+					results["recipients "+u.ID] = recipients
+				}
+				for _, pair := range [][]*user.User{{andy, john}, {andy, lisa}} {
+					common, err := repo.GetCommonFriends(ctx, pair)
+					require.NoError(t, err)
+					results["common "+pair[0].ID+pair[1].ID] = common
+				}
+				return results
+			}
+			read()
+
+			// The relationships of everyone change, but only reads about andy or john are reloaded.
+			changed := []string{"changed@example.com"}
+			next.friends[andy.Email], next.friends[john.Email], next.friends[lisa.Email] = changed, changed, changed
+			next.recipients[andy.ID], next.recipients[john.ID], next.recipients[lisa.ID] = changed, changed, changed
+			next.common[andy.ID+john.ID], next.common[andy.ID+lisa.ID] = changed, changed
+			_ = mutate(repo, ctx, andy.ID, john.ID)
+
+			results := read()
+			for _, key := range []string{
+				"friends " + andy.Email, "friends " + john.Email,
+				"recipients " + andy.ID, "recipients " + john.ID,
+				"common " + andy.ID + john.ID, "common " + andy.ID + lisa.ID,
+			} {
+				assert.Equal(t, changed, results[key], key)
+			}
+			assert.Equal(t, []string{"common@example.com"}, results["friends "+lisa.Email])
+			assert.Equal(t, []string{"common@example.com"}, results["recipients "+lisa.ID])
+		})
+	}
+}
+
+// Test that an evicted generation does not bring back the entries written under it.
+func TestCachedRelationshipRepository_EvictedGeneration(t *testing.T) {
+	ctx := context.Background()
+	next := newFakeRelationships()
+	c := cache.NewLRU(100)
+	repo := newCached(next, c, metrics.New())
+
+	_, err := repo.GetUpdatableEmailAddresses(ctx, andy.ID)
+	require.NoError(t, err)
+	require.NoError(t, c.Delete(ctx, generationKey(andy.ID)))
+	next.recipients[andy.ID] = []string{"changed@example.com"}
+
+	recipients, err := repo.GetUpdatableEmailAddresses(ctx, andy.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"changed@example.com"}, recipients)
+	assert.Equal(t, 2, next.reads)
+}
+
+// Test that entries are filled from the primary, so a lagging replica can not fill the cache
+// with relationships a change has just invalidated, and that reads asking for the primary
+// bypass the cache.
+func TestCachedRelationshipRepository_LaggingReplica(t *testing.T) {
+	ctx := context.Background()
+	next := &laggingRelationships{fakeRelationships: newFakeRelationships(), staleFriends: map[string][]string{}}
+	users := &laggingUsers{fakeUsers{ids: map[string]string{andy.Email: andy.ID}}}
+	repo := NewCachedRelationshipRepository(next, users, cache.NewLRU(100), &config.CacheConfig{TTL: time.Minute}, metrics.New())
+
+	friends, err := repo.GetFriends(ctx, andy.Email)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"common@example.com"}, friends)
+
+	next.staleFriends[andy.Email] = []string{"common@example.com"}
+	next.friends[andy.Email] = []string{"common@example.com", "john@example.com"}
+	require.NoError(t, repo.CreateFriend(ctx, andy.ID, john.ID))
+
+	for i := 0; i < 2; i++ {
+		friends, err = repo.GetFriends(ctx, andy.Email)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"common@example.com", "john@example.com"}, friends)
+	}
+	assert.Equal(t, 2, next.reads)
+	assert.Zero(t, next.replicaReads)
+
+	primaryCtx := database.WithPrimaryReads(ctx)
+	_, err = repo.GetFriends(primaryCtx, andy.Email)
+	require.NoError(t, err)
+	assert.Equal(t, 3, next.reads)
+}
+
+// Test that reads are served from the wrapped repository while the cache fails,
+// and that users that can not be looked up are passed through.
+func TestCachedRelationshipRepository_Bypass(t *testing.T) {
+	ctx := context.Background()
+	next := newFakeRelationships()
+	repo := newCached(next, failingCache{}, metrics.New())
+
+	recipients, err := repo.GetUpdatableEmailAddresses(ctx, andy.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"common@example.com"}, recipients)
+	_, err = repo.GetFriends(ctx, andy.Email)
+	require.NoError(t, err)
+	assert.NoError(t, repo.CreateFriend(ctx, andy.ID, john.ID))
+	assert.Equal(t, 2, next.reads)
+
+	repo = newCached(next, cache.NewLRU(100), metrics.New())
+	friends, err := repo.GetFriends(ctx, "unknown@example.com")
+	require.NoError(t, err)
+	assert.Empty(t, friends)
+}
+
+func TestNewCachedRelationshipRepository_NoCache(t *testing.T) {
+	next := newFakeRelationships()
+	assert.Same(t, next, newCached(next, nil, metrics.New()))
+}
@@ -0,0 +1,135 @@
+package relationship
+
+import (
+	"context"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/koeylp/friends-management/cmd/internal/infra/tracing"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+)
+
+// instrumentedRelationshipRepository records every call to the wrapped repository as a span and in the
+// repository duration and error metrics.
+type instrumentedRelationshipRepository struct {
+	next     RelationshipRepository
+	observer *metrics.RepositoryObserver
+}
+
+// NewInstrumentedRelationshipRepository wraps repo so each call is a span named "RelationshipRepository.<method>"
+// and is recorded in m under the "relationship" repository.
+func NewInstrumentedRelationshipRepository(repo RelationshipRepository, m *metrics.Metrics) RelationshipRepository {
+	return &instrumentedRelationshipRepository{next: repo, observer: m.Repository("relationship")}
+}
+
+func (r *instrumentedRelationshipRepository) CreateFriend(ctx context.Context, requestor_id, target_id string) (err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.CreateFriend")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("CreateFriend", time.Now(), &err)
+	return r.next.CreateFriend(ctx, requestor_id, target_id)
+}
+
+func (r *instrumentedRelationshipRepository) DeleteFriend(ctx context.Context, requestor_id, target_id string) (err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.DeleteFriend")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("DeleteFriend", time.Now(), &err)
+	return r.next.DeleteFriend(ctx, requestor_id, target_id)
+}
+
+func (r *instrumentedRelationshipRepository) CheckFriendshipExists(ctx context.Context, requestor_id, target_id string) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.CheckFriendshipExists")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("CheckFriendshipExists", time.Now(), &err)
+	return r.next.CheckFriendshipExists(ctx, requestor_id, target_id)
+}
+
+func (r *instrumentedRelationshipRepository) GetFriends(ctx context.Context, email string) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.GetFriends")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("GetFriends", time.Now(), &err)
+	return r.next.GetFriends(ctx, email)
+}
+
+func (r *instrumentedRelationshipRepository) GetCommonFriends(ctx context.Context, users []*user.User) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.GetCommonFriends")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("GetCommonFriends", time.Now(), &err)
+	return r.next.GetCommonFriends(ctx, users)
+}
+
+func (r *instrumentedRelationshipRepository) GetFriendsByEmails(ctx context.Context, emails []string) (_ map[string][]string, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.GetFriendsByEmails")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("GetFriendsByEmails", time.Now(), &err)
+	return r.next.GetFriendsByEmails(ctx, emails)
+}
+
+func (r *instrumentedRelationshipRepository) Subscribe(ctx context.Context, requestor_id, target_id string) (err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.Subscribe")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("Subscribe", time.Now(), &err)
+	return r.next.Subscribe(ctx, requestor_id, target_id)
+}
+
+func (r *instrumentedRelationshipRepository) Unsubscribe(ctx context.Context, requestor_id, target_id string) (err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.Unsubscribe")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("Unsubscribe", time.Now(), &err)
+	return r.next.Unsubscribe(ctx, requestor_id, target_id)
+}
+
+func (r *instrumentedRelationshipRepository) CheckSubscriptionExists(ctx context.Context, requestor_id, target_id string) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.CheckSubscriptionExists")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("CheckSubscriptionExists", time.Now(), &err)
+	return r.next.CheckSubscriptionExists(ctx, requestor_id, target_id)
+}
+
+func (r *instrumentedRelationshipRepository) GetUpdatableEmailAddresses(ctx context.Context, sender_id string) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.GetUpdatableEmailAddresses")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("GetUpdatableEmailAddresses", time.Now(), &err)
+	return r.next.GetUpdatableEmailAddresses(ctx, sender_id)
+}
+
+func (r *instrumentedRelationshipRepository) GetSubscribersByEmails(ctx context.Context, emails []string) (_ map[string][]string, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.GetSubscribersByEmails")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("GetSubscribersByEmails", time.Now(), &err)
+	return r.next.GetSubscribersByEmails(ctx, emails)
+}
+
+func (r *instrumentedRelationshipRepository) BlockUpdates(ctx context.Context, requestor_id, target_id string) (err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.BlockUpdates")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("BlockUpdates", time.Now(), &err)
+	return r.next.BlockUpdates(ctx, requestor_id, target_id)
+}
+
+func (r *instrumentedRelationshipRepository) UnblockUpdates(ctx context.Context, requestor_id, target_id string) (err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.UnblockUpdates")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("UnblockUpdates", time.Now(), &err)
+	return r.next.UnblockUpdates(ctx, requestor_id, target_id)
+}
+
+func (r *instrumentedRelationshipRepository) CheckBlockExists(ctx context.Context, requestor_id, target_id string) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.CheckBlockExists")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("CheckBlockExists", time.Now(), &err)
+	return r.next.CheckBlockExists(ctx, requestor_id, target_id)
+}
+
+func (r *instrumentedRelationshipRepository) CheckBlocking(ctx context.Context, requestor_id, target_id string) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.CheckBlocking")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("CheckBlocking", time.Now(), &err)
+	return r.next.CheckBlocking(ctx, requestor_id, target_id)
+}
+
+func (r *instrumentedRelationshipRepository) GetBlockedByEmails(ctx context.Context, emails []string) (_ map[string][]string, err error) {
+	ctx, span := tracing.Start(ctx, "RelationshipRepository.GetBlockedByEmails")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("GetBlockedByEmails", time.Now(), &err)
+	return r.next.GetBlockedByEmails(ctx, emails)
+}
@@ -0,0 +1,310 @@
+package relationship
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/memory"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+)
+
+// memoryRelationshipRepository implements the RelationshipRepository interface over an
+// in-memory store, answering as the PostgreSQL implementation does, including its outbox events.
+type memoryRelationshipRepository struct {
+	store *memory.Store
+}
+
+// NewMemoryRelationshipRepository creates a RelationshipRepository keeping relationships in
+// store, between the users stored there.
+func NewMemoryRelationshipRepository(store *memory.Store) RelationshipRepository {
+	return &memoryRelationshipRepository{store: store}
+}
+
+// CreateFriend adds a new friendship relationship.
+func (repo *memoryRelationshipRepository) CreateFriend(ctx context.Context, requestor_id, target_id string) error {
+	return repo.createRelationship(ctx, requestor_id, target_id, FRIEND, event.FriendCreated)
+}
+
+// DeleteFriend removes the friendship between two users, whichever of them requested it.
+func (repo *memoryRelationshipRepository) DeleteFriend(ctx context.Context, requestor_id, target_id string) error {
+	return repo.deleteRelationship(ctx, requestor_id, target_id, FRIEND, event.FriendDeleted, domain.ErrNotFriends)
+}
+
+// createRelationship stores a relationship together with its outbox event. Like the schema,
+// it refuses relationships of a user with itself or with users that are not stored.
+func (repo *memoryRelationshipRepository) createRelationship(ctx context.Context, requestor_id, target_id, relationshipType, eventType string) error {
+	return repo.store.Update(ctx, func(tables *memory.Tables) error {
+		if requestor_id == target_id {
+			return errors.New(`new row for relation "relationships" violates check constraint "no_self_friendship"`)
+		}
+		requestor, target := tables.UserByID(requestor_id), tables.UserByID(target_id)
+		if requestor == nil || target == nil {
+			return errors.New(`insert or update on table "relationships" violates foreign key constraint`)
+		}
+
+		now := time.Now()
+		relationship := &memory.Relationship{
+			ID:               uuid.New().String(),
+			RequestorID:      requestor_id,
+			TargetID:         target_id,
+			RelationshipType: relationshipType,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+		payload, err := relationshipEvent(relationship.ID, requestor, target)
+		if err != nil {
+			return err
+		}
+		tables.Relationships = append(tables.Relationships, relationship)
+		tables.AppendEvent(eventType, payload, now)
+		return nil
+	})
+}
+
+// deleteRelationship removes the relationships the requestor holds with the target together
+// with recording their outbox event. It returns an error of kind notFound when there is none.
+func (repo *memoryRelationshipRepository) deleteRelationship(ctx context.Context, requestor_id, target_id, relationshipType, eventType string, notFound error) error {
+	return repo.store.Update(ctx, func(tables *memory.Tables) error {
+		matches := func(r *memory.Relationship) bool {
+			if r.RelationshipType != relationshipType {
+				return false
+			}
+			if r.RequestorID == requestor_id && r.TargetID == target_id {
+				return true
+			}
+			// A friendship is mutual, so it is removed whichever of the two requested it.
+			return relationshipType == FRIEND && r.RequestorID == target_id && r.TargetID == requestor_id
+		}
+		i := slices.IndexFunc(tables.Relationships, matches)
+		if i < 0 {
+			return domain.Errorf(notFound, "%s", notFound)
+		}
+
+		payload, err := relationshipEvent(tables.Relationships[i].ID, tables.UserByID(requestor_id), tables.UserByID(target_id))
+		if err != nil {
+			return err
+		}
+		tables.Relationships = slices.DeleteFunc(tables.Relationships, matches)
+		tables.AppendEvent(eventType, payload, time.Now())
+		return nil
+	})
+}
+
+// relationshipEvent encodes the payload of a relationship event between requestor and target.
+func relationshipEvent(relationshipID string, requestor, target *memory.User) ([]byte, error) {
+	payload, err := json.Marshal(&event.RelationshipData{RelationshipID: relationshipID, Requestor: requestor.Email, Target: target.Email})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record relationship event: %w", err)
+	}
+	return payload, nil
+}
+
+// CheckFriendshipExists checks if a friendship exists between two users.
+func (repo *memoryRelationshipRepository) CheckFriendshipExists(ctx context.Context, requestor_id, target_id string) (bool, error) {
+	return repo.exists(ctx, FRIEND, between(requestor_id, target_id))
+}
+
+// CheckSubscriptionExists checks if the requestor is subscribed to the target's updates.
+// A subscription of the target to the requestor's updates does not count.
+func (repo *memoryRelationshipRepository) CheckSubscriptionExists(ctx context.Context, requestor_id, target_id string) (bool, error) {
+	return repo.exists(ctx, SUBSCRIBE, func(r *memory.Relationship) bool {
+		return r.RequestorID == requestor_id && r.TargetID == target_id
+	})
+}
+
+// CheckBlockExists checks if a block relationship exists between two users, in either direction.
+func (repo *memoryRelationshipRepository) CheckBlockExists(ctx context.Context, requestor_id, target_id string) (bool, error) {
+	return repo.exists(ctx, BLOCK, between(requestor_id, target_id))
+}
+
+// CheckBlocking checks if the requestor blocks the target's updates.
+// A block of the requestor's updates by the target does not count.
+func (repo *memoryRelationshipRepository) CheckBlocking(ctx context.Context, requestor_id, target_id string) (bool, error) {
+	return repo.exists(ctx, BLOCK, func(r *memory.Relationship) bool {
+		return r.RequestorID == requestor_id && r.TargetID == target_id
+	})
+}
+
+// exists reports whether a relationship of the given type matches.
+func (repo *memoryRelationshipRepository) exists(ctx context.Context, relationshipType string, matches func(r *memory.Relationship) bool) (bool, error) {
+	var exists bool
+	err := repo.store.View(ctx, func(tables *memory.Tables) error {
+		exists = slices.ContainsFunc(tables.Relationships, func(r *memory.Relationship) bool {
+			return r.RelationshipType == relationshipType && matches(r)
+		})
+		return nil
+	})
+	return exists, err
+}
+
+// between matches the relationships either user holds with the other.
+func between(a, b string) func(r *memory.Relationship) bool {
+	return func(r *memory.Relationship) bool {
+		return (r.RequestorID == a && r.TargetID == b) || (r.RequestorID == b && r.TargetID == a)
+	}
+}
+
+// GetFriends retrieves the list of friends for a given user by email.
+// It returns sql.ErrNoRows when no user has the email.
+func (repo *memoryRelationshipRepository) GetFriends(ctx context.Context, email string) ([]string, error) {
+	friends := make([]string, 0)
+	err := repo.store.View(ctx, func(tables *memory.Tables) error {
+		found := tables.UserByEmail(email)
+		if found == nil {
+			return sql.ErrNoRows
+		}
+		for _, friendID := range friendIDs(tables, found.ID) {
+			friends = append(friends, tables.UserByID(friendID).Email)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return friends, nil
+}
+
+// GetCommonFriends retrieves the common friends between two users.
+func (repo *memoryRelationshipRepository) GetCommonFriends(ctx context.Context, users []*user.User) ([]string, error) {
+	var commonFriends []string
+	err := repo.store.View(ctx, func(tables *memory.Tables) error {
+		others := friendIDs(tables, users[1].ID)
+		for _, friendID := range friendIDs(tables, users[0].ID) {
+			email := tables.UserByID(friendID).Email
+			if slices.Contains(others, friendID) && !slices.Contains(commonFriends, email) {
+				commonFriends = append(commonFriends, email)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commonFriends, nil
+}
+
+// friendIDs returns the IDs of the friends of the user with userID.
+func friendIDs(tables *memory.Tables, userID string) []string {
+	var ids []string
+	for _, r := range tables.Relationships {
+		if r.RelationshipType != FRIEND {
+			continue
+		}
+		if r.RequestorID == userID {
+			ids = append(ids, r.TargetID)
+		} else if r.TargetID == userID {
+			ids = append(ids, r.RequestorID)
+		}
+	}
+	return ids
+}
+
+// Subscribe adds a new subscription relationship.
+func (repo *memoryRelationshipRepository) Subscribe(ctx context.Context, requestor_id string, target_id string) error {
+	return repo.createRelationship(ctx, requestor_id, target_id, SUBSCRIBE, event.SubscriptionCreated)
+}
+
+// Unsubscribe removes the requestor's subscription to the target's updates.
+func (repo *memoryRelationshipRepository) Unsubscribe(ctx context.Context, requestor_id string, target_id string) error {
+	return repo.deleteRelationship(ctx, requestor_id, target_id, SUBSCRIBE, event.SubscriptionDeleted, domain.ErrNotSubscribed)
+}
+
+// BlockUpdates adds a new block relationship.
+func (repo *memoryRelationshipRepository) BlockUpdates(ctx context.Context, requestor_id string, target_id string) error {
+	return repo.createRelationship(ctx, requestor_id, target_id, BLOCK, event.BlockCreated)
+}
+
+// UnblockUpdates removes the requestor's block on the target's updates.
+func (repo *memoryRelationshipRepository) UnblockUpdates(ctx context.Context, requestor_id string, target_id string) error {
+	return repo.deleteRelationship(ctx, requestor_id, target_id, BLOCK, event.BlockDeleted, domain.ErrNotBlocked)
+}
+
+// GetUpdatableEmailAddresses retrieves the emails of the sender's friends and subscribers,
+// filtering out the users the sender blocks.
+func (repo *memoryRelationshipRepository) GetUpdatableEmailAddresses(ctx context.Context, sender_id string) ([]string, error) {
+	emails := make([]string, 0)
+	err := repo.store.View(ctx, func(tables *memory.Tables) error {
+		for _, candidate := range tables.Users {
+			if candidate.ID == sender_id {
+				continue
+			}
+			var friend, subscriber, blocked bool
+			for _, r := range tables.Relationships {
+				switch {
+				case r.RelationshipType == FRIEND && ((r.RequestorID == candidate.ID && r.TargetID == sender_id) || (r.RequestorID == sender_id && r.TargetID == candidate.ID)):
+					friend = true
+				case r.RelationshipType == SUBSCRIBE && r.RequestorID == candidate.ID && r.TargetID == sender_id:
+					subscriber = true
+				case r.RelationshipType == BLOCK && r.RequestorID == sender_id && r.TargetID == candidate.ID:
+					blocked = true
+				}
+			}
+			if (friend || subscriber) && !blocked {
+				emails = append(emails, candidate.Email)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
+// GetFriendsByEmails retrieves the friends of every given user, keyed by user email.
+func (repo *memoryRelationshipRepository) GetFriendsByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	return repo.relatedEmails(ctx, emails, FRIEND, func(r *memory.Relationship, userID string) (string, bool) {
+		if r.RequestorID == userID {
+			return r.TargetID, true
+		}
+		return r.RequestorID, r.TargetID == userID
+	})
+}
+
+// GetSubscribersByEmails retrieves the users subscribed to every given user, keyed by user email.
+func (repo *memoryRelationshipRepository) GetSubscribersByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	return repo.relatedEmails(ctx, emails, SUBSCRIBE, func(r *memory.Relationship, userID string) (string, bool) {
+		return r.RequestorID, r.TargetID == userID
+	})
+}
+
+// GetBlockedByEmails retrieves the users every given user blocks, keyed by user email.
+func (repo *memoryRelationshipRepository) GetBlockedByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	return repo.relatedEmails(ctx, emails, BLOCK, func(r *memory.Relationship, userID string) (string, bool) {
+		return r.TargetID, r.RequestorID == userID
+	})
+}
+
+// relatedEmails groups the emails of the users related to each given user by a relationship
+// of the given type, keyed by user email and sorted. related returns the ID of the other user
+// of a relationship, and whether it relates them to the user at all. Users without any
+// related email are absent from the result.
+func (repo *memoryRelationshipRepository) relatedEmails(ctx context.Context, emails []string, relationshipType string, related func(r *memory.Relationship, userID string) (string, bool)) (map[string][]string, error) {
+	result := make(map[string][]string, len(emails))
+	err := repo.store.View(ctx, func(tables *memory.Tables) error {
+		for _, u := range tables.Users {
+			if !slices.Contains(emails, u.Email) {
+				continue
+			}
+			for _, r := range tables.Relationships {
+				if otherID, ok := related(r, u.ID); ok && r.RelationshipType == relationshipType {
+					result[u.Email] = append(result[u.Email], tables.UserByID(otherID).Email)
+				}
+			}
+			slices.Sort(result[u.Email])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
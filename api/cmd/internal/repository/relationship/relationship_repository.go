@@ -3,10 +3,14 @@ package relationship
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/postgres"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
 	"github.com/koeylp/friends-management/cmd/internal/repository/orm"
 	"github.com/volatiletech/sqlboiler/v4/boil"
@@ -17,42 +21,134 @@ import (
 type RelationshipRepository interface {
 	// Friend
 	CreateFriend(ctx context.Context, requestor_id, target_id string) error
+	DeleteFriend(ctx context.Context, requestor_id, target_id string) error
 	CheckFriendshipExists(ctx context.Context, requestor_id, target_id string) (bool, error)
 	GetFriends(ctx context.Context, email string) ([]string, error)
 	GetCommonFriends(ctx context.Context, users []*user.User) ([]string, error)
+	GetFriendsByEmails(ctx context.Context, emails []string) (map[string][]string, error)
 
 	// Subscription
 	Subscribe(ctx context.Context, requestor_id, target_id string) error
+	Unsubscribe(ctx context.Context, requestor_id, target_id string) error
 	CheckSubscriptionExists(ctx context.Context, requestor_id, target_id string) (bool, error)
 	GetUpdatableEmailAddresses(ctx context.Context, sender_id string) ([]string, error)
+	GetSubscribersByEmails(ctx context.Context, emails []string) (map[string][]string, error)
 
 	// Block
 	BlockUpdates(ctx context.Context, requestor_id, target_id string) error
+	UnblockUpdates(ctx context.Context, requestor_id, target_id string) error
 	CheckBlockExists(ctx context.Context, requestor_id, target_id string) (bool, error)
+	CheckBlocking(ctx context.Context, requestor_id, target_id string) (bool, error)
+	GetBlockedByEmails(ctx context.Context, emails []string) (map[string][]string, error)
 }
 
 // relationshipRepositoryImpl is the implementation of the RelationshipRepository interface.
+// It writes to db, as do the existence checks guarding writes, while friend lists, common
+// friends and recipients are read through reads, which may go to a read replica.
 type relationshipRepositoryImpl struct {
-	db *sql.DB
+	db    *sql.DB
+	reads *postgres.ReadRouter
 }
 
 // NewRelationshipRepository creates a new instance of RelationshipRepository.
 func NewRelationshipRepository(db *sql.DB) RelationshipRepository {
-	return &relationshipRepositoryImpl{db: db}
+	return NewRoutedRelationshipRepository(postgres.NewReadRouter(db, nil))
+}
+
+// NewRoutedRelationshipRepository creates a RelationshipRepository writing to the primary of
+// dbs and reading friend lists, common friends and recipients on the database dbs routes each read to.
+func NewRoutedRelationshipRepository(dbs *postgres.ReadRouter) RelationshipRepository {
+	return &relationshipRepositoryImpl{db: dbs.Primary(), reads: dbs}
 }
 
 // CreateFriend adds a new friendship relationship to the database.
 func (repo *relationshipRepositoryImpl) CreateFriend(ctx context.Context, requestor_id, target_id string) error {
-	friend := orm.Relationship{
+	return repo.createRelationship(ctx, requestor_id, target_id, FRIEND, event.FriendCreated)
+}
+
+// DeleteFriend removes the friendship between two users, whichever of them requested it.
+func (repo *relationshipRepositoryImpl) DeleteFriend(ctx context.Context, requestor_id, target_id string) error {
+	return repo.deleteRelationship(ctx, requestor_id, target_id, FRIEND, event.FriendDeleted, domain.ErrNotFriends)
+}
+
+// createRelationship inserts a relationship together with its outbox event in one transaction,
+// so an event is recorded if and only if the relationship itself is committed.
+func (repo *relationshipRepositoryImpl) createRelationship(ctx context.Context, requestor_id, target_id, relationshipType, eventType string) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	relationship := orm.Relationship{
 		ID:               uuid.New().String(),
 		RequestorID:      requestor_id,
 		TargetID:         target_id,
-		RelationshipType: FRIEND,
+		RelationshipType: relationshipType,
 		CreatedAt:        time.Now(),
 		UpdatedAt:        time.Now(),
 	}
+	if err := relationship.Insert(ctx, tx, boil.Infer()); err != nil {
+		return err
+	}
+	if err := insertEvent(ctx, tx, eventType, &relationship, relationship.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteRelationship removes the relationship the requestor holds with the target together with
+// recording its outbox event in one transaction. It returns an error of kind notFound when there is none.
+func (repo *relationshipRepositoryImpl) deleteRelationship(ctx context.Context, requestor_id, target_id, relationshipType, eventType string, notFound error) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	relationship := orm.Relationship{
+		RequestorID:      requestor_id,
+		TargetID:         target_id,
+		RelationshipType: relationshipType,
+	}
+	query := `DELETE FROM relationships WHERE requestor_id = $1 AND target_id = $2 AND relationship_type = $3 RETURNING id`
+	if relationshipType == FRIEND {
+		// A friendship is mutual, so it is removed whichever of the two requested it.
+		query = `
+    DELETE FROM relationships
+    WHERE ((requestor_id = $1 AND target_id = $2) OR (requestor_id = $2 AND target_id = $1))
+      AND relationship_type = $3
+    RETURNING id`
+	}
+	err = tx.QueryRowContext(ctx, query, requestor_id, target_id, relationshipType).Scan(&relationship.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Errorf(notFound, "%s", notFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete relationship: %w", err)
+	}
+	if err := insertEvent(ctx, tx, eventType, &relationship, time.Now()); err != nil {
+		return err
+	}
 
-	return friend.Insert(ctx, repo.db, boil.Infer())
+	return tx.Commit()
+}
+
+// insertEvent records a relationship event that happened at the given time in the outbox,
+// resolving both user emails inside the same transaction.
+func insertEvent(ctx context.Context, tx *sql.Tx, eventType string, relationship *orm.Relationship, at time.Time) error {
+	query := `
+    INSERT INTO relationship_events (event_type, payload, next_attempt_at, created_at)
+    SELECT $1, json_build_object('relationship_id', $2::text, 'requestor', ru.email, 'target', tu.email), $5, $5
+    FROM users ru, users tu
+    WHERE ru.id = $3 AND tu.id = $4`
+
+	_, err := tx.ExecContext(ctx, query, eventType, relationship.ID, relationship.RequestorID, relationship.TargetID, at)
+	if err != nil {
+		return fmt.Errorf("failed to record relationship event: %w", err)
+	}
+	return nil
 }
 
 // CheckFriendshipExists checks if a friendship exists between two users.
@@ -71,16 +167,17 @@ func (repo *relationshipRepositoryImpl) CheckFriendshipExists(ctx context.Contex
 
 // GetFriends retrieves the list of friends for a given user by email.
 func (repo *relationshipRepositoryImpl) GetFriends(ctx context.Context, email string) ([]string, error) {
+	db := repo.reads.Reader(ctx)
 	user, err := orm.Users(
 		orm.UserWhere.Email.EQ(email),
-	).One(ctx, repo.db)
+	).One(ctx, db)
 	if err != nil {
 		return nil, err
 	}
 
 	relationships, err := orm.Relationships(
 		qm.Where("(requestor_id = ? OR target_id = ?) AND relationship_type = ?", user.ID, user.ID, FRIEND),
-	).All(ctx, repo.db)
+	).All(ctx, db)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +195,7 @@ func (repo *relationshipRepositoryImpl) GetFriends(ctx context.Context, email st
 	for _, friendID := range friendIDs {
 		friend, err := orm.Users(
 			orm.UserWhere.ID.EQ(friendID),
-		).One(ctx, repo.db)
+		).One(ctx, db)
 		if err != nil {
 			return nil, err
 		}
@@ -137,7 +234,7 @@ func (repo *relationshipRepositoryImpl) GetCommonFriends(ctx context.Context, us
     ON u1.friend_id = u.id;
     `
 
-	rows, err := repo.db.QueryContext(ctx, query, users[0].ID, users[1].ID, FRIEND)
+	rows, err := repo.reads.Reader(ctx).QueryContext(ctx, query, users[0].ID, users[1].ID, FRIEND)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query common friends: %w", err)
 	}
@@ -161,40 +258,48 @@ func (repo *relationshipRepositoryImpl) GetCommonFriends(ctx context.Context, us
 
 // Subscribe adds a new subscription relationship to the database.
 func (repo *relationshipRepositoryImpl) Subscribe(ctx context.Context, requestor_id string, target_id string) error {
-	subcription := orm.Relationship{
-		ID:               uuid.New().String(),
-		RequestorID:      requestor_id,
-		TargetID:         target_id,
-		RelationshipType: SUBSCRIBE,
-		CreatedAt:        time.Now(),
-		UpdatedAt:        time.Now(),
-	}
+	return repo.createRelationship(ctx, requestor_id, target_id, SUBSCRIBE, event.SubscriptionCreated)
+}
 
-	return subcription.Insert(ctx, repo.db, boil.Infer())
+// Unsubscribe removes the requestor's subscription to the target's updates.
+func (repo *relationshipRepositoryImpl) Unsubscribe(ctx context.Context, requestor_id string, target_id string) error {
+	return repo.deleteRelationship(ctx, requestor_id, target_id, SUBSCRIBE, event.SubscriptionDeleted, domain.ErrNotSubscribed)
 }
 
-// CheckSubscriptionExists checks if a subscription relationship exists between two users.
+// CheckSubscriptionExists checks if the requestor is subscribed to the target's updates.
+// A subscription of the target to the requestor's updates does not count.
 func (repo *relationshipRepositoryImpl) CheckSubscriptionExists(ctx context.Context, requestor_id string, target_id string) (bool, error) {
+	return repo.holds(ctx, requestor_id, target_id, SUBSCRIBE)
+}
+
+// CheckBlockExists checks if a block relationship exists between two users, in either direction.
+func (repo *relationshipRepositoryImpl) CheckBlockExists(ctx context.Context, requestor_id string, target_id string) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS (
 		SELECT 1 FROM relationships 
 		WHERE (requestor_id = $1 AND target_id = $2 AND relationship_type = $3) OR (requestor_id = $2 AND target_id = $1 AND relationship_type = $3)
 	)`
-	err := repo.db.QueryRowContext(ctx, query, requestor_id, target_id, SUBSCRIBE).Scan(&exists)
+	err := repo.db.QueryRowContext(ctx, query, requestor_id, target_id, BLOCK).Scan(&exists)
 	if err != nil {
 		return true, err
 	}
 	return exists, nil
 }
 
-// CheckBlockExists checks if a block relationship exists between two users.
-func (repo *relationshipRepositoryImpl) CheckBlockExists(ctx context.Context, requestor_id string, target_id string) (bool, error) {
+// CheckBlocking checks if the requestor blocks the target's updates.
+// A block of the requestor's updates by the target does not count.
+func (repo *relationshipRepositoryImpl) CheckBlocking(ctx context.Context, requestor_id string, target_id string) (bool, error) {
+	return repo.holds(ctx, requestor_id, target_id, BLOCK)
+}
+
+// holds reports whether the requestor holds a relationship of the given type with the target.
+func (repo *relationshipRepositoryImpl) holds(ctx context.Context, requestor_id, target_id, relationshipType string) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS (
-		SELECT 1 FROM relationships 
-		WHERE (requestor_id = $1 AND target_id = $2 AND relationship_type = $3) OR (requestor_id = $2 AND target_id = $1 AND relationship_type = $3)
+		SELECT 1 FROM relationships
+		WHERE requestor_id = $1 AND target_id = $2 AND relationship_type = $3
 	)`
-	err := repo.db.QueryRowContext(ctx, query, requestor_id, target_id, BLOCK).Scan(&exists)
+	err := repo.db.QueryRowContext(ctx, query, requestor_id, target_id, relationshipType).Scan(&exists)
 	if err != nil {
 		return true, err
 	}
@@ -203,16 +308,12 @@ func (repo *relationshipRepositoryImpl) CheckBlockExists(ctx context.Context, re
 
 // BlockUpdates adds a new block relationship to the database.
 func (repo *relationshipRepositoryImpl) BlockUpdates(ctx context.Context, requestor_id string, target_id string) error {
-	block := orm.Relationship{
-		ID:               uuid.New().String(),
-		RequestorID:      requestor_id,
-		TargetID:         target_id,
-		RelationshipType: BLOCK,
-		CreatedAt:        time.Now(),
-		UpdatedAt:        time.Now(),
-	}
+	return repo.createRelationship(ctx, requestor_id, target_id, BLOCK, event.BlockCreated)
+}
 
-	return block.Insert(ctx, repo.db, boil.Infer())
+// UnblockUpdates removes the requestor's block on the target's updates.
+func (repo *relationshipRepositoryImpl) UnblockUpdates(ctx context.Context, requestor_id string, target_id string) error {
+	return repo.deleteRelationship(ctx, requestor_id, target_id, BLOCK, event.BlockDeleted, domain.ErrNotBlocked)
 }
 
 // GetUpdatableEmailAddresses retrieves email addresses that can be updated, filtering out blocked users.
@@ -225,7 +326,7 @@ func (repo *relationshipRepositoryImpl) GetUpdatableEmailAddresses(ctx context.C
 		qm.LeftOuterJoin("relationships AS r2 ON r2.requestor_id = users.id AND r2.target_id = ? AND r2.relationship_type = ?", sender_id, SUBSCRIBE),
 		qm.Where("users.id NOT IN (SELECT target_id FROM relationships WHERE requestor_id = ? AND relationship_type = ?)", sender_id, BLOCK),
 		qm.Where("r1.relationship_type = 'Friend' OR r2.relationship_type = 'Subscribe'"),
-	).All(ctx, repo.db)
+	).All(ctx, repo.reads.Reader(ctx))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recipients: %v", err)
@@ -237,3 +338,65 @@ func (repo *relationshipRepositoryImpl) GetUpdatableEmailAddresses(ctx context.C
 	}
 	return emails, nil
 }
+
+// GetFriendsByEmails retrieves the friends of every given user in one query, keyed by user email.
+func (repo *relationshipRepositoryImpl) GetFriendsByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	query := `
+    SELECT u.email, o.email
+    FROM users u
+    JOIN relationships r ON (r.requestor_id = u.id OR r.target_id = u.id) AND r.relationship_type = $2
+    JOIN users o ON o.id = CASE WHEN r.requestor_id = u.id THEN r.target_id ELSE r.requestor_id END
+    WHERE u.email = ANY($1)
+    ORDER BY u.email, o.email`
+
+	return repo.queryRelatedEmails(ctx, query, emails, FRIEND)
+}
+
+// GetSubscribersByEmails retrieves the users subscribed to every given user in one query, keyed by user email.
+func (repo *relationshipRepositoryImpl) GetSubscribersByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	query := `
+    SELECT u.email, o.email
+    FROM users u
+    JOIN relationships r ON r.target_id = u.id AND r.relationship_type = $2
+    JOIN users o ON o.id = r.requestor_id
+    WHERE u.email = ANY($1)
+    ORDER BY u.email, o.email`
+
+	return repo.queryRelatedEmails(ctx, query, emails, SUBSCRIBE)
+}
+
+// GetBlockedByEmails retrieves the users every given user blocks in one query, keyed by user email.
+func (repo *relationshipRepositoryImpl) GetBlockedByEmails(ctx context.Context, emails []string) (map[string][]string, error) {
+	query := `
+    SELECT u.email, o.email
+    FROM users u
+    JOIN relationships r ON r.requestor_id = u.id AND r.relationship_type = $2
+    JOIN users o ON o.id = r.target_id
+    WHERE u.email = ANY($1)
+    ORDER BY u.email, o.email`
+
+	return repo.queryRelatedEmails(ctx, query, emails, BLOCK)
+}
+
+// queryRelatedEmails runs a query returning (user email, related email) rows and groups them by user email.
+// Users without any related email are absent from the result.
+func (repo *relationshipRepositoryImpl) queryRelatedEmails(ctx context.Context, query string, emails []string, relationshipType string) (map[string][]string, error) {
+	rows, err := repo.reads.Reader(ctx).QueryContext(ctx, query, emails, relationshipType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s relationships: %w", relationshipType, err)
+	}
+	defer rows.Close()
+
+	related := make(map[string][]string, len(emails))
+	for rows.Next() {
+		var email, other string
+		if err := rows.Scan(&email, &other); err != nil {
+			return nil, fmt.Errorf("failed to scan email: %w", err)
+		}
+		related[email] = append(related[email], other)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return related, nil
+}
@@ -2,10 +2,16 @@ package relationship
 
 import (
 	"context"
+	"database/sql/driver"
+	"errors"
 	"regexp"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/postgres"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,6 +28,7 @@ func TestCreateFriend(t *testing.T) {
 	requestorID := "user1-id"
 	targetID := "user2-id"
 
+	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "relationships" ("id","requestor_id","target_id","relationship_type","created_at","updated_at")`)).
 		WithArgs(
 			sqlmock.AnyArg(),
@@ -32,6 +39,10 @@ func TestCreateFriend(t *testing.T) {
 			sqlmock.AnyArg(),
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO relationship_events`).
+		WithArgs(event.FriendCreated, sqlmock.AnyArg(), requestorID, targetID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	err = repo.CreateFriend(context.Background(), requestorID, targetID)
 
@@ -41,6 +52,27 @@ func TestCreateFriend(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// TestCreateFriend_EventFailureRollsBack tests that the friendship is not committed when its event cannot be recorded.
+func TestCreateFriend_EventFailureRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRelationshipRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "relationships"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO relationship_events`).
+		WillReturnError(errors.New("disk full"))
+	mock.ExpectRollback()
+
+	err = repo.CreateFriend(context.Background(), "user1-id", "user2-id")
+
+	assert.EqualError(t, err, "failed to record relationship event: disk full")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 // TestCheckFriendshipExists tests the functionality to check if a friendship exists.
 func TestCheckFriendshipExists(t *testing.T) {
 	db, mock, err := sqlmock.New()
@@ -172,6 +204,7 @@ func TestSubscribe(t *testing.T) {
 	requestorID := "user1-id"
 	targetID := "user2-id"
 
+	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "relationships" ("id","requestor_id","target_id","relationship_type","created_at","updated_at")`)).
 		WithArgs(
 			sqlmock.AnyArg(),
@@ -182,6 +215,10 @@ func TestSubscribe(t *testing.T) {
 			sqlmock.AnyArg(),
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO relationship_events`).
+		WithArgs(event.SubscriptionCreated, sqlmock.AnyArg(), requestorID, targetID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	err = repo.Subscribe(context.Background(), requestorID, targetID)
 
@@ -191,6 +228,75 @@ func TestSubscribe(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// TestUnsubscribe tests that removing a subscription records its event in the same transaction.
+func TestUnsubscribe(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRelationshipRepository(db)
+
+	requestorID := "user1-id"
+	targetID := "user2-id"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`DELETE FROM relationships`).
+		WithArgs(requestorID, targetID, SUBSCRIBE).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rel-1"))
+	mock.ExpectExec(`INSERT INTO relationship_events`).
+		WithArgs(event.SubscriptionDeleted, "rel-1", requestorID, targetID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = repo.Unsubscribe(context.Background(), requestorID, targetID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDeleteFriend tests that a friendship is removed whichever user requested it.
+func TestDeleteFriend(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRelationshipRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE ((requestor_id = $1 AND target_id = $2) OR (requestor_id = $2 AND target_id = $1))`)).
+		WithArgs("user1-id", "user2-id", FRIEND).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rel-1"))
+	mock.ExpectExec(`INSERT INTO relationship_events`).
+		WithArgs(event.FriendDeleted, "rel-1", "user1-id", "user2-id", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = repo.DeleteFriend(context.Background(), "user1-id", "user2-id")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUnblockUpdates_NotFound tests that removing a block that does not exist reports it without recording an event.
+func TestUnblockUpdates_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRelationshipRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`DELETE FROM relationships`).
+		WithArgs("user1-id", "user2-id", BLOCK).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	err = repo.UnblockUpdates(context.Background(), "user1-id", "user2-id")
+
+	assert.ErrorIs(t, err, domain.ErrNotBlocked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 // TestCheckSubscriptionExists tests the functionality to check if a subscription exists.
 func TestCheckSubscriptionExists(t *testing.T) {
 	db, mock, err := sqlmock.New()
@@ -257,6 +363,25 @@ func TestCheckBlockExists(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+// TestCheckBlocking tests that only the requestor's own block is looked up.
+func TestCheckBlocking(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRelationshipRepository(db)
+
+	mock.ExpectQuery(`WHERE requestor_id = \$1 AND target_id = \$2 AND relationship_type = \$3\s+\)`).
+		WithArgs("123", "456", BLOCK).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	exists, err := repo.CheckBlocking(context.Background(), "123", "456")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 // TestBlock tests the BlockUpdates method in the RelationshipRepository.
 func TestBlock(t *testing.T) {
 	db, mock, err := sqlmock.New()
@@ -268,6 +393,7 @@ func TestBlock(t *testing.T) {
 	requestorID := "user1-id"
 	targetID := "user2-id"
 
+	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "relationships" ("id","requestor_id","target_id","relationship_type","created_at","updated_at")`)).
 		WithArgs(
 			sqlmock.AnyArg(),
@@ -278,6 +404,10 @@ func TestBlock(t *testing.T) {
 			sqlmock.AnyArg(),
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO relationship_events`).
+		WithArgs(event.BlockCreated, sqlmock.AnyArg(), requestorID, targetID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	err = repo.BlockUpdates(context.Background(), requestorID, targetID)
 
@@ -313,3 +443,120 @@ func TestGetUpdatableEmailAddresses(t *testing.T) {
 	err = mock.ExpectationsWereMet()
 	require.NoError(t, err)
 }
+
+// passthroughConverter hands arguments to sqlmock unchanged, as the pgx driver accepts slices natively.
+type passthroughConverter struct{}
+
+func (passthroughConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	return v, nil
+}
+
+// TestGetFriendsByEmails tests that the friends of several users are fetched in one query and grouped by email.
+func TestGetFriendsByEmails(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(passthroughConverter{}))
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRelationshipRepository(db)
+	emails := []string{"andy@example.com", "john@example.com", "kate@example.com"}
+
+	mock.ExpectQuery(`WHERE u.email = ANY\(\$1\)`).
+		WithArgs(emails, FRIEND).
+		WillReturnRows(sqlmock.NewRows([]string{"email", "email"}).
+			AddRow("andy@example.com", "common@example.com").
+			AddRow("andy@example.com", "john@example.com").
+			AddRow("john@example.com", "andy@example.com"))
+
+	friends, err := repo.GetFriendsByEmails(context.Background(), emails)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"andy@example.com": {"common@example.com", "john@example.com"},
+		"john@example.com": {"andy@example.com"},
+	}, friends)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetSubscribersByEmails tests that subscribers are queried by subscription type.
+func TestGetSubscribersByEmails(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(passthroughConverter{}))
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRelationshipRepository(db)
+	emails := []string{"john@example.com"}
+
+	mock.ExpectQuery(`JOIN relationships r ON r.target_id = u.id`).
+		WithArgs(emails, SUBSCRIBE).
+		WillReturnRows(sqlmock.NewRows([]string{"email", "email"}).AddRow("john@example.com", "lisa@example.com"))
+
+	subscribers, err := repo.GetSubscribersByEmails(context.Background(), emails)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"john@example.com": {"lisa@example.com"}}, subscribers)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetBlockedByEmails tests that a query failure is reported.
+func TestGetBlockedByEmails(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(passthroughConverter{}))
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRelationshipRepository(db)
+
+	mock.ExpectQuery(`JOIN relationships r ON r.requestor_id = u.id`).
+		WithArgs([]string{"andy@example.com"}, BLOCK).
+		WillReturnError(errors.New("connection reset"))
+
+	_, err = repo.GetBlockedByEmails(context.Background(), []string{"andy@example.com"})
+
+	assert.EqualError(t, err, "failed to query Block relationships: connection reset")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRoutedRelationshipRepository tests that lists are read from the replica, and that
+// existence checks and reads asking for the primary are not.
+func TestRoutedRelationshipRepository(t *testing.T) {
+	primary, primaryMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer primary.Close()
+	replica, replicaMock, err := sqlmock.New(sqlmock.ValueConverterOption(passthroughConverter{}))
+	require.NoError(t, err)
+	defer replica.Close()
+
+	repo := NewRoutedRelationshipRepository(postgres.NewReadRouter(primary, replica))
+	users := []*user.User{{ID: "user1-id"}, {ID: "user2-id"}}
+	emails := sqlmock.NewRows([]string{"email"}).AddRow("common@example.com")
+
+	replicaMock.ExpectQuery(`WITH user1_friends AS`).WillReturnRows(emails)
+	primaryMock.ExpectQuery(`SELECT EXISTS`).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	primaryMock.ExpectQuery(`WITH user1_friends AS`).WillReturnRows(sqlmock.NewRows([]string{"email"}))
+
+	result, err := repo.GetCommonFriends(context.Background(), users)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"common@example.com"}, result)
+
+	exists, err := repo.CheckFriendshipExists(context.Background(), "user1-id", "user2-id")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	_, err = repo.GetCommonFriends(database.WithPrimaryReads(context.Background()), users)
+	assert.NoError(t, err)
+
+	// The batch lookups read from the replica too.
+	for _, relationshipType := range []string{FRIEND, SUBSCRIBE, BLOCK} {
+		replicaMock.ExpectQuery(`WHERE u.email = ANY\(\$1\)`).
+			WithArgs([]string{"andy@example.com"}, relationshipType).
+			WillReturnRows(sqlmock.NewRows([]string{"email", "email"}))
+	}
+	_, err = repo.GetFriendsByEmails(context.Background(), []string{"andy@example.com"})
+	assert.NoError(t, err)
+	_, err = repo.GetSubscribersByEmails(context.Background(), []string{"andy@example.com"})
+	assert.NoError(t, err)
+	_, err = repo.GetBlockedByEmails(context.Background(), []string{"andy@example.com"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
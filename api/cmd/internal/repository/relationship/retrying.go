@@ -0,0 +1,126 @@
+package relationship
+
+import (
+	"context"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/postgres"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+)
+
+// retryingRelationshipRepository retries the reads of the wrapped repository that fail with a
+// transient error, such as a serialization failure or a connection reset. Writes are passed
+// through as is, since they may have been applied before the error.
+type retryingRelationshipRepository struct {
+	next   RelationshipRepository
+	policy postgres.RetryPolicy
+}
+
+// NewRetryingRelationshipRepository wraps repo so its reads are retried as configured by cfg.
+func NewRetryingRelationshipRepository(repo RelationshipRepository, cfg *config.DBConfig) RelationshipRepository {
+	return &retryingRelationshipRepository{next: repo, policy: postgres.NewReadRetryPolicy(cfg)}
+}
+
+func (r *retryingRelationshipRepository) CreateFriend(ctx context.Context, requestor_id, target_id string) error {
+	return r.next.CreateFriend(ctx, requestor_id, target_id)
+}
+
+func (r *retryingRelationshipRepository) DeleteFriend(ctx context.Context, requestor_id, target_id string) error {
+	return r.next.DeleteFriend(ctx, requestor_id, target_id)
+}
+
+func (r *retryingRelationshipRepository) CheckFriendshipExists(ctx context.Context, requestor_id, target_id string) (result bool, err error) {
+	err = r.policy.Do(ctx, func() error {
+		result, err = r.next.CheckFriendshipExists(ctx, requestor_id, target_id)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingRelationshipRepository) GetFriends(ctx context.Context, email string) (result []string, err error) {
+	err = r.policy.Do(ctx, func() error {
+		result, err = r.next.GetFriends(ctx, email)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingRelationshipRepository) GetCommonFriends(ctx context.Context, users []*user.User) (result []string, err error) {
+	err = r.policy.Do(ctx, func() error {
+		result, err = r.next.GetCommonFriends(ctx, users)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingRelationshipRepository) GetFriendsByEmails(ctx context.Context, emails []string) (result map[string][]string, err error) {
+	err = r.policy.Do(ctx, func() error {
+		result, err = r.next.GetFriendsByEmails(ctx, emails)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingRelationshipRepository) Subscribe(ctx context.Context, requestor_id, target_id string) error {
+	return r.next.Subscribe(ctx, requestor_id, target_id)
+}
+
+func (r *retryingRelationshipRepository) Unsubscribe(ctx context.Context, requestor_id, target_id string) error {
+	return r.next.Unsubscribe(ctx, requestor_id, target_id)
+}
+
+func (r *retryingRelationshipRepository) CheckSubscriptionExists(ctx context.Context, requestor_id, target_id string) (result bool, err error) {
+	err = r.policy.Do(ctx, func() error {
+		result, err = r.next.CheckSubscriptionExists(ctx, requestor_id, target_id)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingRelationshipRepository) GetUpdatableEmailAddresses(ctx context.Context, sender_id string) (result []string, err error) {
+	err = r.policy.Do(ctx, func() error {
+		result, err = r.next.GetUpdatableEmailAddresses(ctx, sender_id)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingRelationshipRepository) GetSubscribersByEmails(ctx context.Context, emails []string) (result map[string][]string, err error) {
+	err = r.policy.Do(ctx, func() error {
+		result, err = r.next.GetSubscribersByEmails(ctx, emails)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingRelationshipRepository) BlockUpdates(ctx context.Context, requestor_id, target_id string) error {
+	return r.next.BlockUpdates(ctx, requestor_id, target_id)
+}
+
+func (r *retryingRelationshipRepository) UnblockUpdates(ctx context.Context, requestor_id, target_id string) error {
+	return r.next.UnblockUpdates(ctx, requestor_id, target_id)
+}
+
+func (r *retryingRelationshipRepository) CheckBlockExists(ctx context.Context, requestor_id, target_id string) (result bool, err error) {
+	err = r.policy.Do(ctx, func() error {
+		result, err = r.next.CheckBlockExists(ctx, requestor_id, target_id)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingRelationshipRepository) CheckBlocking(ctx context.Context, requestor_id, target_id string) (result bool, err error) {
+	err = r.policy.Do(ctx, func() error {
+		result, err = r.next.CheckBlocking(ctx, requestor_id, target_id)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingRelationshipRepository) GetBlockedByEmails(ctx context.Context, emails []string) (result map[string][]string, err error) {
+	err = r.policy.Do(ctx, func() error {
+		result, err = r.next.GetBlockedByEmails(ctx, emails)
+		return err
+	})
+	return result, err
+}
@@ -0,0 +1,21 @@
+package repotest
+
+import (
+	"testing"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/memory"
+	outboxRepo "github.com/koeylp/friends-management/cmd/internal/repository/outbox"
+	relationshipRepo "github.com/koeylp/friends-management/cmd/internal/repository/relationship"
+	userRepo "github.com/koeylp/friends-management/cmd/internal/repository/user"
+)
+
+func TestMemoryRepositories(t *testing.T) {
+	Run(t, func(t *testing.T) *Repositories {
+		store := memory.NewStore()
+		return &Repositories{
+			Users:         userRepo.NewMemoryUserRepository(store),
+			Relationships: relationshipRepo.NewMemoryRelationshipRepository(store),
+			Outbox:        outboxRepo.NewMemoryOutboxRepository(store),
+		}
+	})
+}
@@ -0,0 +1,401 @@
+// Package repotest holds the contract the user and relationship repositories fulfil, whatever
+// store they keep their data in, as tests any implementation can be run against.
+package repotest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	outboxRepo "github.com/koeylp/friends-management/cmd/internal/repository/outbox"
+	relationshipRepo "github.com/koeylp/friends-management/cmd/internal/repository/relationship"
+	userRepo "github.com/koeylp/friends-management/cmd/internal/repository/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Repositories are the repositories under test, sharing one store. Relationship changes are
+// checked through the events they record in Outbox.
+type Repositories struct {
+	Users         userRepo.UserRepository
+	Relationships relationshipRepo.RelationshipRepository
+	Outbox        outboxRepo.OutboxRepository
+}
+
+// Run runs the contract as subtests of t. open is called for each of them and returns
+// repositories over an empty store.
+func Run(t *testing.T, open func(t *testing.T) *Repositories) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repos *Repositories)
+	}{
+		{"Users", testUsers},
+		{"UsersByEmails", testUsersByEmails},
+		{"Friends", testFriends},
+		{"CommonFriends", testCommonFriends},
+		{"DeleteFriend", testDeleteFriend},
+		{"Subscriptions", testSubscriptions},
+		{"Blocks", testBlocks},
+		{"InvalidRelationships", testInvalidRelationships},
+		{"UpdatableEmailAddresses", testUpdatableEmailAddresses},
+		{"RelatedByEmails", testRelatedByEmails},
+		{"Events", testEvents},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, open(t))
+		})
+	}
+}
+
+// createUsers stores a user for each email and returns them in the same order.
+func createUsers(t *testing.T, repos *Repositories, emails ...string) []*user.User {
+	t.Helper()
+	ctx := context.Background()
+	users := make([]*user.User, len(emails))
+	for i, email := range emails {
+		require.NoError(t, repos.Users.CreateUser(ctx, &user.CreateUser{Email: email}))
+		found, err := repos.Users.GetUserByEmail(ctx, email)
+		require.NoError(t, err)
+		users[i] = found
+	}
+	return users
+}
+
+func testUsers(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	andy := createUsers(t, repos, "andy@example.com")[0]
+
+	assert.Equal(t, "andy@example.com", andy.Email)
+	assert.NotEmpty(t, andy.ID)
+	assert.False(t, andy.CreatedAt.IsZero())
+
+	err := repos.Users.CreateUser(ctx, &user.CreateUser{Email: "andy@example.com"})
+	assert.ErrorIs(t, err, domain.ErrUserExists)
+
+	_, err = repos.Users.GetUserByEmail(ctx, "unknown@example.com")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
+
+// Users are looked up together in the order of the emails, and the emails no user has are reported.
+func testUsersByEmails(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	users := createUsers(t, repos, "andy@example.com", "john@example.com", "lisa@example.com")
+	andy, john := users[0], users[1]
+
+	found, missing, err := repos.Users.GetUsersByEmails(ctx, []string{john.Email, "unknown@example.com", andy.Email})
+	require.NoError(t, err)
+	assert.Equal(t, []*user.User{john, andy}, found)
+	assert.Equal(t, []string{"unknown@example.com"}, missing)
+
+	found, missing, err = repos.Users.GetUsersByEmails(ctx, []string{})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+	assert.Empty(t, missing)
+}
+
+func testFriends(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	users := createUsers(t, repos, "andy@example.com", "john@example.com", "lisa@example.com", "kate@example.com")
+	andy, john, lisa, kate := users[0], users[1], users[2], users[3]
+
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, andy.ID, john.ID))
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, lisa.ID, andy.ID))
+
+	for _, pair := range [][2]*user.User{{andy, john}, {john, andy}, {andy, lisa}} {
+		exists, err := repos.Relationships.CheckFriendshipExists(ctx, pair[0].ID, pair[1].ID)
+		require.NoError(t, err)
+		assert.True(t, exists, "%s and %s are friends", pair[0].Email, pair[1].Email)
+	}
+	exists, err := repos.Relationships.CheckFriendshipExists(ctx, john.ID, lisa.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	friends, err := repos.Relationships.GetFriends(ctx, andy.Email)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{john.Email, lisa.Email}, friends)
+
+	friends, err = repos.Relationships.GetFriends(ctx, john.Email)
+	require.NoError(t, err)
+	assert.Equal(t, []string{andy.Email}, friends)
+
+	// Users without friends have an empty list rather than none.
+	friends, err = repos.Relationships.GetFriends(ctx, kate.Email)
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, friends)
+
+	_, err = repos.Relationships.GetFriends(ctx, "unknown@example.com")
+	assert.Error(t, err)
+}
+
+func testCommonFriends(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	users := createUsers(t, repos, "andy@example.com", "john@example.com", "lisa@example.com", "kate@example.com", "sean@example.com")
+	andy, john, lisa, kate, sean := users[0], users[1], users[2], users[3], users[4]
+
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, andy.ID, lisa.ID))
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, kate.ID, andy.ID))
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, john.ID, lisa.ID))
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, kate.ID, john.ID))
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, andy.ID, sean.ID))
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, andy.ID, john.ID))
+	// Subscriptions do not make friends.
+	require.NoError(t, repos.Relationships.Subscribe(ctx, john.ID, sean.ID))
+
+	common, err := repos.Relationships.GetCommonFriends(ctx, []*user.User{andy, john})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{lisa.Email, kate.Email}, common)
+
+	common, err = repos.Relationships.GetCommonFriends(ctx, []*user.User{lisa, sean})
+	require.NoError(t, err)
+	assert.Equal(t, []string{andy.Email}, common)
+
+	common, err = repos.Relationships.GetCommonFriends(ctx, []*user.User{andy, sean})
+	require.NoError(t, err)
+	assert.Empty(t, common)
+}
+
+func testDeleteFriend(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	users := createUsers(t, repos, "andy@example.com", "john@example.com")
+	andy, john := users[0], users[1]
+
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, andy.ID, john.ID))
+
+	// A friendship is removed by either of its users.
+	require.NoError(t, repos.Relationships.DeleteFriend(ctx, john.ID, andy.ID))
+
+	exists, err := repos.Relationships.CheckFriendshipExists(ctx, andy.ID, john.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	friends, err := repos.Relationships.GetFriends(ctx, andy.Email)
+	require.NoError(t, err)
+	assert.Empty(t, friends)
+
+	err = repos.Relationships.DeleteFriend(ctx, andy.ID, john.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFriends)
+}
+
+func testSubscriptions(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	users := createUsers(t, repos, "andy@example.com", "john@example.com", "lisa@example.com")
+	andy, john, lisa := users[0], users[1], users[2]
+
+	require.NoError(t, repos.Relationships.Subscribe(ctx, andy.ID, john.ID))
+
+	// Only the subscriber's own subscription counts.
+	exists, err := repos.Relationships.CheckSubscriptionExists(ctx, andy.ID, john.ID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	for _, pair := range [][2]*user.User{{john, andy}, {andy, lisa}} {
+		exists, err := repos.Relationships.CheckSubscriptionExists(ctx, pair[0].ID, pair[1].ID)
+		require.NoError(t, err)
+		assert.False(t, exists)
+	}
+
+	// The reverse subscription is a relationship of its own.
+	require.NoError(t, repos.Relationships.Subscribe(ctx, john.ID, andy.ID))
+	require.NoError(t, repos.Relationships.Unsubscribe(ctx, john.ID, andy.ID))
+
+	// Only the subscriber removes a subscription.
+	err = repos.Relationships.Unsubscribe(ctx, john.ID, andy.ID)
+	assert.ErrorIs(t, err, domain.ErrNotSubscribed)
+	require.NoError(t, repos.Relationships.Unsubscribe(ctx, andy.ID, john.ID))
+
+	exists, err = repos.Relationships.CheckSubscriptionExists(ctx, andy.ID, john.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	err = repos.Relationships.Unsubscribe(ctx, andy.ID, john.ID)
+	assert.ErrorIs(t, err, domain.ErrNotSubscribed)
+}
+
+func testBlocks(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	users := createUsers(t, repos, "andy@example.com", "john@example.com", "lisa@example.com")
+	andy, john, lisa := users[0], users[1], users[2]
+
+	require.NoError(t, repos.Relationships.BlockUpdates(ctx, andy.ID, john.ID))
+
+	// CheckBlockExists looks in both directions, CheckBlocking only at the blocking user's own block.
+	for _, pair := range [][2]*user.User{{andy, john}, {john, andy}} {
+		exists, err := repos.Relationships.CheckBlockExists(ctx, pair[0].ID, pair[1].ID)
+		require.NoError(t, err)
+		assert.True(t, exists)
+	}
+	exists, err := repos.Relationships.CheckBlockExists(ctx, andy.ID, lisa.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	exists, err = repos.Relationships.CheckBlocking(ctx, andy.ID, john.ID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = repos.Relationships.CheckBlocking(ctx, john.ID, andy.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// The reverse block is a relationship of its own.
+	require.NoError(t, repos.Relationships.BlockUpdates(ctx, john.ID, andy.ID))
+	require.NoError(t, repos.Relationships.UnblockUpdates(ctx, john.ID, andy.ID))
+
+	// Only the blocking user removes a block.
+	err = repos.Relationships.UnblockUpdates(ctx, john.ID, andy.ID)
+	assert.ErrorIs(t, err, domain.ErrNotBlocked)
+	require.NoError(t, repos.Relationships.UnblockUpdates(ctx, andy.ID, john.ID))
+
+	exists, err = repos.Relationships.CheckBlockExists(ctx, andy.ID, john.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	err = repos.Relationships.UnblockUpdates(ctx, andy.ID, john.ID)
+	assert.ErrorIs(t, err, domain.ErrNotBlocked)
+}
+
+// Relationships of a user with itself or with a user that does not exist are refused.
+func testInvalidRelationships(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	andy := createUsers(t, repos, "andy@example.com")[0]
+	unknownID := uuid.New().String()
+
+	assert.Error(t, repos.Relationships.CreateFriend(ctx, andy.ID, andy.ID))
+	assert.Error(t, repos.Relationships.CreateFriend(ctx, andy.ID, unknownID))
+	assert.Error(t, repos.Relationships.Subscribe(ctx, unknownID, andy.ID))
+	assert.Error(t, repos.Relationships.BlockUpdates(ctx, andy.ID, unknownID))
+
+	friends, err := repos.Relationships.GetFriends(ctx, andy.Email)
+	require.NoError(t, err)
+	assert.Empty(t, friends)
+}
+
+func testUpdatableEmailAddresses(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	users := createUsers(t, repos,
+		"sender@example.com", "friend@example.com", "subscriber@example.com", "both@example.com",
+		"blocked-friend@example.com", "blocked-subscriber@example.com", "stranger@example.com", "followed@example.com")
+	sender, friend, subscriber, both := users[0], users[1], users[2], users[3]
+	blockedFriend, blockedSubscriber, stranger, followed := users[4], users[5], users[6], users[7]
+
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, friend.ID, sender.ID))
+	require.NoError(t, repos.Relationships.Subscribe(ctx, subscriber.ID, sender.ID))
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, sender.ID, both.ID))
+	require.NoError(t, repos.Relationships.Subscribe(ctx, both.ID, sender.ID))
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, sender.ID, blockedFriend.ID))
+	require.NoError(t, repos.Relationships.BlockUpdates(ctx, sender.ID, blockedFriend.ID))
+	require.NoError(t, repos.Relationships.Subscribe(ctx, blockedSubscriber.ID, sender.ID))
+	require.NoError(t, repos.Relationships.BlockUpdates(ctx, sender.ID, blockedSubscriber.ID))
+	require.NoError(t, repos.Relationships.Subscribe(ctx, sender.ID, followed.ID))
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, stranger.ID, friend.ID))
+
+	recipients, err := repos.Relationships.GetUpdatableEmailAddresses(ctx, sender.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{friend.Email, subscriber.Email, both.Email}, recipients)
+
+	recipients, err = repos.Relationships.GetUpdatableEmailAddresses(ctx, stranger.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{friend.Email}, recipients)
+
+	// Senders without recipients have an empty list rather than none.
+	recipients, err = repos.Relationships.GetUpdatableEmailAddresses(ctx, subscriber.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, recipients)
+}
+
+func testRelatedByEmails(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	users := createUsers(t, repos, "andy@example.com", "john@example.com", "lisa@example.com", "kate@example.com")
+	andy, john, lisa, kate := users[0], users[1], users[2], users[3]
+
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, lisa.ID, andy.ID))
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, andy.ID, john.ID))
+	require.NoError(t, repos.Relationships.Subscribe(ctx, kate.ID, andy.ID))
+	require.NoError(t, repos.Relationships.Subscribe(ctx, john.ID, andy.ID))
+	require.NoError(t, repos.Relationships.BlockUpdates(ctx, andy.ID, kate.ID))
+	require.NoError(t, repos.Relationships.BlockUpdates(ctx, john.ID, andy.ID))
+
+	emails := []string{andy.Email, john.Email, kate.Email, "unknown@example.com"}
+
+	// Related emails are sorted, and users without any are absent.
+	friends, err := repos.Relationships.GetFriendsByEmails(ctx, emails)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		andy.Email: {john.Email, lisa.Email},
+		john.Email: {andy.Email},
+	}, friends)
+
+	subscribers, err := repos.Relationships.GetSubscribersByEmails(ctx, emails)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{andy.Email: {john.Email, kate.Email}}, subscribers)
+
+	blocked, err := repos.Relationships.GetBlockedByEmails(ctx, emails)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		andy.Email: {kate.Email},
+		john.Email: {andy.Email},
+	}, blocked)
+}
+
+// Every relationship change records an event naming its users, in the order of the changes.
+func testEvents(t *testing.T, repos *Repositories) {
+	ctx := context.Background()
+	users := createUsers(t, repos, "andy@example.com", "john@example.com")
+	andy, john := users[0], users[1]
+
+	require.NoError(t, repos.Relationships.CreateFriend(ctx, andy.ID, john.ID))
+	require.NoError(t, repos.Relationships.DeleteFriend(ctx, john.ID, andy.ID))
+	require.NoError(t, repos.Relationships.Subscribe(ctx, andy.ID, john.ID))
+	require.NoError(t, repos.Relationships.Unsubscribe(ctx, andy.ID, john.ID))
+	require.NoError(t, repos.Relationships.BlockUpdates(ctx, john.ID, andy.ID))
+	require.NoError(t, repos.Relationships.UnblockUpdates(ctx, john.ID, andy.ID))
+	// Failed changes record nothing.
+	require.Error(t, repos.Relationships.UnblockUpdates(ctx, john.ID, andy.ID))
+
+	events, err := repos.Outbox.ClaimPending(ctx, 10, 5, time.Minute)
+	require.NoError(t, err)
+
+	expected := []struct{ eventType, requestor, target string }{
+		{event.FriendCreated, andy.Email, john.Email},
+		{event.FriendDeleted, john.Email, andy.Email},
+		{event.SubscriptionCreated, andy.Email, john.Email},
+		{event.SubscriptionDeleted, andy.Email, john.Email},
+		{event.BlockCreated, john.Email, andy.Email},
+		{event.BlockDeleted, john.Email, andy.Email},
+	}
+	require.Len(t, events, len(expected))
+	var created event.RelationshipData
+	for i, e := range events {
+		var data event.RelationshipData
+		require.NoError(t, json.Unmarshal(e.Data, &data))
+		assert.Equal(t, expected[i].eventType, e.Type)
+		assert.Equal(t, expected[i].requestor, data.Requestor, e.Type)
+		assert.Equal(t, expected[i].target, data.Target, e.Type)
+		assert.NotEmpty(t, data.RelationshipID, e.Type)
+		if i > 0 {
+			assert.Greater(t, e.ID, events[i-1].ID)
+		}
+		if e.Type == event.FriendCreated {
+			created = data
+		}
+		if e.Type == event.FriendDeleted {
+			// A deleted event names the relationship that was removed.
+			assert.Equal(t, created.RelationshipID, data.RelationshipID)
+		}
+	}
+
+	// Claimed events are leased, and published ones are not claimed again.
+	claimed, err := repos.Outbox.ClaimPending(ctx, 10, 5, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+	require.NoError(t, repos.Outbox.MarkPublished(ctx, events[0].ID))
+	assert.Empty(t, events[1].PublishedSinks)
+	require.NoError(t, repos.Outbox.MarkFailed(ctx, events[1].ID, assert.AnError, time.Now().Add(-time.Second), []string{"stream"}))
+
+	// A retried event names the sinks that accepted it before.
+	claimed, err = repos.Outbox.ClaimPending(ctx, 10, 5, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, events[1].ID, claimed[0].ID)
+	assert.Equal(t, 2, claimed[0].Attempts)
+	assert.Equal(t, []string{"stream"}, claimed[0].PublishedSinks)
+}
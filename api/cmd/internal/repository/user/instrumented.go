@@ -0,0 +1,44 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/koeylp/friends-management/cmd/internal/infra/tracing"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+)
+
+// instrumentedUserRepository records every call to the wrapped repository as a span and in the
+// repository duration and error metrics.
+type instrumentedUserRepository struct {
+	next     UserRepository
+	observer *metrics.RepositoryObserver
+}
+
+// NewInstrumentedUserRepository wraps repo so each call is a span named "UserRepository.<method>"
+// and is recorded in m under the "user" repository.
+func NewInstrumentedUserRepository(repo UserRepository, m *metrics.Metrics) UserRepository {
+	return &instrumentedUserRepository{next: repo, observer: m.Repository("user")}
+}
+
+func (r *instrumentedUserRepository) CreateUser(ctx context.Context, user *user.CreateUser) (err error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.CreateUser")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("CreateUser", time.Now(), &err)
+	return r.next.CreateUser(ctx, user)
+}
+
+func (r *instrumentedUserRepository) GetUserByEmail(ctx context.Context, email string) (_ *user.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetUserByEmail")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("GetUserByEmail", time.Now(), &err)
+	return r.next.GetUserByEmail(ctx, email)
+}
+
+func (r *instrumentedUserRepository) GetUsersByEmails(ctx context.Context, emails []string) (_ []*user.User, _ []string, err error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetUsersByEmails")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("GetUsersByEmails", time.Now(), &err)
+	return r.next.GetUsersByEmails(ctx, emails)
+}
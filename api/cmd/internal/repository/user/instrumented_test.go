@@ -0,0 +1,34 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that calls go through to the wrapped repository and are recorded, failures included.
+func TestInstrumentedUserRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	m := metrics.New()
+	repo := NewInstrumentedUserRepository(NewUserRepository(db), m)
+
+	mock.ExpectQuery(`SELECT "users".* FROM "users"`).WillReturnError(errors.New("connection reset"))
+
+	_, err = repo.GetUserByEmail(context.Background(), "andy@example.com")
+	assert.ErrorContains(t, err, "connection reset")
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	rr := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rr.Body.String(), `friends_repository_query_duration_seconds_count{method="GetUserByEmail",repository="user"} 1`)
+	assert.Contains(t, rr.Body.String(), `friends_repository_query_errors_total{method="GetUserByEmail",repository="user"} 1`)
+}
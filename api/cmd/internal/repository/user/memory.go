@@ -0,0 +1,73 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/memory"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+)
+
+// memoryUserRepository implements the UserRepository interface over an in-memory store.
+type memoryUserRepository struct {
+	store *memory.Store
+}
+
+// NewMemoryUserRepository creates a UserRepository keeping users in store.
+func NewMemoryUserRepository(store *memory.Store) UserRepository {
+	return &memoryUserRepository{store: store}
+}
+
+// CreateUser stores a new user with the provided user data.
+// It returns domain.ErrUserExists when the email is already taken.
+func (repo *memoryUserRepository) CreateUser(ctx context.Context, user *user.CreateUser) error {
+	return repo.store.Update(ctx, func(tables *memory.Tables) error {
+		if tables.UserByEmail(user.Email) != nil {
+			return domain.Errorf(domain.ErrUserExists, "user already exists with email %s", user.Email)
+		}
+		now := time.Now()
+		tables.Users = append(tables.Users, &memory.User{
+			ID:        uuid.New().String(),
+			Email:     user.Email,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		return nil
+	})
+}
+
+// GetUserByEmail retrieves a user by their email address.
+// It returns domain.ErrUserNotFound when no user has the email.
+func (repo *memoryUserRepository) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	var found *user.User
+	err := repo.store.View(ctx, func(tables *memory.Tables) error {
+		stored := tables.UserByEmail(email)
+		if stored == nil {
+			return domain.Errorf(domain.ErrUserNotFound, "user not found with email %s", email)
+		}
+		found = &user.User{ID: stored.ID, Email: stored.Email, CreatedAt: stored.CreatedAt, UpdatedAt: stored.UpdatedAt}
+		return nil
+	})
+	return found, err
+}
+
+// GetUsersByEmails retrieves the users with the given email addresses.
+// Found users are returned in the order of emails, together with the emails no user has.
+func (repo *memoryUserRepository) GetUsersByEmails(ctx context.Context, emails []string) ([]*user.User, []string, error) {
+	byEmail := make(map[string]*user.User, len(emails))
+	err := repo.store.View(ctx, func(tables *memory.Tables) error {
+		for _, email := range emails {
+			if stored := tables.UserByEmail(email); stored != nil {
+				byEmail[email] = &user.User{ID: stored.ID, Email: stored.Email, CreatedAt: stored.CreatedAt, UpdatedAt: stored.UpdatedAt}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	users, missing := splitByEmail(emails, byEmail)
+	return users, missing, nil
+}
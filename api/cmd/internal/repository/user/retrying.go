@@ -0,0 +1,42 @@
+package user
+
+import (
+	"context"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/postgres"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+)
+
+// retryingUserRepository retries the reads of the wrapped repository that fail with a
+// transient error, such as a serialization failure or a connection reset. Writes are passed
+// through as is, since they may have been applied before the error.
+type retryingUserRepository struct {
+	next   UserRepository
+	policy postgres.RetryPolicy
+}
+
+// NewRetryingUserRepository wraps repo so its reads are retried as configured by cfg.
+func NewRetryingUserRepository(repo UserRepository, cfg *config.DBConfig) UserRepository {
+	return &retryingUserRepository{next: repo, policy: postgres.NewReadRetryPolicy(cfg)}
+}
+
+func (r *retryingUserRepository) CreateUser(ctx context.Context, user *user.CreateUser) error {
+	return r.next.CreateUser(ctx, user)
+}
+
+func (r *retryingUserRepository) GetUserByEmail(ctx context.Context, email string) (result *user.User, err error) {
+	err = r.policy.Do(ctx, func() error {
+		result, err = r.next.GetUserByEmail(ctx, email)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingUserRepository) GetUsersByEmails(ctx context.Context, emails []string) (users []*user.User, missing []string, err error) {
+	err = r.policy.Do(ctx, func() error {
+		users, missing, err = r.next.GetUsersByEmails(ctx, emails)
+		return err
+	})
+	return users, missing, err
+}
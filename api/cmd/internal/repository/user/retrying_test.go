@@ -0,0 +1,48 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that reads are retried on transient errors, while writes are not.
+func TestRetryingUserRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewRetryingUserRepository(NewUserRepository(db), &config.DBConfig{ReadAttempts: 3, ReadBackoff: time.Millisecond})
+
+	serializationFailure := &pgconn.PgError{Code: "40001"}
+	mock.ExpectQuery(`SELECT "users".* FROM "users"`).WillReturnError(serializationFailure)
+	mock.ExpectQuery(`SELECT "users".* FROM "users"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "created_at", "updated_at"}).
+			AddRow("1", "andy@example.com", time.Now(), time.Now()))
+
+	found, err := repo.GetUserByEmail(context.Background(), "andy@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "andy@example.com", found.Email)
+
+	mock.ExpectQuery(`SELECT "users".* FROM "users"`).WillReturnError(serializationFailure)
+	mock.ExpectQuery(`SELECT "users".* FROM "users"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "created_at", "updated_at"}).
+			AddRow("1", "andy@example.com", time.Now(), time.Now()))
+
+	users, missing, err := repo.GetUsersByEmails(context.Background(), []string{"andy@example.com", "john@example.com"})
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, []string{"john@example.com"}, missing)
+
+	mock.ExpectExec(`INSERT INTO "users"`).WillReturnError(serializationFailure)
+
+	err = repo.CreateUser(context.Background(), &user.CreateUser{Email: "john@example.com"})
+	assert.ErrorContains(t, err, "40001")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,9 +3,13 @@ package user
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/postgres"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
 	"github.com/koeylp/friends-management/cmd/internal/repository/orm"
 
@@ -17,19 +21,32 @@ import (
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *user.CreateUser) error
 	GetUserByEmail(ctx context.Context, email string) (*user.User, error)
+	GetUsersByEmails(ctx context.Context, emails []string) ([]*user.User, []string, error)
 }
 
 // userRepositoryImpl implements the UserRepository interface.
+// It writes to db and looks users up through reads, which may go to a read replica.
 type userRepositoryImpl struct {
-	db *sql.DB
+	db    *sql.DB
+	reads *postgres.ReadRouter
 }
 
 // NewUserRepository creates a new instance of UserRepository with the provided database connection.
 func NewUserRepository(db *sql.DB) UserRepository {
-	return &userRepositoryImpl{db: db}
+	return NewRoutedUserRepository(postgres.NewReadRouter(db, nil))
 }
 
+// NewRoutedUserRepository creates a UserRepository writing to the primary of dbs and
+// looking users up on the database dbs routes each read to.
+func NewRoutedUserRepository(dbs *postgres.ReadRouter) UserRepository {
+	return &userRepositoryImpl{db: dbs.Primary(), reads: dbs}
+}
+
+// uniqueViolation is the PostgreSQL error code for a unique constraint violation.
+const uniqueViolation = "23505"
+
 // CreateUser inserts a new user into the database using the provided user data.
+// It returns domain.ErrUserExists when the email is already taken.
 func (repo *userRepositoryImpl) CreateUser(ctx context.Context, user *user.CreateUser) error {
 	newUser := orm.User{
 		ID:        uuid.New().String(),
@@ -40,16 +57,58 @@ func (repo *userRepositoryImpl) CreateUser(ctx context.Context, user *user.Creat
 
 	err := newUser.Insert(ctx, repo.db, boil.Infer())
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return domain.Errorf(domain.ErrUserExists, "user already exists with email %s", user.Email)
+		}
 		return err
 	}
 	return nil
 }
 
 // GetUserByEmail retrieves a user from the database by their email address.
+// It returns domain.ErrUserNotFound when no user has the email.
 func (repo *userRepositoryImpl) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
-	foundUser, err := orm.Users(qm.Where("email = ?", email)).One(ctx, repo.db)
+	foundUser, err := orm.Users(qm.Where("email = ?", email)).One(ctx, repo.reads.Reader(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.Errorf(domain.ErrUserNotFound, "user not found with email %s", email)
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &user.User{ID: foundUser.ID, Email: foundUser.Email, CreatedAt: foundUser.CreatedAt, UpdatedAt: foundUser.UpdatedAt}, err
 }
+
+// GetUsersByEmails retrieves the users with the given email addresses in a single query.
+// Found users are returned in the order of emails, together with the emails no user has.
+func (repo *userRepositoryImpl) GetUsersByEmails(ctx context.Context, emails []string) ([]*user.User, []string, error) {
+	if len(emails) == 0 {
+		return []*user.User{}, []string{}, nil
+	}
+
+	foundUsers, err := orm.Users(orm.UserWhere.Email.IN(emails)).All(ctx, repo.reads.Reader(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	byEmail := make(map[string]*user.User, len(foundUsers))
+	for _, foundUser := range foundUsers {
+		byEmail[foundUser.Email] = &user.User{ID: foundUser.ID, Email: foundUser.Email, CreatedAt: foundUser.CreatedAt, UpdatedAt: foundUser.UpdatedAt}
+	}
+	users, missing := splitByEmail(emails, byEmail)
+	return users, missing, nil
+}
+
+// splitByEmail returns the users of byEmail in the order of emails, and the emails
+// that are not in byEmail.
+func splitByEmail(emails []string, byEmail map[string]*user.User) ([]*user.User, []string) {
+	users := make([]*user.User, 0, len(emails))
+	missing := make([]string, 0)
+	for _, email := range emails {
+		if found, ok := byEmail[email]; ok {
+			users = append(users, found)
+		} else {
+			missing = append(missing, email)
+		}
+	}
+	return users, missing
+}
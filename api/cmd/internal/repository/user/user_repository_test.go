@@ -2,11 +2,14 @@ package user
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
 	"github.com/koeylp/friends-management/cmd/internal/model/dto/user"
 	"github.com/stretchr/testify/assert"
 )
@@ -72,3 +75,80 @@ func TestGetUserByEmail(t *testing.T) {
 		t.Errorf("unfulfilled expectations: %v", err)
 	}
 }
+
+// TestCreateUser_Exists tests that a duplicate email is reported as domain.ErrUserExists.
+func TestCreateUser_Exists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error initializing sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectExec("INSERT INTO \"users\"").
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+
+	err = repo.CreateUser(context.Background(), &user.CreateUser{Email: "taken@example.com"})
+	assert.ErrorIs(t, err, domain.ErrUserExists)
+	assert.EqualError(t, err, "user already exists with email taken@example.com")
+}
+
+// TestGetUserByEmail_NotFound tests that a missing user is reported as domain.ErrUserNotFound.
+func TestGetUserByEmail_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error initializing sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectQuery(`SELECT .* FROM "users" WHERE \(email = \$1\) LIMIT 1`).
+		WithArgs("ghost@example.com").
+		WillReturnError(sql.ErrNoRows)
+
+	result, err := repo.GetUserByEmail(context.Background(), "ghost@example.com")
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	assert.EqualError(t, err, "user not found with email ghost@example.com")
+}
+
+// TestGetUsersByEmails tests that users are looked up in one query, returned in the order of the
+// emails, together with the emails no user has.
+func TestGetUsersByEmails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error initializing sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+	createdAt := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "email", "created_at", "updated_at"}).
+		AddRow("2", "john@example.com", createdAt, createdAt).
+		AddRow("1", "andy@example.com", createdAt, createdAt)
+
+	mock.ExpectQuery(`SELECT .* FROM "users" WHERE \("users"\."email" IN \(\$1,\$2,\$3\)\)`).
+		WithArgs("andy@example.com", "unknown@example.com", "john@example.com").
+		WillReturnRows(rows)
+
+	users, missing, err := repo.GetUsersByEmails(ctx, []string{"andy@example.com", "unknown@example.com", "john@example.com"})
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "1", users[0].ID)
+	assert.Equal(t, "john@example.com", users[1].Email)
+	assert.Equal(t, []string{"unknown@example.com"}, missing)
+
+	// Without emails there is nothing to query.
+	users, missing, err = repo.GetUsersByEmails(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+	assert.Empty(t, missing)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
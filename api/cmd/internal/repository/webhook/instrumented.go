@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/koeylp/friends-management/cmd/internal/infra/tracing"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+)
+
+// instrumentedWebhookRepository records every call to the wrapped repository as a span and in the
+// repository duration and error metrics.
+type instrumentedWebhookRepository struct {
+	next     WebhookRepository
+	observer *metrics.RepositoryObserver
+}
+
+// NewInstrumentedWebhookRepository wraps repo so each call is a span named "WebhookRepository.<method>"
+// and is recorded in m under the "webhook" repository.
+func NewInstrumentedWebhookRepository(repo WebhookRepository, m *metrics.Metrics) WebhookRepository {
+	return &instrumentedWebhookRepository{next: repo, observer: m.Repository("webhook")}
+}
+
+func (r *instrumentedWebhookRepository) CreateSubscription(ctx context.Context, subscription *webhook.Subscription) (err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.CreateSubscription")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("CreateSubscription", time.Now(), &err)
+	return r.next.CreateSubscription(ctx, subscription)
+}
+
+func (r *instrumentedWebhookRepository) GetSubscription(ctx context.Context, id string) (_ *webhook.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.GetSubscription")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("GetSubscription", time.Now(), &err)
+	return r.next.GetSubscription(ctx, id)
+}
+
+func (r *instrumentedWebhookRepository) ListSubscriptions(ctx context.Context) (_ []*webhook.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.ListSubscriptions")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("ListSubscriptions", time.Now(), &err)
+	return r.next.ListSubscriptions(ctx)
+}
+
+func (r *instrumentedWebhookRepository) DeactivateSubscription(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.DeactivateSubscription")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("DeactivateSubscription", time.Now(), &err)
+	return r.next.DeactivateSubscription(ctx, id)
+}
+
+func (r *instrumentedWebhookRepository) EnqueueDeliveries(ctx context.Context, eventID int64, subscriptionIDs []string) (err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.EnqueueDeliveries")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("EnqueueDeliveries", time.Now(), &err)
+	return r.next.EnqueueDeliveries(ctx, eventID, subscriptionIDs)
+}
+
+func (r *instrumentedWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) (_ []*webhook.PendingDelivery, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.ClaimDueDeliveries")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("ClaimDueDeliveries", time.Now(), &err)
+	return r.next.ClaimDueDeliveries(ctx, limit, lease)
+}
+
+func (r *instrumentedWebhookRepository) RecordAttempt(ctx context.Context, deliveryID string, attempt *webhook.DeliveryAttempt, status string, nextAttemptAt time.Time) (err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.RecordAttempt")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("RecordAttempt", time.Now(), &err)
+	return r.next.RecordAttempt(ctx, deliveryID, attempt, status, nextAttemptAt)
+}
+
+func (r *instrumentedWebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit int) (_ []*webhook.Delivery, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.ListDeliveries")
+	defer tracing.End(span, &err)
+	defer r.observer.Observe("ListDeliveries", time.Now(), &err)
+	return r.next.ListDeliveries(ctx, subscriptionID, limit)
+}
@@ -0,0 +1,247 @@
+package webhook
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/memory"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+)
+
+// memoryWebhookRepository implements the WebhookRepository interface over an in-memory store.
+type memoryWebhookRepository struct {
+	store *memory.Store
+}
+
+// NewMemoryWebhookRepository creates a WebhookRepository keeping subscriptions and deliveries
+// in store, delivering the events recorded there.
+func NewMemoryWebhookRepository(store *memory.Store) WebhookRepository {
+	return &memoryWebhookRepository{store: store}
+}
+
+// CreateSubscription stores a new active webhook subscription.
+func (repo *memoryWebhookRepository) CreateSubscription(ctx context.Context, subscription *webhook.Subscription) error {
+	return repo.store.Update(ctx, func(tables *memory.Tables) error {
+		if tables.SubscriptionByID(subscription.ID) != nil {
+			return errors.New(`duplicate key value violates unique constraint "webhook_subscriptions_pkey"`)
+		}
+		tables.Subscriptions = append(tables.Subscriptions, &memory.Subscription{
+			ID:        subscription.ID,
+			URL:       subscription.URL,
+			Secret:    subscription.Secret,
+			Events:    slices.Clone(subscription.Events),
+			Active:    subscription.Active,
+			CreatedAt: subscription.CreatedAt,
+			UpdatedAt: subscription.CreatedAt,
+		})
+		return nil
+	})
+}
+
+// GetSubscription retrieves an active subscription by id, without its secret.
+// It returns domain.ErrWebhookNotFound when no active subscription exists.
+func (repo *memoryWebhookRepository) GetSubscription(ctx context.Context, id string) (*webhook.Subscription, error) {
+	var subscription *webhook.Subscription
+	err := repo.store.View(ctx, func(tables *memory.Tables) error {
+		stored := tables.SubscriptionByID(id)
+		if stored == nil || !stored.Active {
+			return domain.Errorf(domain.ErrWebhookNotFound, "webhook subscription not found with id %s", id)
+		}
+		subscription = toSubscription(stored)
+		return nil
+	})
+	return subscription, err
+}
+
+// ListSubscriptions retrieves every active subscription, without secrets.
+func (repo *memoryWebhookRepository) ListSubscriptions(ctx context.Context) ([]*webhook.Subscription, error) {
+	subscriptions := make([]*webhook.Subscription, 0)
+	err := repo.store.View(ctx, func(tables *memory.Tables) error {
+		for _, stored := range tables.Subscriptions {
+			if stored.Active {
+				subscriptions = append(subscriptions, toSubscription(stored))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(subscriptions, func(a, b *webhook.Subscription) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return subscriptions, nil
+}
+
+// toSubscription copies a stored subscription, without its secret.
+func toSubscription(stored *memory.Subscription) *webhook.Subscription {
+	return &webhook.Subscription{
+		ID:        stored.ID,
+		URL:       stored.URL,
+		Events:    slices.Clone(stored.Events),
+		Active:    stored.Active,
+		CreatedAt: stored.CreatedAt,
+	}
+}
+
+// DeactivateSubscription stops deliveries to a subscription while keeping its delivery history.
+// It returns domain.ErrWebhookNotFound when no active subscription exists.
+func (repo *memoryWebhookRepository) DeactivateSubscription(ctx context.Context, id string) error {
+	return repo.store.Update(ctx, func(tables *memory.Tables) error {
+		stored := tables.SubscriptionByID(id)
+		if stored == nil || !stored.Active {
+			return domain.Errorf(domain.ErrWebhookNotFound, "webhook subscription not found with id %s", id)
+		}
+
+		now := time.Now()
+		stored.Active, stored.UpdatedAt = false, now
+		for _, delivery := range tables.Deliveries {
+			if delivery.SubscriptionID == id && delivery.Status == webhook.StatusPending {
+				delivery.Status, delivery.UpdatedAt = webhook.StatusFailed, now
+			}
+		}
+		return nil
+	})
+}
+
+// EnqueueDeliveries schedules an event for immediate delivery to each subscription.
+// Enqueuing the same event twice is a no-op, so the outbox may safely redeliver it.
+func (repo *memoryWebhookRepository) EnqueueDeliveries(ctx context.Context, eventID int64, subscriptionIDs []string) error {
+	if len(subscriptionIDs) == 0 {
+		return nil
+	}
+
+	return repo.store.Update(ctx, func(tables *memory.Tables) error {
+		if tables.EventByID(eventID) == nil {
+			return errors.New(`failed to enqueue delivery: insert or update on table "webhook_deliveries" violates foreign key constraint`)
+		}
+		for _, subscriptionID := range subscriptionIDs {
+			if tables.SubscriptionByID(subscriptionID) == nil {
+				return errors.New(`failed to enqueue delivery: insert or update on table "webhook_deliveries" violates foreign key constraint`)
+			}
+		}
+
+		now := time.Now()
+		for _, subscriptionID := range subscriptionIDs {
+			enqueued := slices.ContainsFunc(tables.Deliveries, func(d *memory.Delivery) bool {
+				return d.SubscriptionID == subscriptionID && d.EventID == eventID
+			})
+			if enqueued {
+				continue
+			}
+			tables.Deliveries = append(tables.Deliveries, &memory.Delivery{
+				ID:             uuid.New().String(),
+				SubscriptionID: subscriptionID,
+				EventID:        eventID,
+				Status:         webhook.StatusPending,
+				NextAttemptAt:  now,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			})
+		}
+		return nil
+	})
+}
+
+// ClaimDueDeliveries leases up to limit pending deliveries that are due, together with their
+// subscription and event. A claimed delivery that is not recorded before the lease expires is retried.
+func (repo *memoryWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*webhook.PendingDelivery, error) {
+	deliveries := make([]*webhook.PendingDelivery, 0, limit)
+	err := repo.store.Update(ctx, func(tables *memory.Tables) error {
+		now := time.Now()
+		var due []*memory.Delivery
+		for _, delivery := range tables.Deliveries {
+			if delivery.Status == webhook.StatusPending && !delivery.NextAttemptAt.After(now) {
+				due = append(due, delivery)
+			}
+		}
+		slices.SortStableFunc(due, func(a, b *memory.Delivery) int { return a.NextAttemptAt.Compare(b.NextAttemptAt) })
+
+		for _, delivery := range due[:max(min(limit, len(due)), 0)] {
+			delivery.Attempts++
+			delivery.NextAttemptAt, delivery.UpdatedAt = now.Add(lease), now
+			subscription, e := tables.SubscriptionByID(delivery.SubscriptionID), tables.EventByID(delivery.EventID)
+			deliveries = append(deliveries, &webhook.PendingDelivery{
+				ID:       delivery.ID,
+				Attempts: delivery.Attempts,
+				URL:      subscription.URL,
+				Secret:   subscription.Secret,
+				Event:    &event.Event{ID: e.ID, Type: e.EventType, Data: e.Payload, CreatedAt: e.CreatedAt},
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt appends an attempt to the delivery history and moves the delivery to its new status.
+func (repo *memoryWebhookRepository) RecordAttempt(ctx context.Context, deliveryID string, attempt *webhook.DeliveryAttempt, status string, nextAttemptAt time.Time) error {
+	return repo.store.Update(ctx, func(tables *memory.Tables) error {
+		delivery := tables.DeliveryByID(deliveryID)
+		if delivery == nil {
+			return errors.New(`failed to record delivery attempt: insert or update on table "webhook_delivery_attempts" violates foreign key constraint`)
+		}
+		delivery.History = append(delivery.History, &memory.Attempt{
+			Attempt:     attempt.Attempt,
+			StatusCode:  attempt.StatusCode,
+			Error:       attempt.Error,
+			DurationMs:  attempt.DurationMs,
+			AttemptedAt: attempt.AttemptedAt,
+		})
+		delivery.Status, delivery.LastStatusCode = status, attempt.StatusCode
+		delivery.NextAttemptAt, delivery.UpdatedAt = nextAttemptAt, time.Now()
+		return nil
+	})
+}
+
+// ListDeliveries retrieves the most recent deliveries of a subscription with their attempt history.
+func (repo *memoryWebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*webhook.Delivery, error) {
+	deliveries := make([]*webhook.Delivery, 0)
+	err := repo.store.View(ctx, func(tables *memory.Tables) error {
+		for _, stored := range tables.Deliveries {
+			if stored.SubscriptionID != subscriptionID {
+				continue
+			}
+			delivery := &webhook.Delivery{
+				ID:             stored.ID,
+				SubscriptionID: stored.SubscriptionID,
+				EventID:        stored.EventID,
+				EventType:      tables.EventByID(stored.EventID).EventType,
+				Status:         stored.Status,
+				Attempts:       stored.Attempts,
+				LastStatusCode: stored.LastStatusCode,
+				NextAttemptAt:  stored.NextAttemptAt,
+				CreatedAt:      stored.CreatedAt,
+				History:        make([]*webhook.DeliveryAttempt, 0, len(stored.History)),
+			}
+			for _, attempt := range stored.History {
+				delivery.History = append(delivery.History, &webhook.DeliveryAttempt{
+					Attempt:     attempt.Attempt,
+					StatusCode:  attempt.StatusCode,
+					Error:       attempt.Error,
+					DurationMs:  attempt.DurationMs,
+					AttemptedAt: attempt.AttemptedAt,
+				})
+			}
+			slices.SortStableFunc(delivery.History, func(a, b *webhook.DeliveryAttempt) int { return cmp.Compare(a.Attempt, b.Attempt) })
+			deliveries = append(deliveries, delivery)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deliveries: %w", err)
+	}
+
+	slices.SortStableFunc(deliveries, func(a, b *webhook.Delivery) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return deliveries[:max(min(limit, len(deliveries)), 0)], nil
+}
@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/memory"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMemoryWebhooks stores an event and an active subscription to every event, sub-1.
+func newMemoryWebhooks(t *testing.T) (WebhookRepository, int64) {
+	store := memory.NewStore()
+	var eventID int64
+	require.NoError(t, store.Update(context.Background(), func(tables *memory.Tables) error {
+		eventID = tables.AppendEvent("block.created", []byte(`{"requestor":"a@example.com"}`), time.Now()).ID
+		return nil
+	}))
+
+	repo := NewMemoryWebhookRepository(store)
+	require.NoError(t, repo.CreateSubscription(context.Background(), &webhook.Subscription{
+		ID: "sub-1", URL: "https://example.com/hook", Events: []string{webhook.AllEvents}, Secret: "s3cr3t", Active: true, CreatedAt: time.Now(),
+	}))
+	return repo, eventID
+}
+
+// Test that subscriptions are served without their secret until they are deactivated.
+func TestMemoryWebhookRepository_Subscriptions(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newMemoryWebhooks(t)
+
+	subscription, err := repo.GetSubscription(ctx, "sub-1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook", subscription.URL)
+	assert.Empty(t, subscription.Secret)
+	subscriptions, err := repo.ListSubscriptions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+
+	require.NoError(t, repo.DeactivateSubscription(ctx, "sub-1"))
+
+	_, err = repo.GetSubscription(ctx, "sub-1")
+	assert.ErrorIs(t, err, domain.ErrWebhookNotFound)
+	assert.ErrorIs(t, repo.DeactivateSubscription(ctx, "sub-1"), domain.ErrWebhookNotFound)
+	subscriptions, err = repo.ListSubscriptions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, subscriptions)
+}
+
+// Test that a delivery is enqueued once, claimed with its endpoint and event, leased,
+// and listed with the attempts recorded for it.
+func TestMemoryWebhookRepository_Deliveries(t *testing.T) {
+	ctx := context.Background()
+	repo, eventID := newMemoryWebhooks(t)
+
+	require.NoError(t, repo.EnqueueDeliveries(ctx, eventID, []string{"sub-1"}))
+	require.NoError(t, repo.EnqueueDeliveries(ctx, eventID, []string{"sub-1"}))
+	assert.Error(t, repo.EnqueueDeliveries(ctx, eventID, []string{"unknown"}))
+
+	deliveries, err := repo.ClaimDueDeliveries(ctx, 20, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, "s3cr3t", deliveries[0].Secret)
+	assert.Equal(t, eventID, deliveries[0].Event.ID)
+	assert.Equal(t, "block.created", deliveries[0].Event.Type)
+
+	claimed, err := repo.ClaimDueDeliveries(ctx, 20, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	statusCode := 503
+	attempt := &webhook.DeliveryAttempt{Attempt: 1, StatusCode: &statusCode, Error: "unexpected status 503", DurationMs: 12, AttemptedAt: time.Now()}
+	require.NoError(t, repo.RecordAttempt(ctx, deliveries[0].ID, attempt, webhook.StatusPending, time.Now().Add(-time.Second)))
+	assert.Error(t, repo.RecordAttempt(ctx, "unknown", attempt, webhook.StatusPending, time.Now()))
+
+	listed, err := repo.ListDeliveries(ctx, "sub-1", 50)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, webhook.StatusPending, listed[0].Status)
+	assert.Equal(t, 503, *listed[0].LastStatusCode)
+	require.Len(t, listed[0].History, 1)
+	assert.Equal(t, "unexpected status 503", listed[0].History[0].Error)
+
+	// Deactivating the subscription fails its pending deliveries.
+	require.NoError(t, repo.DeactivateSubscription(ctx, "sub-1"))
+	claimed, err = repo.ClaimDueDeliveries(ctx, 20, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+	listed, err = repo.ListDeliveries(ctx, "sub-1", 50)
+	require.NoError(t, err)
+	assert.Equal(t, webhook.StatusFailed, listed[0].Status)
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/postgres"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+)
+
+// retryingWebhookRepository retries the reads of the wrapped repository that fail with a
+// transient error, such as a serialization failure or a connection reset. Writes are passed
+// through as is, since they may have been applied before the error.
+type retryingWebhookRepository struct {
+	next   WebhookRepository
+	policy postgres.RetryPolicy
+}
+
+// NewRetryingWebhookRepository wraps repo so its reads are retried as configured by cfg.
+func NewRetryingWebhookRepository(repo WebhookRepository, cfg *config.DBConfig) WebhookRepository {
+	return &retryingWebhookRepository{next: repo, policy: postgres.NewReadRetryPolicy(cfg)}
+}
+
+func (r *retryingWebhookRepository) CreateSubscription(ctx context.Context, subscription *webhook.Subscription) error {
+	return r.next.CreateSubscription(ctx, subscription)
+}
+
+func (r *retryingWebhookRepository) GetSubscription(ctx context.Context, id string) (result *webhook.Subscription, err error) {
+	err = r.policy.Do(ctx, func() error {
+		result, err = r.next.GetSubscription(ctx, id)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingWebhookRepository) ListSubscriptions(ctx context.Context) (result []*webhook.Subscription, err error) {
+	err = r.policy.Do(ctx, func() error {
+		result, err = r.next.ListSubscriptions(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (r *retryingWebhookRepository) DeactivateSubscription(ctx context.Context, id string) error {
+	return r.next.DeactivateSubscription(ctx, id)
+}
+
+func (r *retryingWebhookRepository) EnqueueDeliveries(ctx context.Context, eventID int64, subscriptionIDs []string) error {
+	return r.next.EnqueueDeliveries(ctx, eventID, subscriptionIDs)
+}
+
+func (r *retryingWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*webhook.PendingDelivery, error) {
+	return r.next.ClaimDueDeliveries(ctx, limit, lease)
+}
+
+func (r *retryingWebhookRepository) RecordAttempt(ctx context.Context, deliveryID string, attempt *webhook.DeliveryAttempt, status string, nextAttemptAt time.Time) error {
+	return r.next.RecordAttempt(ctx, deliveryID, attempt, status, nextAttemptAt)
+}
+
+func (r *retryingWebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit int) (result []*webhook.Delivery, err error) {
+	err = r.policy.Do(ctx, func() error {
+		result, err = r.next.ListDeliveries(ctx, subscriptionID, limit)
+		return err
+	})
+	return result, err
+}
@@ -0,0 +1,302 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/event"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+)
+
+// WebhookRepository defines the interface for webhook subscriptions and their delivery queue.
+type WebhookRepository interface {
+	// Subscription
+	CreateSubscription(ctx context.Context, subscription *webhook.Subscription) error
+	GetSubscription(ctx context.Context, id string) (*webhook.Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]*webhook.Subscription, error)
+	DeactivateSubscription(ctx context.Context, id string) error
+
+	// Delivery
+	EnqueueDeliveries(ctx context.Context, eventID int64, subscriptionIDs []string) error
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*webhook.PendingDelivery, error)
+	RecordAttempt(ctx context.Context, deliveryID string, attempt *webhook.DeliveryAttempt, status string, nextAttemptAt time.Time) error
+	ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*webhook.Delivery, error)
+}
+
+// webhookRepositoryImpl is the implementation of the WebhookRepository interface.
+type webhookRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository.
+func NewWebhookRepository(db *sql.DB) WebhookRepository {
+	return &webhookRepositoryImpl{db: db}
+}
+
+// CreateSubscription stores a new active webhook subscription.
+func (repo *webhookRepositoryImpl) CreateSubscription(ctx context.Context, subscription *webhook.Subscription) error {
+	events, err := json.Marshal(subscription.Events)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.db.ExecContext(ctx, `
+    INSERT INTO webhook_subscriptions (id, url, secret, events, active, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $6)`,
+		subscription.ID, subscription.URL, subscription.Secret, events, subscription.Active, subscription.CreatedAt)
+	return err
+}
+
+// GetSubscription retrieves an active subscription by id, without its secret.
+// It returns domain.ErrWebhookNotFound when no active subscription exists.
+func (repo *webhookRepositoryImpl) GetSubscription(ctx context.Context, id string) (*webhook.Subscription, error) {
+	row := repo.db.QueryRowContext(ctx, `
+    SELECT id, url, events, active, created_at
+    FROM webhook_subscriptions
+    WHERE id = $1 AND active`, id)
+	subscription, err := scanSubscription(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.Errorf(domain.ErrWebhookNotFound, "webhook subscription not found with id %s", id)
+	}
+	return subscription, err
+}
+
+// ListSubscriptions retrieves every active subscription, without secrets.
+func (repo *webhookRepositoryImpl) ListSubscriptions(ctx context.Context) ([]*webhook.Subscription, error) {
+	rows, err := repo.db.QueryContext(ctx, `
+    SELECT id, url, events, active, created_at
+    FROM webhook_subscriptions
+    WHERE active
+    ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*webhook.Subscription, 0)
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// scanSubscription scans the columns selected by GetSubscription and ListSubscriptions.
+func scanSubscription(row interface{ Scan(...interface{}) error }) (*webhook.Subscription, error) {
+	var subscription webhook.Subscription
+	var events []byte
+	if err := row.Scan(&subscription.ID, &subscription.URL, &events, &subscription.Active, &subscription.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(events, &subscription.Events); err != nil {
+		return nil, fmt.Errorf("failed to decode subscription events: %w", err)
+	}
+	return &subscription, nil
+}
+
+// DeactivateSubscription stops deliveries to a subscription while keeping its delivery history.
+// It returns domain.ErrWebhookNotFound when no active subscription exists.
+func (repo *webhookRepositoryImpl) DeactivateSubscription(ctx context.Context, id string) error {
+	result, err := repo.db.ExecContext(ctx, `
+    UPDATE webhook_subscriptions SET active = FALSE, updated_at = $2
+    WHERE id = $1 AND active`, id, time.Now())
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.Errorf(domain.ErrWebhookNotFound, "webhook subscription not found with id %s", id)
+	}
+
+	_, err = repo.db.ExecContext(ctx, `
+    UPDATE webhook_deliveries SET status = $2, updated_at = $3
+    WHERE subscription_id = $1 AND status = $4`, id, webhook.StatusFailed, time.Now(), webhook.StatusPending)
+	return err
+}
+
+// EnqueueDeliveries schedules an event for immediate delivery to each subscription.
+// Enqueuing the same event twice is a no-op, so the outbox may safely redeliver it.
+func (repo *webhookRepositoryImpl) EnqueueDeliveries(ctx context.Context, eventID int64, subscriptionIDs []string) error {
+	if len(subscriptionIDs) == 0 {
+		return nil
+	}
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, subscriptionID := range subscriptionIDs {
+		_, err := tx.ExecContext(ctx, `
+    INSERT INTO webhook_deliveries (id, subscription_id, event_id, status, attempts, next_attempt_at, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, 0, $5, $5, $5)
+    ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+			uuid.New().String(), subscriptionID, eventID, webhook.StatusPending, now)
+		if err != nil {
+			return fmt.Errorf("failed to enqueue delivery: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ClaimDueDeliveries leases up to limit pending deliveries that are due, together with their
+// subscription and event. A claimed delivery that is not recorded before the lease expires is retried.
+func (repo *webhookRepositoryImpl) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*webhook.PendingDelivery, error) {
+	query := `
+    WITH claimed AS (
+        UPDATE webhook_deliveries
+        SET attempts = attempts + 1, next_attempt_at = $2, updated_at = $1
+        WHERE id IN (
+            SELECT id FROM webhook_deliveries
+            WHERE status = $3 AND next_attempt_at <= $1
+            ORDER BY next_attempt_at
+            LIMIT $4
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, subscription_id, event_id, attempts
+    )
+    SELECT c.id, c.attempts, s.url, s.secret, e.id, e.event_type, e.payload, e.created_at
+    FROM claimed c
+    JOIN webhook_subscriptions s ON s.id = c.subscription_id
+    JOIN relationship_events e ON e.id = c.event_id`
+
+	now := time.Now()
+	rows, err := repo.db.QueryContext(ctx, query, now, now.Add(lease), webhook.StatusPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*webhook.PendingDelivery, 0, limit)
+	for rows.Next() {
+		delivery := webhook.PendingDelivery{Event: &event.Event{}}
+		if err := rows.Scan(&delivery.ID, &delivery.Attempts, &delivery.URL, &delivery.Secret,
+			&delivery.Event.ID, &delivery.Event.Type, &delivery.Event.Data, &delivery.Event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt appends an attempt to the delivery history and moves the delivery to its new status.
+func (repo *webhookRepositoryImpl) RecordAttempt(ctx context.Context, deliveryID string, attempt *webhook.DeliveryAttempt, status string, nextAttemptAt time.Time) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var attemptErr sql.NullString
+	if attempt.Error != "" {
+		attemptErr = sql.NullString{String: attempt.Error, Valid: true}
+	}
+	_, err = tx.ExecContext(ctx, `
+    INSERT INTO webhook_delivery_attempts (delivery_id, attempt, status_code, error, duration_ms, attempted_at)
+    VALUES ($1, $2, $3, $4, $5, $6)`,
+		deliveryID, attempt.Attempt, attempt.StatusCode, attemptErr, attempt.DurationMs, attempt.AttemptedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record delivery attempt: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+    UPDATE webhook_deliveries
+    SET status = $2, last_status_code = $3, next_attempt_at = $4, updated_at = $5
+    WHERE id = $1`,
+		deliveryID, status, attempt.StatusCode, nextAttemptAt, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update delivery: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// ListDeliveries retrieves the most recent deliveries of a subscription with their attempt history.
+func (repo *webhookRepositoryImpl) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*webhook.Delivery, error) {
+	query := `
+    WITH recent AS (
+        SELECT d.id, d.subscription_id, d.event_id, e.event_type, d.status, d.attempts,
+               d.last_status_code, d.next_attempt_at, d.created_at
+        FROM webhook_deliveries d
+        JOIN relationship_events e ON e.id = d.event_id
+        WHERE d.subscription_id = $1
+        ORDER BY d.created_at DESC
+        LIMIT $2
+    )
+    SELECT r.id, r.subscription_id, r.event_id, r.event_type, r.status, r.attempts,
+           r.last_status_code, r.next_attempt_at, r.created_at,
+           a.attempt, a.status_code, a.error, a.duration_ms, a.attempted_at
+    FROM recent r
+    LEFT JOIN webhook_delivery_attempts a ON a.delivery_id = r.id
+    ORDER BY r.created_at DESC, r.id, a.attempt`
+
+	rows, err := repo.db.QueryContext(ctx, query, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*webhook.Delivery, 0)
+	var current *webhook.Delivery
+	for rows.Next() {
+		var delivery webhook.Delivery
+		var lastStatusCode, attemptNumber, statusCode sql.NullInt64
+		var durationMs sql.NullInt64
+		var attemptErr sql.NullString
+		var attemptedAt sql.NullTime
+		if err := rows.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType,
+			&delivery.Status, &delivery.Attempts, &lastStatusCode, &delivery.NextAttemptAt, &delivery.CreatedAt,
+			&attemptNumber, &statusCode, &attemptErr, &durationMs, &attemptedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+
+		if current == nil || current.ID != delivery.ID {
+			delivery.LastStatusCode = nullableInt(lastStatusCode)
+			delivery.History = make([]*webhook.DeliveryAttempt, 0)
+			current = &delivery
+			deliveries = append(deliveries, current)
+		}
+		if attemptNumber.Valid {
+			current.History = append(current.History, &webhook.DeliveryAttempt{
+				Attempt:     int(attemptNumber.Int64),
+				StatusCode:  nullableInt(statusCode),
+				Error:       attemptErr.String,
+				DurationMs:  durationMs.Int64,
+				AttemptedAt: attemptedAt.Time,
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return deliveries, nil
+}
+
+func nullableInt(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	i := int(value.Int64)
+	return &i
+}
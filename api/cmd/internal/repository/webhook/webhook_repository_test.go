@@ -0,0 +1,175 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/koeylp/friends-management/cmd/internal/model/domain"
+	"github.com/koeylp/friends-management/cmd/internal/model/dto/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateSubscription tests that subscriptions are stored with their event filter as JSON.
+func TestCreateSubscription(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+	subscription := &webhook.Subscription{
+		ID:        "sub-1",
+		URL:       "https://example.com/hook",
+		Events:    []string{"friend.created", "block.created"},
+		Secret:    "s3cr3t",
+		Active:    true,
+		CreatedAt: time.Now(),
+	}
+
+	mock.ExpectExec(`INSERT INTO webhook_subscriptions`).
+		WithArgs("sub-1", "https://example.com/hook", "s3cr3t", []byte(`["friend.created","block.created"]`), true, subscription.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.CreateSubscription(context.Background(), subscription))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestListSubscriptions tests that active subscriptions are decoded without secrets.
+func TestListSubscriptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+
+	mock.ExpectQuery(`SELECT id, url, events, active, created_at\s+FROM webhook_subscriptions\s+WHERE active`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "events", "active", "created_at"}).
+			AddRow("sub-1", "https://example.com/hook", []byte(`["*"]`), true, time.Now()))
+
+	subscriptions, err := repo.ListSubscriptions(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	assert.Equal(t, []string{"*"}, subscriptions[0].Events)
+	assert.Empty(t, subscriptions[0].Secret)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDeactivateSubscription_NotFound tests that deactivating an unknown subscription reports domain.ErrWebhookNotFound.
+func TestDeactivateSubscription_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+
+	mock.ExpectExec(`UPDATE webhook_subscriptions SET active = FALSE`).
+		WithArgs("missing", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.DeactivateSubscription(context.Background(), "missing"), domain.ErrWebhookNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestEnqueueDeliveries tests that one idempotent delivery row is inserted per subscription.
+func TestEnqueueDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO webhook_deliveries .* ON CONFLICT \(subscription_id, event_id\) DO NOTHING`).
+		WithArgs(sqlmock.AnyArg(), "sub-1", int64(9), webhook.StatusPending, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO webhook_deliveries`).
+		WithArgs(sqlmock.AnyArg(), "sub-2", int64(9), webhook.StatusPending, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.EnqueueDeliveries(context.Background(), 9, []string{"sub-1", "sub-2"}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestClaimDueDeliveries tests that claimed deliveries carry their endpoint, secret and event.
+func TestClaimDueDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+
+	mock.ExpectQuery(`WITH claimed AS \(\s+UPDATE webhook_deliveries`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), webhook.StatusPending, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "attempts", "url", "secret", "event_id", "event_type", "payload", "created_at"}).
+			AddRow("del-1", 2, "https://example.com/hook", "s3cr3t", 9, "block.created", []byte(`{"requestor":"a@example.com"}`), time.Now()))
+
+	deliveries, err := repo.ClaimDueDeliveries(context.Background(), 20, time.Minute)
+
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, "del-1", deliveries[0].ID)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.Equal(t, "s3cr3t", deliveries[0].Secret)
+	assert.Equal(t, int64(9), deliveries[0].Event.ID)
+	assert.Equal(t, "block.created", deliveries[0].Event.Type)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRecordAttempt tests that an attempt is appended to the history and the delivery status updated together.
+func TestRecordAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+	statusCode := 503
+	attempt := &webhook.DeliveryAttempt{Attempt: 1, StatusCode: &statusCode, Error: "unexpected status 503", DurationMs: 12, AttemptedAt: time.Now()}
+	retryAt := time.Now().Add(time.Second)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO webhook_delivery_attempts`).
+		WithArgs("del-1", 1, &statusCode, sqlmock.AnyArg(), int64(12), attempt.AttemptedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE webhook_deliveries\s+SET status = \$2`).
+		WithArgs("del-1", webhook.StatusPending, &statusCode, retryAt, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.RecordAttempt(context.Background(), "del-1", attempt, webhook.StatusPending, retryAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestListDeliveries tests that joined attempt rows are grouped under their delivery.
+func TestListDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+	now := time.Now()
+	columns := []string{"id", "subscription_id", "event_id", "event_type", "status", "attempts", "last_status_code", "next_attempt_at", "created_at",
+		"attempt", "status_code", "error", "duration_ms", "attempted_at"}
+
+	mock.ExpectQuery(`WITH recent AS`).
+		WithArgs("sub-1", 50).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("del-2", "sub-1", 10, "friend.created", "pending", 0, nil, now, now, nil, nil, nil, nil, nil).
+			AddRow("del-1", "sub-1", 9, "block.created", "succeeded", 2, 200, now, now, 1, 500, "unexpected status 500", 30, now).
+			AddRow("del-1", "sub-1", 9, "block.created", "succeeded", 2, 200, now, now, 2, 200, nil, 25, now))
+
+	deliveries, err := repo.ListDeliveries(context.Background(), "sub-1", 50)
+
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Empty(t, deliveries[0].History)
+	assert.Nil(t, deliveries[0].LastStatusCode)
+	require.Len(t, deliveries[1].History, 2)
+	assert.Equal(t, 200, *deliveries[1].LastStatusCode)
+	assert.Equal(t, "unexpected status 500", deliveries[1].History[0].Error)
+	assert.Equal(t, 200, *deliveries[1].History[1].StatusCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,24 +1,53 @@
 package main
 
 import (
-	"database/sql"
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
 
 	"github.com/go-chi/chi/v5"
+	graphCtrl "github.com/koeylp/friends-management/cmd/internal/controller/graph"
 	relationshipCtrl "github.com/koeylp/friends-management/cmd/internal/controller/relationship"
 	userCtrl "github.com/koeylp/friends-management/cmd/internal/controller/user"
+	webhookCtrl "github.com/koeylp/friends-management/cmd/internal/controller/webhook"
+	"github.com/koeylp/friends-management/cmd/internal/handler/gql"
 	handler "github.com/koeylp/friends-management/cmd/internal/handler/rest"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/middleware"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rpc"
+	"github.com/koeylp/friends-management/cmd/internal/infra/cache"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/memory"
+	"github.com/koeylp/friends-management/cmd/internal/infra/database/postgres"
+	"github.com/koeylp/friends-management/cmd/internal/infra/health"
+	"github.com/koeylp/friends-management/cmd/internal/infra/logging"
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/koeylp/friends-management/cmd/internal/infra/outbox"
+	"github.com/koeylp/friends-management/cmd/internal/infra/stream"
+	"github.com/koeylp/friends-management/cmd/internal/infra/tracing"
+	"github.com/koeylp/friends-management/cmd/internal/infra/webhook"
+	graphRepo "github.com/koeylp/friends-management/cmd/internal/repository/graph"
+	outboxRepo "github.com/koeylp/friends-management/cmd/internal/repository/outbox"
 	relationshipRepo "github.com/koeylp/friends-management/cmd/internal/repository/relationship"
 	userRepo "github.com/koeylp/friends-management/cmd/internal/repository/user"
+	webhookRepo "github.com/koeylp/friends-management/cmd/internal/repository/webhook"
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"google.golang.org/grpc"
 )
 
 func NewRouter() *chi.Mux {
 	return chi.NewRouter()
 }
 
-func RegisterRoutes(r *chi.Mux, userHandler *handler.UserHandler, relationshipHandler *handler.RelationshipHandler) {
+func RegisterRoutes(r *chi.Mux, userHandler *handler.UserHandler, relationshipHandler *handler.RelationshipHandler, graphHandler *handler.GraphHandler, webhookHandler *handler.WebhookHandler, streamHandler *handler.StreamHandler, docsHandler *handler.DocsHandler, graphQLHandler *handler.GraphQLHandler, metricsHandler *handler.MetricsHandler, healthHandler *handler.HealthHandler, requestMetrics *middleware.RequestMetrics, requestLogger *middleware.RequestLogger, requestValidator *middleware.RequestValidator) {
+	r.Use(middleware.Trace, requestMetrics.Middleware, requestLogger.Middleware, requestValidator.Middleware)
+	r.Get("/metrics", metricsHandler.MetricsHandler)
+	r.Get("/healthz", healthHandler.LivenessHandler)
+	r.Get("/readyz", healthHandler.ReadinessHandler)
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Route("/users", func(r chi.Router) {
 			r.Post("/", userHandler.CreateUserHandler)
@@ -28,36 +57,214 @@ func RegisterRoutes(r *chi.Mux, userHandler *handler.UserHandler, relationshipHa
 			r.Post("/list", relationshipHandler.GetFriendListByEmailHandler)
 			r.Post("/common-list", relationshipHandler.GetCommonListHandler)
 		})
-		r.Route("/subcription", func(r chi.Router) {
-			r.Post("/", relationshipHandler.SubscribeHandler)
-			r.Post("/recipients", relationshipHandler.GetUpdatableEmailAddressesHandler)
-		})
+		// "/subcription" is the original, misspelled path; it is kept for existing clients.
+		for _, path := range []string{"/subscription", "/subcription"} {
+			r.Route(path, func(r chi.Router) {
+				r.Post("/", relationshipHandler.SubscribeHandler)
+				r.Post("/recipients", relationshipHandler.GetUpdatableEmailAddressesHandler)
+			})
+		}
+		r.Post("/updates", relationshipHandler.PostUpdateHandler)
 		r.Route("/block", func(r chi.Router) {
 			r.Post("/", relationshipHandler.BlockUpdatesHandler)
 		})
+		r.Route("/graph", func(r chi.Router) {
+			r.Post("/export", graphHandler.ExportGraphHandler)
+		})
+		r.Post("/graphql", graphQLHandler.QueryHandler)
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Post("/", webhookHandler.RegisterWebhookHandler)
+			r.Get("/", webhookHandler.ListWebhooksHandler)
+			r.Delete("/{id}", webhookHandler.UnregisterWebhookHandler)
+			r.Get("/{id}/deliveries", webhookHandler.ListWebhookDeliveriesHandler)
+		})
+		r.Route("/events", func(r chi.Router) {
+			r.Get("/stream", streamHandler.StreamEventsHandler)
+		})
+		r.Get("/openapi.json", docsHandler.OpenAPIHandler)
+		r.Get("/docs", docsHandler.DocsUIHandler)
+	})
+	r.Route("/api/v2", func(r chi.Router) {
+		r.Route("/users/{email}", func(r chi.Router) {
+			r.Get("/friends", relationshipHandler.ListFriendsHandler)
+			r.Get("/common-friends/{other}", relationshipHandler.ListCommonFriendsHandler)
+			r.Put("/subscriptions/{target}", relationshipHandler.PutSubscriptionHandler)
+			r.Delete("/subscriptions/{target}", relationshipHandler.DeleteSubscriptionHandler)
+			r.Put("/blocks/{target}", relationshipHandler.PutBlockHandler)
+			r.Delete("/blocks/{target}", relationshipHandler.DeleteBlockHandler)
+		})
 	})
 }
 
 var Module = fx.Options(
 	fx.Provide(
 		NewRouter,
-		userRepo.NewUserRepository,
-		relationshipRepo.NewRelationshipRepository,
+		config.GetLogConfig,
+		logging.NewLogger,
+		metrics.New,
+		config.GetTraceConfig,
+		config.GetDBConfig,
+		config.GetOutboxConfig,
+		config.GetWebhookConfig,
+		config.GetStreamConfig,
+		config.GetServerConfig,
 		userCtrl.NewUserController,
 		relationshipCtrl.NewRelationshipController,
+		graphCtrl.NewGraphController,
+		webhookCtrl.NewWebhookController,
 		handler.NewUserHandler,
 		handler.NewRelationshipHandler,
+		handler.NewGraphHandler,
+		handler.NewWebhookHandler,
+		handler.NewStreamHandler,
+		handler.NewDocsHandler,
+		handler.NewGraphQLHandler,
+		handler.NewMetricsHandler,
+		handler.NewHealthHandler,
+		gql.NewSchema,
+		middleware.NewRequestMetrics,
+		middleware.NewRequestLogger,
+		middleware.NewRequestValidator,
+		rpc.NewUserServer,
+		rpc.NewRelationshipServer,
+		rpc.NewServer,
+		stream.NewHub,
+		fx.Annotate(func(hub *stream.Hub) outbox.Sink { return hub }, fx.ResultTags(`group:"outbox_sinks"`)),
+		fx.Annotate(outbox.NewConfiguredSinks, fx.ResultTags(`group:"outbox_sinks,flatten"`)),
+		fx.Annotate(webhook.NewSink, fx.As(new(outbox.Sink)), fx.ResultTags(`group:"outbox_sinks"`)),
+		fx.Annotate(outbox.NewDispatcher, fx.ParamTags(``, `group:"outbox_sinks"`)),
+		webhook.NewDeliverer,
+	),
+	fx.Decorate(
+		userRepo.NewInstrumentedUserRepository,
+		relationshipRepo.NewInstrumentedRelationshipRepository,
+		graphRepo.NewInstrumentedGraphRepository,
+		outboxRepo.NewInstrumentedOutboxRepository,
+		webhookRepo.NewInstrumentedWebhookRepository,
+		relationshipCtrl.NewTracedRelationshipController,
 	),
-	fx.Invoke(RegisterRoutes),
+	fx.Invoke(slog.SetDefault, tracing.Register, RegisterRoutes, outbox.RegisterDispatcher, webhook.RegisterDeliverer),
 )
 
-func StartServer(db *sql.DB) {
+// PostgresStore provides the repositories over PostgreSQL, which is opened first and closed last.
+var PostgresStore = fx.Options(
+	fx.Provide(
+		postgres.NewDB,
+		health.NewChecker,
+		config.GetCacheConfig,
+		cache.New,
+		// Reads are retried on transient errors around the repositories that serve them,
+		// and relationship reads are cached when a cache backend is configured.
+		postgres.OpenReadRouter,
+		fx.Annotate(userRepo.NewRoutedUserRepository, fx.ResultTags(`name:"store"`)),
+		fx.Annotate(userRepo.NewRetryingUserRepository, fx.ParamTags(`name:"store"`)),
+		fx.Annotate(relationshipRepo.NewRoutedRelationshipRepository, fx.ResultTags(`name:"store"`)),
+		fx.Annotate(relationshipRepo.NewRetryingRelationshipRepository, fx.ParamTags(`name:"store"`), fx.ResultTags(`name:"retrying"`)),
+		fx.Annotate(relationshipRepo.NewCachedRelationshipRepository, fx.ParamTags(`name:"retrying"`)),
+		graphRepo.NewGraphRepository,
+		outboxRepo.NewOutboxRepository,
+		fx.Annotate(webhookRepo.NewWebhookRepository, fx.ResultTags(`name:"store"`)),
+		fx.Annotate(webhookRepo.NewRetryingWebhookRepository, fx.ParamTags(`name:"store"`)),
+	),
+	fx.Invoke((*metrics.Metrics).RegisterDB),
+)
+
+// MemoryStore provides the repositories over one in-memory store, for tests and demos.
+var MemoryStore = fx.Options(
+	fx.Provide(
+		memory.NewStore,
+		health.NewServingChecker,
+		userRepo.NewMemoryUserRepository,
+		relationshipRepo.NewMemoryRelationshipRepository,
+		graphRepo.NewMemoryGraphRepository,
+		outboxRepo.NewMemoryOutboxRepository,
+		webhookRepo.NewMemoryWebhookRepository,
+	),
+)
+
+// Store provides the repositories over the store named by the configuration.
+func Store(cfg *config.StoreConfig) fx.Option {
+	switch cfg.Backend {
+	case "postgres":
+		return PostgresStore
+	case "memory":
+		return MemoryStore
+	default:
+		return fx.Error(fmt.Errorf("unknown store backend %q: use postgres or memory", cfg.Backend))
+	}
+}
+
+// newEventLogger logs the application's own lifecycle through the application logger.
+// Dependency wiring is only logged at debug level, while errors keep the error level.
+func newEventLogger(logger *slog.Logger) fxevent.Logger {
+	eventLogger := &fxevent.SlogLogger{Logger: logger}
+	eventLogger.UseLogLevel(slog.LevelDebug)
+	return eventLogger
+}
+
+// RegisterServer serves the router for the lifetime of the application and shuts it down gracefully on stop.
+// Open event streams are closed first, since they would otherwise hold the shutdown open.
+func RegisterServer(lc fx.Lifecycle, r *chi.Mux, hub *stream.Hub, logger *slog.Logger) {
+	srv := &http.Server{Addr: ":8080", Handler: r}
+	srv.RegisterOnShutdown(hub.Close)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Error("HTTP server failed", "error", err)
+					os.Exit(1)
+				}
+			}()
+			return nil
+		},
+		OnStop: srv.Shutdown,
+	})
+}
+
+// RegisterGRPCServer serves the gRPC API alongside the HTTP server for the lifetime of the application.
+// In-flight calls are allowed to finish on stop until the stop context expires.
+func RegisterGRPCServer(lc fx.Lifecycle, server *grpc.Server, cfg *config.ServerConfig, logger *slog.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", cfg.GRPCAddr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", cfg.GRPCAddr, err)
+			}
+			go func() {
+				if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+					logger.Error("gRPC server failed", "error", err)
+					os.Exit(1)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stopped := make(chan struct{})
+			go func() {
+				server.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+				return nil
+			case <-ctx.Done():
+				server.Stop()
+				return ctx.Err()
+			}
+		},
+	})
+}
+
+// StartServer runs the application until it is signalled to stop, over the store configured
+// by STORE_BACKEND. A database is opened first and closed last; the application exits with
+// status 1 when it cannot be reached.
+func StartServer() {
 	app := fx.New(
 		Module,
-		fx.Supply(db),
-		fx.Invoke(func(r *chi.Mux) {
-			log.Fatal(http.ListenAndServe(":8080", r))
-		}),
+		Store(config.GetStoreConfig()),
+		fx.WithLogger(newEventLogger),
+		// The checker's stop hook must run first, so it is registered after the servers.
+		fx.Invoke(RegisterServer, RegisterGRPCServer, (*health.Checker).Register),
 	)
 
 	app.Run()
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	handler "github.com/koeylp/friends-management/cmd/internal/handler/rest"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/middleware"
+	"github.com/koeylp/friends-management/cmd/internal/handler/rest/openapi"
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/koeylp/friends-management/cmd/internal/infra/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRoutesMatchOpenAPI fails when a registered route has no entry in the OpenAPI document,
+// or the document describes a route that is not registered.
+func TestRoutesMatchOpenAPI(t *testing.T) {
+	r := newTestRouter(t)
+
+	var registered []string
+	err := chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		registered = append(registered, method+" "+strings.TrimSuffix(route, "/"))
+		return nil
+	})
+	require.NoError(t, err)
+
+	var documented []string
+	for path, operations := range openapi.Build(openapi.Routes).Paths {
+		for method := range operations {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	sort.Strings(registered)
+	sort.Strings(documented)
+	assert.Equal(t, registered, documented)
+}
+
+// TestRequestValidation checks that the router validates bodies before any handler runs,
+// including on routes registered with a trailing slash.
+func TestRequestValidation(t *testing.T) {
+	r := newTestRouter(t)
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"Unknown field", "/api/v1/friends", `{"friends":["a@example.com","b@example.com"],"extra":true}`, http.StatusBadRequest, "INVALID_PAYLOAD"},
+		{"Trailing slash", "/api/v1/friends/", `{"friends":["a@example.com"]}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"Nested route", "/api/v1/subcription/recipients", `{"sender":"a@example.com"}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"Too large", "/api/v1/users", `{"email":"` + strings.Repeat("a", 64) + `@example.com"}`, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var problem map[string]interface{}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+			assert.Equal(t, tt.expectedCode, problem["code"])
+		})
+	}
+}
+
+// newTestRouter registers every route with handlers that have no dependencies.
+func newTestRouter(t *testing.T) *chi.Mux {
+	docsHandler, err := handler.NewDocsHandler()
+	require.NoError(t, err)
+
+	r := NewRouter()
+	RegisterRoutes(r,
+		handler.NewUserHandler(nil),
+		handler.NewRelationshipHandler(nil),
+		handler.NewGraphHandler(nil),
+		handler.NewWebhookHandler(nil),
+		handler.NewStreamHandler(nil, nil, nil),
+		docsHandler,
+		handler.NewGraphQLHandler(nil),
+		handler.NewMetricsHandler(metrics.New()),
+		handler.NewHealthHandler(nil),
+		middleware.NewRequestMetrics(metrics.New()),
+		middleware.NewRequestLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		middleware.NewRequestValidator(&config.ServerConfig{MaxBodyBytes: 64}),
+	)
+	return r
+}
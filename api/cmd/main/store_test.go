@@ -0,0 +1,20 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/koeylp/friends-management/cmd/internal/infra/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+)
+
+// TestStore tests that the application can be built over each store, and that an unknown
+// store is refused.
+func TestStore(t *testing.T) {
+	for _, backend := range []string{"postgres", "memory"} {
+		assert.NoError(t, fx.ValidateApp(Module, Store(&config.StoreConfig{Backend: backend})), backend)
+	}
+
+	err := fx.ValidateApp(Module, Store(&config.StoreConfig{Backend: "sqlite"}))
+	assert.ErrorContains(t, err, `unknown store backend "sqlite"`)
+}